@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"time"
 
 	"main/src/domain"
 
@@ -19,16 +20,41 @@ type (
 		Analysis bool
 	}
 
+	RunControl struct {
+		TimeBudget time.Duration
+		Progress   bool
+	}
+
 	ConfigFiles struct {
 		ConfigPath   string
 		TopologyPath string
 		TrafficPath  string
+		FaultsPath   string
+		ModesPath    string
 	}
 
 	outputArgs struct {
-		NoConsoleOutput bool
-		OutputFileFlag  bool
-		OutputFilepath  string
+		NoConsoleOutput   bool
+		OutputFileFlag    bool
+		OutputFilepath    string
+		Percentiles       bool
+		Decomposition     bool
+		Interference      bool
+		Fairness          bool
+		HistogramFileFlag bool
+		HistogramFilepath string
+		HopStatsFileFlag  bool
+		HopStatsFilepath  string
+		TimelineFileFlag  bool
+		TimelineFilepath  string
+		NetworkFileFlag   bool
+		NetworkFilepath   string
+		ForensicsFileFlag bool
+		ForensicsFilepath string
+		FaultFileFlag     bool
+		FaultFilepath     string
+		ModeFileFlag      bool
+		ModeFilepath      string
 	}
 )
 
@@ -85,6 +111,33 @@ func AnalysisArgs(app *cli.App) *Analysis {
 	return analysis
 }
 
+func RunControlArgs(app *cli.App) *RunControl {
+	const category = "Run Control"
+
+	rControl := &RunControl{}
+
+	app.Flags = append(
+		app.Flags,
+		&cli.DurationFlag{
+			Name:        "time-budget",
+			Aliases:     []string{"tb"},
+			Usage:       "stop the simulation once `DURATION` of wall-clock time has elapsed (e.g. 90s, 15m), outputting partial results",
+			Destination: &rControl.TimeBudget,
+			Category:    category,
+		},
+		&cli.BoolFlag{
+			Name:        "progress",
+			Aliases:     []string{"prog"},
+			Usage:       "draw a progress line (cycle, cycles per second, ETA & packets delivered) on stderr",
+			Value:       false,
+			Destination: &rControl.Progress,
+			Category:    category,
+		},
+	)
+
+	return rControl
+}
+
 func ConfigFilesArgs(app *cli.App) *ConfigFiles {
 	const category = "Configuration Files"
 
@@ -116,6 +169,20 @@ func ConfigFilesArgs(app *cli.App) *ConfigFiles {
 			Required:    true,
 			Category:    category,
 		},
+		&cli.StringFlag{
+			Name:        "faults",
+			Aliases:     []string{"f"},
+			Usage:       "load fault schedule from `FILE`",
+			Destination: &cConf.FaultsPath,
+			Category:    category,
+		},
+		&cli.StringFlag{
+			Name:        "modes",
+			Aliases:     []string{"m"},
+			Usage:       "load operating modes from `FILE`",
+			Destination: &cConf.ModesPath,
+			Category:    category,
+		},
 	)
 
 	return cConf
@@ -126,6 +193,21 @@ const (
 	overideMaxPriorityFlag = "max_priority"
 	overrideBufferSizeFlag = "buffer_size"
 	processingDelayFlag    = "processing_delay"
+	linkLatencyFlag        = "link_latency"
+	creditLatencyFlag      = "credit_latency"
+	linkBandwidthFlag      = "link_bandwidth"
+	numVCsFlag             = "num_vcs"
+	arbiterFlag            = "arbiter"
+	switchingFlag          = "switching"
+	speedupFlag            = "crossbar_speedup"
+	outputBufferFlag       = "output_buffer_depth"
+	flowControlFlag        = "flow_control"
+	injectionQueueFlag     = "injection_queue_depth"
+	injectionScopeFlag     = "injection_queue_scope"
+	injectionPolicyFlag    = "injection_queue_policy"
+	ejectionRateFlag       = "ejection_rate"
+	serviceTimeFlag        = "ejection_service_time"
+	ejectionBufferFlag     = "ejection_buffer_size"
 )
 
 func ConfigOverridesArgs(app *cli.App) {
@@ -162,6 +244,111 @@ func ConfigOverridesArgs(app *cli.App) {
 			Category:    category,
 			DefaultText: "no-op when unset",
 		},
+		&cli.IntFlag{
+			Name:        linkLatencyFlag,
+			Aliases:     []string{"ll"},
+			Usage:       fmt.Sprintf(usageBaseStr, linkLatencyFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.IntFlag{
+			Name:        creditLatencyFlag,
+			Aliases:     []string{"cl"},
+			Usage:       fmt.Sprintf(usageBaseStr, creditLatencyFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.Float64Flag{
+			Name:        linkBandwidthFlag,
+			Aliases:     []string{"lb"},
+			Usage:       fmt.Sprintf(usageBaseStr, linkBandwidthFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.IntFlag{
+			Name:        numVCsFlag,
+			Aliases:     []string{"nv"},
+			Usage:       fmt.Sprintf(usageBaseStr, numVCsFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.StringFlag{
+			Name:        arbiterFlag,
+			Aliases:     []string{"arb"},
+			Usage:       fmt.Sprintf(usageBaseStr, arbiterFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.StringFlag{
+			Name:        switchingFlag,
+			Aliases:     []string{"sw"},
+			Usage:       fmt.Sprintf(usageBaseStr, switchingFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.IntFlag{
+			Name:        speedupFlag,
+			Aliases:     []string{"xs"},
+			Usage:       fmt.Sprintf(usageBaseStr, speedupFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.IntFlag{
+			Name:        outputBufferFlag,
+			Aliases:     []string{"obd"},
+			Usage:       fmt.Sprintf(usageBaseStr, outputBufferFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.StringFlag{
+			Name:        flowControlFlag,
+			Aliases:     []string{"fc"},
+			Usage:       fmt.Sprintf(usageBaseStr, flowControlFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.IntFlag{
+			Name:        injectionQueueFlag,
+			Aliases:     []string{"iqd"},
+			Usage:       fmt.Sprintf(usageBaseStr, injectionQueueFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.StringFlag{
+			Name:        injectionScopeFlag,
+			Aliases:     []string{"iqs"},
+			Usage:       fmt.Sprintf(usageBaseStr, injectionScopeFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.StringFlag{
+			Name:        injectionPolicyFlag,
+			Aliases:     []string{"iqp"},
+			Usage:       fmt.Sprintf(usageBaseStr, injectionPolicyFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.Float64Flag{
+			Name:        ejectionRateFlag,
+			Aliases:     []string{"er"},
+			Usage:       fmt.Sprintf(usageBaseStr, ejectionRateFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.IntFlag{
+			Name:        serviceTimeFlag,
+			Aliases:     []string{"est"},
+			Usage:       fmt.Sprintf(usageBaseStr, serviceTimeFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
+		&cli.IntFlag{
+			Name:        ejectionBufferFlag,
+			Aliases:     []string{"ebs"},
+			Usage:       fmt.Sprintf(usageBaseStr, ejectionBufferFlag),
+			Category:    category,
+			DefaultText: "no-op when unset",
+		},
 	)
 }
 
@@ -178,10 +365,68 @@ func ApplyConfigOverrides(ctx *cli.Context, conf domain.SimConfig) domain.SimCon
 	if ctx.IsSet(processingDelayFlag) {
 		conf.ProcessingDelay = ctx.Int(processingDelayFlag)
 	}
+	if ctx.IsSet(linkLatencyFlag) {
+		conf.LinkLatency = ctx.Int(linkLatencyFlag)
+	}
+	if ctx.IsSet(creditLatencyFlag) {
+		conf.CreditLatency = ctx.Int(creditLatencyFlag)
+	}
+	if ctx.IsSet(linkBandwidthFlag) {
+		conf.LinkBandwidth = ctx.Float64(linkBandwidthFlag)
+	}
+	if ctx.IsSet(numVCsFlag) {
+		conf.NumVCs = ctx.Int(numVCsFlag)
+	}
+	if ctx.IsSet(arbiterFlag) {
+		conf.Arbiter = domain.ArbitrationPolicy(ctx.String(arbiterFlag))
+	}
+	if ctx.IsSet(switchingFlag) {
+		conf.Switching = domain.SwitchingMode(ctx.String(switchingFlag))
+	}
+	if ctx.IsSet(speedupFlag) {
+		conf.CrossbarSpeedup = ctx.Int(speedupFlag)
+	}
+	if ctx.IsSet(outputBufferFlag) {
+		conf.OutputBufferDepth = ctx.Int(outputBufferFlag)
+	}
+	if ctx.IsSet(flowControlFlag) {
+		conf.FlowControl = domain.FlowControl(ctx.String(flowControlFlag))
+	}
+	if ctx.IsSet(injectionQueueFlag) {
+		conf.InjectionQueueDepth = ctx.Int(injectionQueueFlag)
+	}
+	if ctx.IsSet(injectionScopeFlag) {
+		conf.InjectionQueueScope = domain.InjectionQueueScope(ctx.String(injectionScopeFlag))
+	}
+	if ctx.IsSet(injectionPolicyFlag) {
+		conf.InjectionQueuePolicy = domain.InjectionQueuePolicy(ctx.String(injectionPolicyFlag))
+	}
+	if ctx.IsSet(ejectionRateFlag) {
+		conf.EjectionRate = ctx.Float64(ejectionRateFlag)
+	}
+	if ctx.IsSet(serviceTimeFlag) {
+		conf.EjectionServiceTime = ctx.Int(serviceTimeFlag)
+	}
+	if ctx.IsSet(ejectionBufferFlag) {
+		conf.EjectionBufferSize = ctx.Int(ejectionBufferFlag)
+	}
 	return conf
 }
 
-var outputFileFlag = "results-csv"
+var (
+	outputFileFlag    = "results-csv"
+	percentilesFlag   = "percentiles"
+	decompositionFlag = "latency-decomposition"
+	interferenceFlag  = "interference-sets"
+	fairnessFlag      = "fairness"
+	histogramFileFlag = "latency-histograms"
+	hopStatsFileFlag  = "hop-stats-csv"
+	timelineFileFlag  = "packet-timeline-csv"
+	networkFileFlag   = "network-stats-csv"
+	forensicsFileFlag = "deadline-miss-json"
+	faultFileFlag     = "fault-report-csv"
+	modeFileFlag      = "mode-stats-csv"
+)
 
 func SetupOutputArgs(app *cli.App) {
 	const category = "Output"
@@ -200,6 +445,72 @@ func SetupOutputArgs(app *cli.App) {
 			Usage:    "store output results csv to `FILE`",
 			Category: category,
 		},
+		&cli.BoolFlag{
+			Name:     percentilesFlag,
+			Aliases:  []string{"pct"},
+			Usage:    "include latency percentile columns in the console and csv output",
+			Category: category,
+		},
+		&cli.BoolFlag{
+			Name:     decompositionFlag,
+			Aliases:  []string{"ld"},
+			Usage:    "include latency decomposition (jitter, source queueing, network & serialisation delay) columns in the console and csv output",
+			Category: category,
+		},
+		&cli.BoolFlag{
+			Name:     interferenceFlag,
+			Aliases:  []string{"is"},
+			Usage:    "include analytical and simulation observed interference set columns in the console and csv output",
+			Category: category,
+		},
+		&cli.BoolFlag{
+			Name:     fairnessFlag,
+			Aliases:  []string{"fair"},
+			Usage:    "include per traffic flow throughput, delivery ratio & slowdown columns and Jain's fairness indices in the console and csv output",
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     histogramFileFlag,
+			Aliases:  []string{"hist"},
+			Usage:    "store per traffic flow latency histograms to `FILE` (json if the extension is .json, otherwise csv)",
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     hopStatsFileFlag,
+			Aliases:  []string{"hops"},
+			Usage:    "store per traffic flow, per hop, header and tail flit waiting times csv to `FILE`",
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     timelineFileFlag,
+			Aliases:  []string{"timeline"},
+			Usage:    "store every arrived packet's per hop timeline csv to `FILE`",
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     networkFileFlag,
+			Aliases:  []string{"net"},
+			Usage:    "store link utilisation and router buffer occupancy statistics csv to `FILE`",
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     forensicsFileFlag,
+			Aliases:  []string{"dmj"},
+			Usage:    "store a forensic record of every packet which exceeded its deadline, including the flits which occupied the links it waited for, json to `FILE` (logs every link traversal, increasing memory use)",
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     faultFileFlag,
+			Aliases:  []string{"frep"},
+			Usage:    "store every packet affected by injected faults, with its status and extra latency, csv to `FILE`",
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     modeFileFlag,
+			Aliases:  []string{"mstats"},
+			Usage:    "store per mode and per mode change straddling packet statistics csv to `FILE`",
+			Category: category,
+		},
 	)
 }
 
@@ -213,5 +524,45 @@ func OutputArgs(ctx *cli.Context) outputArgs {
 		oArgs.OutputFilepath = ctx.String(outputFileFlag)
 	}
 
+	oArgs.Percentiles = ctx.Bool(percentilesFlag)
+	oArgs.Decomposition = ctx.Bool(decompositionFlag)
+	oArgs.Interference = ctx.Bool(interferenceFlag)
+	oArgs.Fairness = ctx.Bool(fairnessFlag)
+
+	if ctx.IsSet(histogramFileFlag) {
+		oArgs.HistogramFileFlag = true
+		oArgs.HistogramFilepath = ctx.String(histogramFileFlag)
+	}
+
+	if ctx.IsSet(hopStatsFileFlag) {
+		oArgs.HopStatsFileFlag = true
+		oArgs.HopStatsFilepath = ctx.String(hopStatsFileFlag)
+	}
+
+	if ctx.IsSet(timelineFileFlag) {
+		oArgs.TimelineFileFlag = true
+		oArgs.TimelineFilepath = ctx.String(timelineFileFlag)
+	}
+
+	if ctx.IsSet(networkFileFlag) {
+		oArgs.NetworkFileFlag = true
+		oArgs.NetworkFilepath = ctx.String(networkFileFlag)
+	}
+
+	if ctx.IsSet(forensicsFileFlag) {
+		oArgs.ForensicsFileFlag = true
+		oArgs.ForensicsFilepath = ctx.String(forensicsFileFlag)
+	}
+
+	if ctx.IsSet(faultFileFlag) {
+		oArgs.FaultFileFlag = true
+		oArgs.FaultFilepath = ctx.String(faultFileFlag)
+	}
+
+	if ctx.IsSet(modeFileFlag) {
+		oArgs.ModeFileFlag = true
+		oArgs.ModeFilepath = ctx.String(modeFileFlag)
+	}
+
 	return oArgs
 }
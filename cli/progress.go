@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"main/src/core/simulation"
+	"main/src/domain"
+)
+
+// Returns a progress function which redraws a single progress line on w, ending the line once the simulation is done.
+func progressLine(w io.Writer) simulation.ProgressFunc {
+	lastLen := 0
+
+	return func(p domain.SimProgress) {
+		line := formatProgress(p)
+
+		// Pad with spaces to clear any remainder of a longer previous line.
+		padding := lastLen - len(line)
+		if padding < 0 {
+			padding = 0
+		}
+		lastLen = len(line)
+
+		fmt.Fprintf(w, "\r%s%*s", line, padding, "")
+		if p.Done {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+func formatProgress(p domain.SimProgress) string {
+	var percent, rate float64
+	if p.CycleLimit > 0 {
+		percent = float64(p.Cycle) / float64(p.CycleLimit) * 100
+	}
+	if p.Elapsed > 0 {
+		rate = float64(p.Cycle) / p.Elapsed.Seconds()
+	}
+
+	eta := "-"
+	if p.Done && p.Cycle < p.CycleLimit {
+		eta = "stopped"
+	} else if p.Done {
+		eta = "done"
+	} else if rate > 0 {
+		eta = (time.Duration(float64(p.CycleLimit-p.Cycle)/rate) * time.Second).Round(time.Second).String()
+	}
+
+	return fmt.Sprintf(
+		"cycle %d/%d (%.1f%%) | %.0f cycles/s | ETA %s | %d packets delivered",
+		p.Cycle, p.CycleLimit, percent, rate, eta, p.PacketsArrived,
+	)
+}
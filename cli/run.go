@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"main/log"
 	"main/src/config"
 	"main/src/core"
 	"main/src/core/results"
+	"main/src/faults"
 	"main/src/topology"
 	"main/src/traffic"
 
@@ -15,6 +20,9 @@ import (
 
 const (
 	appName = "CyNoC"
+
+	// Conventional exit code of a process terminated by SIGINT.
+	interruptedExitCode = 130
 )
 
 func NewApp() *cli.App {
@@ -22,6 +30,7 @@ func NewApp() *cli.App {
 
 	logArgs := LogArgs(app)
 	analysisArgs := AnalysisArgs(app)
+	runControl := RunControlArgs(app)
 	confArgs := ConfigFilesArgs(app)
 	ConfigOverridesArgs(app)
 	SetupOutputArgs(app)
@@ -36,6 +45,9 @@ func NewApp() *cli.App {
 			log.Log.Fatal().Err(err).Msg("error reading config file")
 		}
 		conf = ApplyConfigOverrides(cliCtx, conf)
+		if err := config.Validate(conf); err != nil {
+			log.Log.Fatal().Err(err).Msg("invalid config after applying overrides")
+		}
 
 		top, err := topology.ReadTopology(confArgs.TopologyPath)
 		if err != nil {
@@ -47,15 +59,65 @@ func NewApp() *cli.App {
 			log.Log.Fatal().Err(err).Msg("error reading traffic flows file")
 		}
 
-		resultsSet, err := core.Run(conf, top, trafficFlowConfigs, analysisArgs.Analysis, log.Log)
+		opts := core.Options{
+			Analysis:         analysisArgs.Analysis,
+			LinkTraversalLog: OutputArgs(cliCtx).ForensicsFileFlag,
+		}
+
+		if runControl.Progress {
+			opts.Progress = progressLine(os.Stderr)
+		}
+
+		if confArgs.FaultsPath != "" {
+			opts.Faults, err = faults.LoadFaultSchedule(confArgs.FaultsPath)
+			if err != nil {
+				log.Log.Fatal().Err(err).Msg("error reading fault schedule file")
+			}
+		}
+
+		if confArgs.ModesPath != "" {
+			opts.Modes, err = traffic.LoadModeConfig(confArgs.ModesPath, trafficFlowConfigs)
+			if err != nil {
+				log.Log.Fatal().Err(err).Msg("error reading modes file")
+			}
+		}
+
+		// The first interrupt stops the simulation at the end of the current cycle and outputs partial results, the
+		// signal handler is then released so a second interrupt terminates immediately.
+		signalCtx, stop := signal.NotifyContext(cliCtx.Context, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-signalCtx.Done()
+			stop()
+		}()
+
+		ctx := signalCtx
+		if runControl.TimeBudget > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(signalCtx, runControl.TimeBudget)
+			defer cancel()
+		}
+
+		resultsSet, err := core.Run(ctx, conf, top, trafficFlowConfigs, opts, log.Log)
 		if err != nil {
 			log.Log.Fatal().Err(err).Msg("error running simulation")
 		}
 
-		if err := output(cliCtx, resultsSet); err != nil {
+		if err := output(cliCtx, resultsSet, len(opts.Faults) > 0, !conf.Preemptive(), conf.InjectionQueueDepth > 0); err != nil {
 			log.Log.Fatal().Err(err).Msg("error outputting results")
 		}
 
+		if resultsSet.AnalysisInterrupted() {
+			log.Log.Warn().Dur("time_budget", runControl.TimeBudget).Msg("analysis interrupted, results exclude analysis")
+		}
+
+		if resultsSet.Truncated() {
+			if signalCtx.Err() != nil {
+				return cli.Exit("simulation interrupted, results are partial", interruptedExitCode)
+			}
+			log.Log.Warn().Dur("time_budget", runControl.TimeBudget).Msg("time budget reached, results are partial")
+		}
+
 		return nil
 	}
 
@@ -76,18 +138,89 @@ func initLogger(logConf *LogConfig) {
 	log.InitLogger(logLevel)
 }
 
-func output(cliCtx *cli.Context, results results.Results) error {
+func output(cliCtx *cli.Context, resultsSet results.Results, faultsInjected, nonPreemptive, boundedInjection bool) error {
 	outputArgs := OutputArgs(cliCtx)
 
+	if outputArgs.Percentiles {
+		resultsSet.EnableColumnGroup(results.PercentileColumns)
+	}
+	if outputArgs.Decomposition {
+		resultsSet.EnableColumnGroup(results.DecompositionColumns)
+	}
+	if outputArgs.Interference {
+		resultsSet.EnableColumnGroup(results.InterferenceColumns)
+	}
+	if outputArgs.Fairness {
+		resultsSet.EnableColumnGroup(results.FairnessColumns)
+	}
+	if faultsInjected {
+		resultsSet.EnableColumnGroup(results.FaultColumns)
+	}
+	if nonPreemptive {
+		resultsSet.EnableColumnGroup(results.BlockingColumns)
+	}
+	if boundedInjection {
+		resultsSet.EnableColumnGroup(results.InjectionColumns)
+	}
+
 	if outputArgs.OutputFileFlag {
-		if err := results.OutputCSV(outputArgs.OutputFilepath); err != nil {
+		if err := resultsSet.OutputCSV(outputArgs.OutputFilepath); err != nil {
 			log.Log.Error().Err(err).Msgf("error writing traffic flow results to %s", outputArgs.OutputFilepath)
 			return err
 		}
 	}
 
+	if outputArgs.HistogramFileFlag {
+		if err := resultsSet.OutputHistograms(outputArgs.HistogramFilepath); err != nil {
+			log.Log.Error().Err(err).Msgf("error writing latency histograms to %s", outputArgs.HistogramFilepath)
+			return err
+		}
+	}
+
+	if outputArgs.HopStatsFileFlag {
+		if err := resultsSet.OutputHopStats(outputArgs.HopStatsFilepath); err != nil {
+			log.Log.Error().Err(err).Msgf("error writing per hop statistics to %s", outputArgs.HopStatsFilepath)
+			return err
+		}
+	}
+
+	if outputArgs.TimelineFileFlag {
+		if err := resultsSet.OutputTimelines(outputArgs.TimelineFilepath); err != nil {
+			log.Log.Error().Err(err).Msgf("error writing packet timelines to %s", outputArgs.TimelineFilepath)
+			return err
+		}
+	}
+
+	if outputArgs.NetworkFileFlag {
+		if err := resultsSet.OutputNetworkStats(outputArgs.NetworkFilepath); err != nil {
+			log.Log.Error().Err(err).Msgf("error writing network statistics to %s", outputArgs.NetworkFilepath)
+			return err
+		}
+	}
+
+	if outputArgs.ForensicsFileFlag {
+		if err := resultsSet.OutputDeadlineMisses(outputArgs.ForensicsFilepath); err != nil {
+			log.Log.Error().Err(err).Msgf("error writing deadline miss forensics to %s", outputArgs.ForensicsFilepath)
+			return err
+		}
+	}
+
+	if outputArgs.FaultFileFlag {
+		if err := resultsSet.OutputFaultReport(outputArgs.FaultFilepath); err != nil {
+			log.Log.Error().Err(err).Msgf("error writing fault report to %s", outputArgs.FaultFilepath)
+			return err
+		}
+	}
+
+	if outputArgs.ModeFileFlag {
+		if err := resultsSet.OutputModeStats(outputArgs.ModeFilepath); err != nil {
+			log.Log.Error().Err(err).Msgf("error writing mode statistics to %s", outputArgs.ModeFilepath)
+			return err
+		}
+	}
+
 	if !outputArgs.NoConsoleOutput {
-		str, err := results.Prettify()
+		str, err := resultsSet.Prettify()
 		if err != nil {
 			log.Log.Error().Err(err).Msg("error prettifying results")
 			return err
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"main/log"
 	"main/src/domain"
@@ -18,6 +19,23 @@ var (
 	ErrInvalidMaxPriority     = errors.New("invalid max priority")
 	ErrInvalidBufferSize      = errors.New("invalid buffer size")
 	ErrInvalidProcessingDelay = errors.New("invalid processing delay")
+	ErrInvalidLinkLatency     = errors.New("invalid link latency")
+	ErrInvalidCreditLatency   = errors.New("invalid credit latency")
+	ErrInvalidLinkBandwidth   = errors.New("invalid link bandwidth")
+	ErrInvalidVCDepths        = errors.New("invalid virtual channel depths")
+	ErrInvalidNumVCs          = errors.New("invalid number of virtual channels")
+	ErrInvalidVCMap           = errors.New("invalid priority to virtual channel map")
+	ErrInvalidArbiter         = errors.New("invalid arbitration policy")
+	ErrInvalidArbiterWeights  = errors.New("invalid arbitration weights")
+	ErrInvalidSwitching       = errors.New("invalid switching mode")
+	ErrInvalidPipeline        = errors.New("invalid router pipeline")
+	ErrInvalidSpeedup         = errors.New("invalid crossbar speedup")
+	ErrInvalidOutputBuffer    = errors.New("invalid output buffer depth")
+	ErrInvalidFlowControl     = errors.New("invalid flow control")
+	ErrInvalidOnOffThresholds = errors.New("invalid on/off flow control thresholds")
+	ErrInvalidWindow          = errors.New("invalid retransmission window")
+	ErrInvalidInjectionQueue  = errors.New("invalid injection queue")
+	ErrInvalidEjection        = errors.New("invalid network interface ejection")
 )
 
 func ReadConfig(fPath string) (domain.SimConfig, error) {
@@ -41,10 +59,22 @@ func ReadConfig(fPath string) (domain.SimConfig, error) {
 		return domain.SimConfig{}, err
 	}
 
+	// The buffer size defaults to the total of the virtual channel depths.
+	if len(config.VCDepths) > 0 && config.BufferSize == 0 {
+		for _, depth := range config.VCDepths {
+			config.BufferSize += depth
+		}
+	}
+
 	log.Log.Info().Msg("loaded config from file")
 	return config, validate(config)
 }
 
+// Validates a configuration changed after it was read, e.g. by command line overrides.
+func Validate(conf domain.SimConfig) error {
+	return validate(conf)
+}
+
 func validate(conf domain.SimConfig) error {
 	if conf.CycleLimit < 1 {
 		err := errors.Join(ErrInvalidConfig, ErrInvalidCycleLimit)
@@ -58,15 +88,31 @@ func validate(conf domain.SimConfig) error {
 		return err
 	}
 
+	if conf.NumVCs < 0 || conf.NumVCs > conf.MaxPriority {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidNumVCs)
+		log.Log.Error().Err(err).Int("num_vcs", conf.NumVCs).Int("max_priority", conf.MaxPriority).Msg("number of virtual channels must be between 1 and max priority")
+		return err
+	}
+
+	if len(conf.VCMap) > 0 {
+		if err := validateVCMap(conf); err != nil {
+			return err
+		}
+	}
+
 	if conf.BufferSize < 1 {
 		err := errors.Join(ErrInvalidConfig, ErrInvalidBufferSize)
 		log.Log.Error().Err(err).Int("buffer_size", conf.BufferSize).Msg("buffer size must be greater than 0")
 		return err
 	}
 
-	if conf.BufferSize%conf.MaxPriority != 0 {
+	if len(conf.VCDepths) > 0 {
+		if err := validateVCDepths(conf); err != nil {
+			return err
+		}
+	} else if conf.BufferSize%conf.VCCount() != 0 {
 		err := errors.Join(ErrInvalidConfig, ErrInvalidBufferSize)
-		log.Log.Error().Err(err).Int("buffer_size", conf.BufferSize).Int("max_priority", conf.MaxPriority).Msg("buffer size must be a multiple of max priority")
+		log.Log.Error().Err(err).Int("buffer_size", conf.BufferSize).Int("num_vcs", conf.VCCount()).Msg("buffer size must be a multiple of the number of virtual channels")
 		return err
 	}
 
@@ -76,12 +122,229 @@ func validate(conf domain.SimConfig) error {
 		return err
 	}
 
-	if conf.ProcessingDelay < 1 {
+	if conf.Pipeline.Enabled() {
+		if err := validatePipeline(conf); err != nil {
+			return err
+		}
+	} else if conf.ProcessingDelay < 1 {
 		err := errors.Join(ErrInvalidConfig, ErrInvalidProcessingDelay)
 		log.Log.Error().Err(err).Int("processing_delay", conf.ProcessingDelay).Msg("processing delay must be greater than 0")
 		return err
 	}
 
+	if conf.LinkLatency < 0 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidLinkLatency)
+		log.Log.Error().Err(err).Int("link_latency", conf.LinkLatency).Msg("link latency must not be negative")
+		return err
+	}
+
+	if conf.CreditLatency < 0 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidCreditLatency)
+		log.Log.Error().Err(err).Int("credit_latency", conf.CreditLatency).Msg("credit latency must not be negative")
+		return err
+	}
+
+	if conf.LinkBandwidth != 0 && !domain.ValidBandwidth(conf.LinkBandwidth) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidLinkBandwidth)
+		log.Log.Error().Err(err).Float64("link_bandwidth", conf.LinkBandwidth).Msg("link bandwidth must be a whole number of flits per cycle or of cycles per flit")
+		return err
+	}
+
+	if conf.Arbiter != "" && !slices.Contains(domain.ArbitrationPolicies(), conf.Arbiter) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidArbiter)
+		log.Log.Error().Err(err).Str("arbiter", string(conf.Arbiter)).Any("valid", domain.ArbitrationPolicies()).Msg("unknown arbitration policy")
+		return err
+	}
+
+	if len(conf.ArbiterWeights) > 0 {
+		if err := validateArbiterWeights(conf); err != nil {
+			return err
+		}
+	}
+
+	if conf.OutputBufferDepth < 0 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidOutputBuffer)
+		log.Log.Error().Err(err).Int("output_buffer_depth", conf.OutputBufferDepth).Msg("output buffer depth must not be negative")
+		return err
+	}
+
+	if conf.CrossbarSpeedup < 0 || (conf.CrossbarSpeedup > 1 && conf.OutputBufferDepth == 0) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidSpeedup)
+		log.Log.Error().Err(err).Int("crossbar_speedup", conf.CrossbarSpeedup).Int("output_buffer_depth", conf.OutputBufferDepth).Msg("crossbar speedup must not be negative, and output_buffer_depth must be at least 1 when speedup > 1")
+		return err
+	}
+
+	if conf.Switching != "" && !slices.Contains(domain.SwitchingModes(), conf.Switching) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidSwitching)
+		log.Log.Error().Err(err).Str("switching", string(conf.Switching)).Any("valid", domain.SwitchingModes()).Msg("unknown switching mode")
+		return err
+	}
+
+	if conf.FlowControl != "" {
+		if err := validateFlowControl(conf); err != nil {
+			return err
+		}
+	}
+
+	if err := validateInjectionQueue(conf); err != nil {
+		return err
+	}
+
+	if err := validateEjection(conf); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateInjectionQueue(conf domain.SimConfig) error {
+	if conf.InjectionQueueDepth < 0 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidInjectionQueue)
+		log.Log.Error().Err(err).Int("injection_queue_depth", conf.InjectionQueueDepth).Msg("injection queue depth must not be negative")
+		return err
+	}
+
+	if conf.InjectionQueueScope != "" && !slices.Contains(domain.InjectionQueueScopes(), conf.InjectionQueueScope) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidInjectionQueue)
+		log.Log.Error().Err(err).Str("injection_queue_scope", string(conf.InjectionQueueScope)).Any("valid", domain.InjectionQueueScopes()).Msg("unknown injection queue scope")
+		return err
+	}
+
+	if conf.InjectionQueuePolicy != "" && !slices.Contains(domain.InjectionQueuePolicies(), conf.InjectionQueuePolicy) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidInjectionQueue)
+		log.Log.Error().Err(err).Str("injection_queue_policy", string(conf.InjectionQueuePolicy)).Any("valid", domain.InjectionQueuePolicies()).Msg("unknown injection queue policy")
+		return err
+	}
+
+	return nil
+}
+
+func validateFlowControl(conf domain.SimConfig) error {
+	if !slices.Contains(domain.FlowControls(), conf.FlowControl) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidFlowControl)
+		log.Log.Error().Err(err).Str("flow_control", string(conf.FlowControl)).Any("valid", domain.FlowControls()).Msg("unknown flow control scheme")
+		return err
+	}
+
+	if conf.FlowControl != domain.CreditFlowControl && conf.WholePacketSwitching() {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidFlowControl)
+		log.Log.Error().Err(err).Str("flow_control", string(conf.FlowControl)).Str("switching", string(conf.Switching)).Msg("virtual cut-through & store-and-forward switching require credit based flow control")
+		return err
+	}
+
+	if conf.OffThreshold < 0 || conf.OnThreshold < 0 || (conf.OnThreshold != 0 && conf.OnThreshold <= conf.OffThreshold) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidOnOffThresholds)
+		log.Log.Error().Err(err).Int("off_threshold", conf.OffThreshold).Int("on_threshold", conf.OnThreshold).Msg("on/off thresholds must not be negative and the on threshold must be greater than the off threshold")
+		return err
+	}
+
+	if conf.RetransmissionWindow < 0 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidWindow)
+		log.Log.Error().Err(err).Int("retransmission_window", conf.RetransmissionWindow).Msg("retransmission window must not be negative")
+		return err
+	}
+
+	return nil
+}
+
+func validateEjection(conf domain.SimConfig) error {
+	if conf.EjectionRate != 0 && !domain.ValidBandwidth(conf.EjectionRate) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidEjection)
+		log.Log.Error().Err(err).Float64("ejection_rate", conf.EjectionRate).Msg("ejection rate must be a whole number of flits per cycle or of cycles per flit")
+		return err
+	}
+
+	if conf.EjectionServiceTime < 0 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidEjection)
+		log.Log.Error().Err(err).Int("ejection_service_time", conf.EjectionServiceTime).Msg("ejection service time must not be negative")
+		return err
+	}
+
+	if conf.EjectionBufferSize < 0 || (conf.EjectionBufferSize > 0 && conf.VChanDepths(conf.EjectionBufferSize) == nil) {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidEjection)
+		log.Log.Error().Err(err).Int("ejection_buffer_size", conf.EjectionBufferSize).Int("num_vcs", conf.VCCount()).Ints("vc_depths", conf.VCDepths).Msg("ejection buffer size must not be negative and must be a multiple of the number of virtual channels, or total vc_depths when set")
+		return err
+	}
+
+	return nil
+}
+
+func validatePipeline(conf domain.SimConfig) error {
+	stages := conf.Pipeline
+	if stages.RoutingComputation < 0 || stages.VCAllocation < 0 || stages.SwitchAllocation < 0 || stages.SwitchTraversal < 0 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidPipeline)
+		log.Log.Error().Err(err).Any("pipeline", stages).Msg("router pipeline stage latencies must not be negative")
+		return err
+	}
+
+	if latency, _ := conf.PipelineDelay(true); latency < 1 {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidPipeline)
+		log.Log.Error().Err(err).Any("pipeline", stages).Msg("router pipeline must take header flits at least 1 cycle")
+		return err
+	}
+
+	return nil
+}
+
+func validateArbiterWeights(conf domain.SimConfig) error {
+	if len(conf.ArbiterWeights) != conf.MaxPriority {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidArbiterWeights)
+		log.Log.Error().Err(err).Ints("arbiter_weights", conf.ArbiterWeights).Int("max_priority", conf.MaxPriority).Msg("an arbitration weight must be given for every priority")
+		return err
+	}
+
+	for _, weight := range conf.ArbiterWeights {
+		if weight < 1 {
+			err := errors.Join(ErrInvalidConfig, ErrInvalidArbiterWeights)
+			log.Log.Error().Err(err).Ints("arbiter_weights", conf.ArbiterWeights).Msg("arbitration weights must be greater than 0")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateVCMap(conf domain.SimConfig) error {
+	if len(conf.VCMap) != conf.MaxPriority {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidVCMap)
+		log.Log.Error().Err(err).Ints("vc_map", conf.VCMap).Int("max_priority", conf.MaxPriority).Msg("a virtual channel must be given for every priority")
+		return err
+	}
+
+	for _, vChan := range conf.VCMap {
+		if vChan < 1 || vChan > conf.VCCount() {
+			err := errors.Join(ErrInvalidConfig, ErrInvalidVCMap)
+			log.Log.Error().Err(err).Ints("vc_map", conf.VCMap).Int("num_vcs", conf.VCCount()).Msg("virtual channels must be between 1 and the number of virtual channels")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateVCDepths(conf domain.SimConfig) error {
+	if len(conf.VCDepths) != conf.VCCount() {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidVCDepths)
+		log.Log.Error().Err(err).Ints("vc_depths", conf.VCDepths).Int("num_vcs", conf.VCCount()).Msg("a depth must be given for every virtual channel")
+		return err
+	}
+
+	total := 0
+	for _, depth := range conf.VCDepths {
+		if depth < 1 {
+			err := errors.Join(ErrInvalidConfig, ErrInvalidVCDepths)
+			log.Log.Error().Err(err).Ints("vc_depths", conf.VCDepths).Msg("virtual channel depths must be greater than 0")
+			return err
+		}
+		total += depth
+	}
+
+	if total != conf.BufferSize {
+		err := errors.Join(ErrInvalidConfig, ErrInvalidVCDepths)
+		log.Log.Error().Err(err).Ints("vc_depths", conf.VCDepths).Int("buffer_size", conf.BufferSize).Msg("virtual channel depths must total the buffer size")
+		return err
+	}
+
 	return nil
 }
 
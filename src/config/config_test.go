@@ -90,6 +90,463 @@ func TestReadConfig(t *testing.T) {
 				"processing_delay": 0,
 			},
 		},
+		{
+			name:     "invalid_link_latency_negative",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidLinkLatency,
+			overrides: map[string]any{
+				"link_latency": -1,
+			},
+		},
+		{
+			name:     "invalid_credit_latency_negative",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidCreditLatency,
+			overrides: map[string]any{
+				"credit_latency": -2,
+			},
+		},
+		{
+			name:     "invalid_link_bandwidth_fractional",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidLinkBandwidth,
+			overrides: map[string]any{
+				"link_bandwidth": 0.4,
+			},
+		},
+		{
+			name:     "invalid_link_bandwidth_negative",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidLinkBandwidth,
+			overrides: map[string]any{
+				"link_bandwidth": -1,
+			},
+		},
+		{
+			name:     "valid_vc_depths",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"buffer_size": 0,
+				"vc_depths":   []int{8, 4, 4, 4, 2, 1},
+			},
+			expected: domain.SimConfig{
+				CycleLimit:      1000,
+				MaxPriority:     6,
+				BufferSize:      23,
+				ProcessingDelay: 1,
+				VCDepths:        []int{8, 4, 4, 4, 2, 1},
+			},
+		},
+		{
+			name:     "invalid_vc_depths_count",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidVCDepths,
+			overrides: map[string]any{
+				"buffer_size": 0,
+				"vc_depths":   []int{8, 4, 2},
+			},
+		},
+		{
+			name:     "invalid_vc_depths_zero",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidVCDepths,
+			overrides: map[string]any{
+				"buffer_size": 0,
+				"vc_depths":   []int{8, 4, 4, 4, 4, 0},
+			},
+		},
+		{
+			name:     "valid_num_vcs",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"num_vcs": 2,
+				"vc_map":  []int{1, 1, 2, 2, 2, 2},
+			},
+			expected: domain.SimConfig{
+				CycleLimit:      1000,
+				MaxPriority:     6,
+				BufferSize:      24,
+				ProcessingDelay: 1,
+				NumVCs:          2,
+				VCMap:           []int{1, 1, 2, 2, 2, 2},
+			},
+		},
+		{
+			name:     "valid_arbiter",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"arbiter":         "weighted_round_robin",
+				"arbiter_weights": []int{6, 5, 4, 3, 2, 1},
+			},
+			expected: domain.SimConfig{
+				CycleLimit:      1000,
+				MaxPriority:     6,
+				BufferSize:      24,
+				ProcessingDelay: 1,
+				Arbiter:         domain.WeightedRoundRobinArbitration,
+				ArbiterWeights:  []int{6, 5, 4, 3, 2, 1},
+			},
+		},
+		{
+			name:     "valid_switching",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"switching": "non_preemptive_wormhole",
+			},
+			expected: domain.SimConfig{
+				CycleLimit:      1000,
+				MaxPriority:     6,
+				BufferSize:      24,
+				ProcessingDelay: 1,
+				Switching:       domain.NonPreemptiveWormholeSwitching,
+			},
+		},
+		{
+			name:     "valid_switching_vct",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"switching": "vct",
+			},
+			expected: domain.SimConfig{
+				CycleLimit:      1000,
+				MaxPriority:     6,
+				BufferSize:      24,
+				ProcessingDelay: 1,
+				Switching:       domain.VirtualCutThroughSwitching,
+			},
+		},
+		{
+			name:     "valid_pipeline",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"processing_delay": 0,
+				"pipeline":         map[string]any{"rc": 1, "va": 1, "sa": 1, "st": 1, "lookahead": true},
+			},
+			expected: domain.SimConfig{
+				CycleLimit:  1000,
+				MaxPriority: 6,
+				BufferSize:  24,
+				Pipeline: domain.RouterPipeline{
+					RoutingComputation: 1,
+					VCAllocation:       1,
+					SwitchAllocation:   1,
+					SwitchTraversal:    1,
+					Lookahead:          true,
+				},
+			},
+		},
+		{
+			name:     "invalid_pipeline_negative_stage",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidPipeline,
+			overrides: map[string]any{
+				"pipeline": map[string]any{"rc": 1, "va": -1},
+			},
+		},
+		{
+			name:     "invalid_pipeline_lookahead_only_stage",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidPipeline,
+			overrides: map[string]any{
+				"pipeline": map[string]any{"rc": 1, "lookahead": true},
+			},
+		},
+		{
+			name:     "valid_output_buffers",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"crossbar_speedup":    2,
+				"output_buffer_depth": 4,
+			},
+			expected: domain.SimConfig{
+				CycleLimit:        1000,
+				MaxPriority:       6,
+				BufferSize:        24,
+				ProcessingDelay:   1,
+				CrossbarSpeedup:   2,
+				OutputBufferDepth: 4,
+			},
+		},
+		{
+			name:     "invalid_speedup_without_output_buffers",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidSpeedup,
+			overrides: map[string]any{
+				"crossbar_speedup": 2,
+			},
+		},
+		{
+			name:     "invalid_output_buffer_depth_negative",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidOutputBuffer,
+			overrides: map[string]any{
+				"output_buffer_depth": -1,
+			},
+		},
+		{
+			name:     "valid_flow_control_on_off",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"flow_control":  "on_off",
+				"off_threshold": 2,
+				"on_threshold":  4,
+			},
+			expected: domain.SimConfig{
+				CycleLimit:      1000,
+				MaxPriority:     6,
+				BufferSize:      24,
+				ProcessingDelay: 1,
+				FlowControl:     domain.OnOffFlowControl,
+				OffThreshold:    2,
+				OnThreshold:     4,
+			},
+		},
+		{
+			name:     "invalid_flow_control",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidFlowControl,
+			overrides: map[string]any{
+				"flow_control": "stop_go",
+			},
+		},
+		{
+			name:     "invalid_flow_control_vct",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidFlowControl,
+			overrides: map[string]any{
+				"switching":    "vct",
+				"flow_control": "on_off",
+			},
+		},
+		{
+			name:     "invalid_on_off_thresholds",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidOnOffThresholds,
+			overrides: map[string]any{
+				"flow_control":  "on_off",
+				"off_threshold": 3,
+				"on_threshold":  3,
+			},
+		},
+		{
+			name:     "invalid_retransmission_window",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidWindow,
+			overrides: map[string]any{
+				"flow_control":          "ack_nack",
+				"retransmission_window": -1,
+			},
+		},
+		{
+			name:     "valid_injection_queue",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"injection_queue_depth":  8,
+				"injection_queue_scope":  "priority",
+				"injection_queue_policy": "drop_oldest",
+			},
+			expected: domain.SimConfig{
+				CycleLimit:           1000,
+				MaxPriority:          6,
+				BufferSize:           24,
+				ProcessingDelay:      1,
+				InjectionQueueDepth:  8,
+				InjectionQueueScope:  domain.PriorityInjectionQueueScope,
+				InjectionQueuePolicy: domain.DropOldestInjectionQueuePolicy,
+			},
+		},
+		{
+			name:     "invalid_injection_queue_depth",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidInjectionQueue,
+			overrides: map[string]any{
+				"injection_queue_depth": -1,
+			},
+		},
+		{
+			name:     "invalid_injection_queue_scope",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidInjectionQueue,
+			overrides: map[string]any{
+				"injection_queue_depth": 8,
+				"injection_queue_scope": "flow",
+			},
+		},
+		{
+			name:     "invalid_injection_queue_policy",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidInjectionQueue,
+			overrides: map[string]any{
+				"injection_queue_depth":  8,
+				"injection_queue_policy": "drop_all",
+			},
+		},
+		{
+			name:     "valid_ejection",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			overrides: map[string]any{
+				"ejection_rate":         0.5,
+				"ejection_service_time": 10,
+				"ejection_buffer_size":  12,
+			},
+			expected: domain.SimConfig{
+				CycleLimit:          1000,
+				MaxPriority:         6,
+				BufferSize:          24,
+				ProcessingDelay:     1,
+				EjectionRate:        0.5,
+				EjectionServiceTime: 10,
+				EjectionBufferSize:  12,
+			},
+		},
+		{
+			name:     "invalid_ejection_rate",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidEjection,
+			overrides: map[string]any{
+				"ejection_rate": 0.4,
+			},
+		},
+		{
+			name:     "invalid_ejection_service_time",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidEjection,
+			overrides: map[string]any{
+				"ejection_service_time": -1,
+			},
+		},
+		{
+			name:     "invalid_ejection_buffer_size",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidEjection,
+			overrides: map[string]any{
+				"ejection_buffer_size": 10,
+			},
+		},
+		{
+			name:     "invalid_switching",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidSwitching,
+			overrides: map[string]any{
+				"switching": "circuit",
+			},
+		},
+		{
+			name:     "invalid_arbiter",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidArbiter,
+			overrides: map[string]any{
+				"arbiter": "lottery",
+			},
+		},
+		{
+			name:     "invalid_arbiter_weights_count",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidArbiterWeights,
+			overrides: map[string]any{
+				"arbiter_weights": []int{2, 1},
+			},
+		},
+		{
+			name:     "invalid_arbiter_weights_zero",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidArbiterWeights,
+			overrides: map[string]any{
+				"arbiter_weights": []int{6, 5, 4, 3, 2, 0},
+			},
+		},
+		{
+			name:     "invalid_num_vcs_exceeds_max_priority",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidNumVCs,
+			overrides: map[string]any{
+				"num_vcs": 7,
+			},
+		},
+		{
+			name:     "invalid_buffer_size_not_multiple_num_vcs",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidBufferSize,
+			overrides: map[string]any{
+				"num_vcs": 5,
+			},
+		},
+		{
+			name:     "invalid_vc_map_count",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidVCMap,
+			overrides: map[string]any{
+				"num_vcs": 2,
+				"vc_map":  []int{1, 2},
+			},
+		},
+		{
+			name:     "invalid_vc_map_out_of_range",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidVCMap,
+			overrides: map[string]any{
+				"num_vcs": 2,
+				"vc_map":  []int{1, 1, 2, 2, 3, 3},
+			},
+		},
+		{
+			name:     "invalid_vc_depths_per_vc",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidVCDepths,
+			overrides: map[string]any{
+				"buffer_size": 0,
+				"num_vcs":     2,
+				"vc_depths":   []int{8, 4, 4},
+			},
+		},
+		{
+			name:     "invalid_vc_depths_total",
+			baseFile: "valid_basic.yaml",
+			enabled:  true,
+			err:      ErrInvalidVCDepths,
+			overrides: map[string]any{
+				"vc_depths": []int{8, 4, 4, 4, 2, 1},
+			},
+		},
 	}
 
 	tmpDir := t.TempDir()
@@ -199,3 +656,29 @@ func yamlFileToJsonFile(tb testing.TB, tmpDir string, yPath string) string {
 
 	return jPath
 }
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	conf, err := ReadConfig(path.Join(testResourcesDir, "valid_basic.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, Validate(conf))
+
+	t.Run("InvalidSwitching", func(t *testing.T) {
+		c := conf
+		c.Switching = "bogus"
+		require.ErrorIs(t, Validate(c), ErrInvalidConfig)
+	})
+
+	t.Run("InvalidFlowControl", func(t *testing.T) {
+		c := conf
+		c.FlowControl = "bogus"
+		require.ErrorIs(t, Validate(c), ErrInvalidConfig)
+	})
+
+	t.Run("InvalidInjectionQueuePolicy", func(t *testing.T) {
+		c := conf
+		c.InjectionQueuePolicy = "bogus"
+		require.ErrorIs(t, Validate(c), ErrInvalidConfig)
+	})
+}
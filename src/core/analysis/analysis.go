@@ -2,12 +2,32 @@ package analysis
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"main/src/domain"
 	"main/src/topology"
 )
 
+// Reports whether the analyses support the simulation configuration. Under vct & saf switching a packet's header flit
+// waits on credits for, or the arrival of, the whole packet at every hop, which their wormhole latencies do not include.
+// Their interference terms only hold under fixed priority arbitration, any other arbiter may serve lower priority flits
+// ahead of higher priority ones.
+func Supports(conf domain.SimConfig) error {
+	if conf.WholePacketSwitching() {
+		return errors.Join(domain.ErrUnsupportedAnalysis, fmt.Errorf("%s switching is not supported", conf.SwitchingMode()))
+	}
+	if conf.ArbitrationPolicy() != domain.FixedPriorityArbitration {
+		return errors.Join(domain.ErrUnsupportedAnalysis, fmt.Errorf("%s arbitration is not supported", conf.ArbitrationPolicy()))
+	}
+	return nil
+}
+
 func Analysis(ctx context.Context, conf domain.SimConfig, top *topology.Topology, trafficFlows []domain.TrafficFlowConfig) (domain.AnalysisResults, error) {
+	if err := Supports(conf); err != nil {
+		return nil, err
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -29,7 +49,11 @@ func Analysis(ctx context.Context, conf domain.SimConfig, top *topology.Topology
 			return nil, err
 		}
 
-		analysisTFs, err = shiBurns(ctx, analysisTFs)
+		if conf.Preemptive() {
+			analysisTFs, err = shiBurns(ctx, analysisTFs)
+		} else {
+			analysisTFs, err = nonPreemptiveShiBurns(ctx, analysisTFs)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -39,9 +63,14 @@ func Analysis(ctx context.Context, conf domain.SimConfig, top *topology.Topology
 			res[analysisTFs[i].ID] = domain.TrafficFlowAnalysisSet{
 				TrafficFlowConfig:         analysisTFs[i].TrafficFlowConfig,
 				Basic:                     analysisTFs[i].Basic,
+				Blocking:                  analysisTFs[i].Blocking,
 				ShiAndBurns:               analysisTFs[i].ShiAndBurns,
 				DirectInterferenceCount:   analysisTFs[i].DirectInterferenceCount,
 				IndirectInterferenceCount: analysisTFs[i].IndirectInterferenceCount,
+				InterferenceSets: domain.InterferenceSets{
+					Direct:   sortedKeys(analysisTFs[i].directIntSet),
+					Indirect: sortedKeys(analysisTFs[i].indirectIntSet),
+				},
 			}
 		}
 
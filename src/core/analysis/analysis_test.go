@@ -0,0 +1,62 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"main/src/domain"
+	"main/src/topology"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalysisSupportedSwitching(t *testing.T) {
+	t.Parallel()
+
+	tfs := []domain.TrafficFlowConfig{
+		{ID: "t1", Priority: 1, Period: 50, Deadline: 50, PacketSize: 4, Route: "[n0,n1,n2]"},
+		{ID: "t2", Priority: 2, Period: 50, Deadline: 50, PacketSize: 4, Route: "[n0,n1,n2]"},
+	}
+
+	for _, mode := range domain.SwitchingModes() {
+		t.Run(string(mode), func(t *testing.T) {
+			conf := domain.SimConfig{MaxPriority: 2, BufferSize: 8, ProcessingDelay: 1, Switching: mode}
+
+			res, err := Analysis(context.Background(), conf, topology.ThreeNodeLine(t), tfs)
+			if conf.WholePacketSwitching() {
+				require.ErrorIs(t, err, domain.ErrUnsupportedAnalysis)
+				assert.Nil(t, res)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, res, len(tfs))
+		})
+	}
+}
+
+func TestAnalysisSupportedArbitration(t *testing.T) {
+	t.Parallel()
+
+	tfs := []domain.TrafficFlowConfig{
+		{ID: "t1", Priority: 1, Period: 50, Deadline: 50, PacketSize: 4, Route: "[n0,n1,n2]"},
+		{ID: "t2", Priority: 2, Period: 50, Deadline: 50, PacketSize: 4, Route: "[n0,n1,n2]"},
+	}
+
+	for _, policy := range append(domain.ArbitrationPolicies(), "") {
+		t.Run(string(policy), func(t *testing.T) {
+			conf := domain.SimConfig{MaxPriority: 2, BufferSize: 8, ProcessingDelay: 1, Arbiter: policy}
+
+			res, err := Analysis(context.Background(), conf, topology.ThreeNodeLine(t), tfs)
+			if conf.ArbitrationPolicy() != domain.FixedPriorityArbitration {
+				require.ErrorIs(t, err, domain.ErrUnsupportedAnalysis)
+				assert.Nil(t, res)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, res, len(tfs))
+		})
+	}
+}
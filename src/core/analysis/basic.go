@@ -19,7 +19,130 @@ func basicLatency(ctx context.Context, conf domain.SimConfig, analysisTFs []anal
 }
 
 func calcBasicLatency(conf domain.SimConfig, aTF analysisTF) int {
-	noFlits := aTF.PacketSize
-	processingDelay := len(aTF.Route) * conf.ProcessingDelay
-	return noFlits + processingDelay
+	// Each router along the route passes the header flit through its own pipeline, the body & tail flits following
+	// through the pipelined stages one switch allocation apart, so a router forwards the packet's flits no faster than
+	// one every switch allocation. When the switch allocation outlasts the spacing of the narrow link the router
+	// forwards onto, a flit of a lower priority packet may take the link in between, delaying every flit by up to the
+	// spacing.
+	processingDelay := 0
+	rate := flitRate{cycles: 1, flits: 1}
+	for i := 0; i < len(aTF.Route); i++ {
+		hop := hopConfig(conf, aTF, i)
+		latency, _ := hop.PipelineDelay(true)
+		processingDelay += latency
+
+		_, atHead := hop.PipelineDelay(false)
+		cycles := max(atHead, 1)
+		if spacing := linkSpacing(conf, aTF, i); cycles > spacing {
+			cycles += spacing - 1
+		}
+		rate = rate.slowest(flitRate{cycles: cycles, flits: 1})
+	}
+
+	// A single cycle link is already covered by the flit count, only extra link stages add latency. The packet is
+	// serialised at the rate of the route's narrowest link, network interface links carry a flit every cycle so wider
+	// links do not shorten it. A narrow link spaces every flit it carries, so the header flit may wait out the spacing
+	// after a flit of a lower priority packet sent just before it.
+	linkDelay := 0
+	for _, link := range aTF.links {
+		link = conf.ResolveLink(link)
+		linkDelay += link.Latency - 1 + link.CyclesPerFlit() - 1
+		rate = rate.slowest(flitRate{cycles: link.CyclesPerFlit(), flits: 1})
+	}
+
+	// The destination network interface consumes the packet's flits no faster than its ejection rate, and the header
+	// flit may wait out the ejection rate's spacing after a flit of another packet consumed just before it.
+	ejectionCycles := domain.CyclesPerFlit(conf.ForNode(aTF.destination).EjectionRate)
+	rate = rate.slowest(flitRate{cycles: ejectionCycles, flits: 1})
+	ejectionDelay := ejectionCycles - 1
+
+	// The packet's virtual channel in each buffer along the route limits it to the channel's depth in flits every round
+	// trip of its credits.
+	for _, loop := range creditLoops(conf, aTF) {
+		rate = rate.slowest(loop)
+	}
+
+	serialisation := rate.cyclesFor(aTF.PacketSize-1) + 1
+
+	return serialisation + processingDelay + linkDelay + ejectionDelay
+}
+
+// Returns the simulation configuration of the i-th router along the traffic flow's route.
+func hopConfig(conf domain.SimConfig, aTF analysisTF, i int) domain.SimConfig {
+	var hop domain.NodeConfig
+	if i < len(aTF.hops) {
+		hop = aTF.hops[i]
+	}
+	return conf.ForNode(hop)
+}
+
+// Returns the cycles the link leaving the i-th router along the traffic flow's route spaces its flits, the destination
+// network interface's link carrying a flit every cycle.
+func linkSpacing(conf domain.SimConfig, aTF analysisTF, i int) int {
+	if i >= len(aTF.links) {
+		return 1
+	}
+	return conf.ResolveLink(aTF.links[i]).CyclesPerFlit()
+}
+
+// Returns the rate each router input buffer along the traffic flow's route admits the packet's flits at, a virtual
+// channel of depth d admitting d flits every round trip of a body flit's credit. A round trip lasts from the flit being
+// sent, across the link and through the downstream router's pipeline, to its credit returning over the credit link, the
+// header flit's longer pipeline delay is already part of the latency, a narrow link adding its spacing as a flit
+// waiting on a credit may find a flit of a lower priority packet took the link while it waited. Buffers whose virtual channel depth is unknown, or
+// which hold the whole packet, are left out. The destination network interface consumes a flit the cycle it arrives, so
+// its ejection buffer never limits the packet.
+func creditLoops(conf domain.SimConfig, aTF analysisTF) []flitRate {
+	loops := make([]flitRate, 0, len(aTF.Route))
+
+	// The source network interface's single cycle link into the first router's local port.
+	first := hopConfig(conf, aTF, 0)
+	latency, _ := first.PipelineDelay(false)
+	loops = append(loops, flitRate{
+		cycles: latency + 1 + linkSpacing(conf, aTF, 0) - 1,
+		flits:  first.VChanDepth(first.BufferSize, aTF.Priority),
+	})
+
+	for i, link := range aTF.links {
+		link = conf.ResolveLink(link)
+		next := hopConfig(conf, aTF, i+1)
+
+		bufferSize := next.BufferSize
+		if link.BufferSize > 0 {
+			bufferSize = link.BufferSize
+		}
+
+		latency, _ := next.PipelineDelay(false)
+		loops = append(loops, flitRate{
+			cycles: link.Latency - 1 + latency + link.CreditLatency + link.CyclesPerFlit() - 1 + linkSpacing(conf, aTF, i+1) - 1,
+			flits:  next.VChanDepth(bufferSize, aTF.Priority),
+		})
+	}
+
+	binding := loops[:0]
+	for _, loop := range loops {
+		if loop.flits > 0 && loop.flits < aTF.PacketSize {
+			binding = append(binding, loop)
+		}
+	}
+	return binding
+}
+
+// A rate of flits every number of cycles.
+type flitRate struct {
+	cycles int
+	flits  int
+}
+
+// Returns the slower of the two rates.
+func (r flitRate) slowest(other flitRate) flitRate {
+	if other.cycles*r.flits > r.cycles*other.flits {
+		return other
+	}
+	return r
+}
+
+// Returns the cycles to carry n flits at the rate, rounded up.
+func (r flitRate) cyclesFor(n int) int {
+	return (n*r.cycles + r.flits - 1) / r.flits
 }
@@ -94,6 +94,229 @@ func TestBasicLatency(t *testing.T) {
 			},
 			expected: 21,
 		},
+		{
+			conf: domain.SimConfig{
+				ProcessingDelay: 3,
+				LinkLatency:     3,
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 2,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 15,
+		},
+		{
+			conf: domain.SimConfig{
+				ProcessingDelay: 3,
+				LinkLatency:     3,
+			},
+			top: func(t testing.TB) *topology.Topology {
+				top := topology.ThreeNodeLine(t)
+				edge, exists := top.Edge("e1")
+				require.True(t, exists)
+				edge.SetLinkConfig(domain.LinkConfig{Latency: 5})
+				return top
+			},
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 2,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 17,
+		},
+		{
+			conf: domain.SimConfig{
+				ProcessingDelay: 3,
+				LinkBandwidth:   4,
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 4,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 13,
+		},
+		{
+			conf: domain.SimConfig{
+				ProcessingDelay: 3,
+			},
+			top: func(t testing.TB) *topology.Topology {
+				top := topology.ThreeNodeLine(t)
+				edge, exists := top.Edge("e0")
+				require.True(t, exists)
+				edge.SetLinkConfig(domain.LinkConfig{Bandwidth: 0.5})
+				return top
+			},
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 4,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 17,
+		},
+		{
+			conf: domain.SimConfig{
+				ProcessingDelay: 3,
+			},
+			top: func(t testing.TB) *topology.Topology {
+				top := topology.ThreeNodeLine(t)
+				top.SetNodeConfig("n1", domain.NodeConfig{ProcessingDelay: 7})
+				return top
+			},
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 2,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 15,
+		},
+		{
+			conf: domain.SimConfig{
+				Pipeline: domain.RouterPipeline{RoutingComputation: 1, VCAllocation: 1, SwitchAllocation: 1, SwitchTraversal: 1},
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 2,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 14,
+		},
+		{
+			conf: domain.SimConfig{
+				Pipeline: domain.RouterPipeline{RoutingComputation: 1, VCAllocation: 1, SwitchAllocation: 1, SwitchTraversal: 1},
+			},
+			top: func(t testing.TB) *topology.Topology {
+				top := topology.ThreeNodeLine(t)
+				top.SetNodeConfig("n1", domain.NodeConfig{ProcessingDelay: 7})
+				return top
+			},
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 2,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 17,
+		},
+		{
+			conf: domain.SimConfig{
+				Pipeline: domain.RouterPipeline{RoutingComputation: 1, VCAllocation: 1, SwitchAllocation: 2, SwitchTraversal: 1},
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 5,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 24,
+		},
+		{
+			conf: domain.SimConfig{
+				ProcessingDelay: 1,
+				EjectionRate:    0.5,
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 5,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 13,
+		},
+		{
+			conf: domain.SimConfig{
+				ProcessingDelay: 1,
+			},
+			top: func(t testing.TB) *topology.Topology {
+				top := topology.ThreeNodeLine(t)
+				top.SetNodeConfig("n0", domain.NodeConfig{EjectionRate: 0.5})
+				top.SetNodeConfig("n2", domain.NodeConfig{EjectionRate: 0.25})
+				return top
+			},
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				PacketSize: 5,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 23,
+		},
+		{
+			conf: domain.SimConfig{
+				MaxPriority:     1,
+				BufferSize:      2,
+				ProcessingDelay: 1,
+				CreditLatency:   4,
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				Priority:   1,
+				PacketSize: 10,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 27,
+		},
+		{
+			conf: domain.SimConfig{
+				MaxPriority:     2,
+				BufferSize:      6,
+				VCDepths:        []int{4, 2},
+				ProcessingDelay: 1,
+				CreditLatency:   4,
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				Priority:   1,
+				PacketSize: 10,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 16,
+		},
+		{
+			conf: domain.SimConfig{
+				MaxPriority:     2,
+				BufferSize:      6,
+				VCDepths:        []int{4, 2},
+				ProcessingDelay: 1,
+				CreditLatency:   4,
+			},
+			top: topology.ThreeNodeLine,
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				Priority:   2,
+				PacketSize: 10,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 27,
+		},
+		{
+			conf: domain.SimConfig{
+				MaxPriority:     2,
+				BufferSize:      4,
+				ProcessingDelay: 1,
+				CreditLatency:   4,
+			},
+			top: func(t testing.TB) *topology.Topology {
+				top := topology.ThreeNodeLine(t)
+				for _, id := range []string{"e0", "e1"} {
+					edge, exists := top.Edge(id)
+					require.True(t, exists)
+					edge.SetLinkConfig(domain.LinkConfig{BufferSize: 20})
+				}
+				return top
+			},
+			tf: domain.TrafficFlowConfig{
+				ID:         "t1",
+				Priority:   1,
+				PacketSize: 10,
+				Route:      "[n0,n1,n2]",
+			},
+			expected: 13,
+		},
 	}
 
 	for i := 0; i < len(testCases); i++ {
@@ -0,0 +1,188 @@
+package analysis
+
+import (
+	"context"
+	"math"
+
+	"main/src/domain"
+)
+
+// Bounds response times under non-preemptive switching, where a packet holds each output port on its route from its
+// header flit being granted the port until its tail flit has been sent, so a packet waiting for a port waits out the
+// whole hold of the packet granted it, which may itself be waiting at later ports on its own route. Assumes routes
+// free of cyclic port dependencies, as deadlock free routing guarantees, so a packet holding a port only ever waits at
+// later ports on its route, never for a packet waiting behind it.
+//
+// A packet's hold of a port is bound by its basic latency plus its waits at the later ports on its route. Its header
+// flit waits at each port for the packet holding it on arrival, of any traffic flow sharing the port, then for every
+// packet of a higher priority traffic flow sharing the port granted it while the header waits. Waits count higher
+// priority packets using their response times & waits grow each other's holds, so all are repeated until they settle.
+// A traffic flow whose response time & jitter may exceed its period may have packets waiting behind its own, which the
+// waits do not include, so it has no bound & neither has any traffic flow waiting on its packets.
+// Assumes analysisTFs are sorted by priority & Basic latency has been calculated.
+func nonPreemptiveShiBurns(ctx context.Context, analysisTFs []analysisTF) ([]analysisTF, error) {
+	analysisTFs = findIntereferenceSets(analysisTFs)
+
+	np := newNonPreemptive(analysisTFs)
+	for settled := false; !settled; {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			settled = np.iterate()
+		}
+	}
+
+	for i := 0; i < len(analysisTFs); i++ {
+		analysisTFs[i].Blocking = np.blocking[i]
+		analysisTFs[i].ShiAndBurns = np.response(i)
+		if np.unbounded[i] {
+			analysisTFs[i].ShiAndBurns = max(analysisTFs[i].ShiAndBurns, analysisTFs[i].Deadline+1)
+		}
+	}
+
+	return analysisTFs, nil
+}
+
+type nonPreemptive struct {
+	analysisTFs []analysisTF
+	// Output ports on each traffic flow's route in route order, and the traffic flows sent through each port.
+	ports   [][]string
+	sharers map[string][]int
+	// Each traffic flow's wait at each port on its route, and the part spent waiting on lower priority packets.
+	waits     [][]int
+	blocking  []int
+	unbounded []bool
+}
+
+func newNonPreemptive(analysisTFs []analysisTF) *nonPreemptive {
+	np := &nonPreemptive{
+		analysisTFs: analysisTFs,
+		ports:       make([][]string, len(analysisTFs)),
+		sharers:     make(map[string][]int),
+		waits:       make([][]int, len(analysisTFs)),
+		blocking:    make([]int, len(analysisTFs)),
+		unbounded:   make([]bool, len(analysisTFs)),
+	}
+
+	for i := 0; i < len(analysisTFs); i++ {
+		np.ports[i] = routePorts(analysisTFs[i].Route)
+		np.waits[i] = make([]int, len(np.ports[i]))
+		for _, port := range np.ports[i] {
+			np.sharers[port] = append(np.sharers[port], i)
+		}
+	}
+
+	return np
+}
+
+// Recalculates every bounded traffic flow's waits from the current holds & response times, reporting whether they
+// have settled. Waits only grow & a traffic flow left unbounded stays so.
+func (np *nonPreemptive) iterate() bool {
+	settled := true
+	for i := 0; i < len(np.analysisTFs); i++ {
+		if np.unbounded[i] {
+			continue
+		}
+
+		blocking := 0
+		for k, port := range np.ports[i] {
+			wait, lower, ok := np.portWait(i, port)
+			if !ok {
+				np.unbounded[i] = true
+				settled = false
+				break
+			}
+
+			if wait > np.waits[i][k] {
+				np.waits[i][k] = wait
+				settled = false
+			}
+			blocking += lower
+		}
+		np.blocking[i] = max(np.blocking[i], blocking)
+
+		tf := np.analysisTFs[i]
+		if !np.unbounded[i] && np.response(i)+tf.Jitter > tf.Period {
+			np.unbounded[i] = true
+			settled = false
+		}
+	}
+
+	return settled
+}
+
+// Returns traffic flow i's wait at the port & the part of it spent on a lower priority packet holding the port on
+// arrival, or false should the wait have no bound.
+func (np *nonPreemptive) portWait(i int, port string) (int, int, bool) {
+	tf := np.analysisTFs[i]
+
+	holding, lower := 0, 0
+	higher := make([]int, 0, len(np.sharers[port]))
+	for _, j := range np.sharers[port] {
+		if j == i {
+			continue
+		}
+		if np.unbounded[j] {
+			return 0, 0, false
+		}
+
+		hold := np.hold(j, port)
+		holding = max(holding, hold)
+		if np.analysisTFs[j].Priority > tf.Priority {
+			lower = max(lower, hold)
+		} else {
+			higher = append(higher, j)
+		}
+	}
+
+	current := holding
+	for {
+		next := holding
+		for _, j := range higher {
+			hp := np.analysisTFs[j]
+			x := int(math.Ceil(float64(current+hp.Jitter+np.response(j)) / float64(hp.Period)))
+			next += x * np.hold(j, port)
+		}
+
+		if next == current {
+			return current, lower, true
+		}
+		if tf.Basic+next+tf.Jitter > tf.Period {
+			return 0, 0, false
+		}
+		current = next
+	}
+}
+
+// Returns how long a packet of traffic flow j may hold the port, its basic latency plus its waits at the later ports.
+func (np *nonPreemptive) hold(j int, port string) int {
+	hold := np.analysisTFs[j].Basic
+	for k := len(np.ports[j]) - 1; k >= 0 && np.ports[j][k] != port; k-- {
+		hold += np.waits[j][k]
+	}
+	return hold
+}
+
+func (np *nonPreemptive) response(i int) int {
+	response := np.analysisTFs[i].Basic
+	for _, wait := range np.waits[i] {
+		response += wait
+	}
+	return response
+}
+
+// Returns the output ports a packet following the route is sent through, its source's injection link, each router to
+// router link & the last router's ejection port, matching the ports intersectingRoutes shares.
+func routePorts(route domain.Route) []string {
+	if len(route) == 0 {
+		return nil
+	}
+
+	ports := make([]string, 0, len(route)+1)
+	ports = append(ports, "inject "+route[0])
+	for i := 0; i < len(route)-1; i++ {
+		ports = append(ports, route[i]+" "+route[i+1])
+	}
+	return append(ports, "eject "+route[len(route)-1])
+}
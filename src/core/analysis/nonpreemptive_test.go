@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"main/src/domain"
+	"main/src/topology"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonPreemptiveShiBurns(t *testing.T) {
+	t.Parallel()
+
+	conf := domain.SimConfig{CycleLimit: 10000, MaxPriority: 2, BufferSize: 8, ProcessingDelay: 1}
+
+	nonPreemptive := func(t *testing.T, t2Period int) []analysisTF {
+		aTfs, err := constructAnalysisTfs(topology.ThreeNodeLine(t), []domain.TrafficFlowConfig{
+			{ID: "t1", Priority: 1, Period: 100, Deadline: 100, PacketSize: 4, Route: "[n0,n1,n2]"},
+			{ID: "t2", Priority: 2, Period: t2Period, Deadline: t2Period, PacketSize: 8, Route: "[n1,n2]"},
+		})
+		require.NoError(t, err)
+
+		aTfs, err = basicLatency(context.TODO(), conf, aTfs)
+		require.NoError(t, err)
+
+		aTfs, err = nonPreemptiveShiBurns(context.TODO(), aTfs)
+		require.NoError(t, err)
+		return aTfs
+	}
+
+	t.Run("Bounded", func(t *testing.T) {
+		aTfs := nonPreemptive(t, 100)
+
+		// t2 holds the ejection port for its basic latency of 10, and n1's output port towards n2 for 10 plus its wait of
+		// 14 at the ejection port, for t1's packet holding it & one granted ahead of it, blocking t1 for 34 in total.
+		// t2 waits 34 at n1's output port towards n2, for t1's hold of 17 & one more t1 packet, and 14 at the ejection
+		// port.
+		expectedBlocking := []int{34, 0}
+		expectedShiBurns := []int{41, 58}
+
+		assert.Len(t, aTfs, len(expectedBlocking))
+		for i := 0; i < len(aTfs); i++ {
+			assert.Equal(t, expectedBlocking[i], aTfs[i].Blocking)
+			assert.Equal(t, expectedShiBurns[i], aTfs[i].ShiAndBurns)
+			assert.True(t, aTfs[i].AnalysisSchedulable())
+		}
+	})
+
+	t.Run("Unbounded", func(t *testing.T) {
+		// t2's packets may queue behind its own, leaving both t2 & t1, which waits on t2's packets, without a bound.
+		aTfs := nonPreemptive(t, 50)
+
+		assert.Len(t, aTfs, 2)
+		for i := 0; i < len(aTfs); i++ {
+			assert.Greater(t, aTfs[i].ShiAndBurns, aTfs[i].Deadline)
+			assert.False(t, aTfs[i].AnalysisSchedulable())
+		}
+	})
+}
+
+func TestRoutePorts(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, routePorts(domain.Route{}))
+	assert.Equal(t, []string{"inject n0", "eject n0"}, routePorts(domain.Route{"n0"}))
+	assert.Equal(t, []string{"inject n0", "n0 n1", "n1 n2", "eject n2"}, routePorts(domain.Route{"n0", "n1", "n2"}))
+}
@@ -1,6 +1,8 @@
 package analysis
 
 import (
+	"sort"
+
 	"main/src/domain"
 	"main/src/topology"
 )
@@ -8,6 +10,12 @@ import (
 type analysisTF struct {
 	domain.TrafficFlowAnalysisSet
 	domain.Route
+	// Configuration of each router along the route, and of each router to router link between them.
+	hops  []domain.NodeConfig
+	links []domain.LinkConfig
+	// Configuration of the destination network interface's node.
+	destination domain.NodeConfig
+
 	directIntSet   map[string]int
 	indirectIntSet map[string]int
 }
@@ -36,6 +44,15 @@ func newAnalysisTF(top *topology.Topology, trafficFlow domain.TrafficFlowConfig)
 		return analysisTF{}, err
 	}
 
+	hops := make([]domain.NodeConfig, len(route))
+	links := make([]domain.LinkConfig, 0, len(route))
+	for i := 0; i < len(route); i++ {
+		hops[i] = top.NodeConfig(route[i])
+		if i+1 < len(route) {
+			links = append(links, top.LinkConfig(route[i], route[i+1]))
+		}
+	}
+
 	return analysisTF{
 		TrafficFlowAnalysisSet: domain.TrafficFlowAnalysisSet{
 			TrafficFlowConfig:         trafficFlow,
@@ -44,7 +61,10 @@ func newAnalysisTF(top *topology.Topology, trafficFlow domain.TrafficFlowConfig)
 			DirectInterferenceCount:   -1,
 			IndirectInterferenceCount: -1,
 		},
-		Route: route,
+		Route:       route,
+		hops:        hops,
+		links:       links,
+		destination: top.NodeConfig(strRoute[len(strRoute)-1]),
 	}, nil
 }
 
@@ -62,3 +82,13 @@ func sortTfsByPriority(trafficFlows []domain.TrafficFlowConfig) []domain.Traffic
 
 	return tfs
 }
+
+func sortedKeys(set map[string]int) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"main/src/core/analysis"
@@ -15,7 +16,29 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func Run(conf domain.SimConfig, top *topology.Topology, trafficConf []domain.TrafficFlowConfig, runAnalysisFlag bool, logger zerolog.Logger) (results.Results, error) {
+type Options struct {
+	Analysis bool
+	// Logs every flit sent over every link so deadline misses can report the flits which occupied the links they
+	// waited for, memory use grows with the number of flits simulated.
+	LinkTraversalLog bool
+	// Fault schedule injected into the network, no faults are injected if empty.
+	Faults []domain.FaultConfig
+	// Operating modes switching the traffic flows which release packets, all traffic flows are active if empty.
+	Modes []domain.ModeConfig
+	// Receives periodic snapshots of the simulation's progress, unused if nil.
+	Progress simulation.ProgressFunc
+}
+
+// Runs the simulation, and analysis if enabled. Cancelling the context interrupts the simulation, returning partial
+// results truncated at the cycle reached.
+func Run(ctx context.Context, conf domain.SimConfig, top *topology.Topology, trafficConf []domain.TrafficFlowConfig, opts Options, logger zerolog.Logger) (results.Results, error) {
+	if opts.Analysis {
+		if err := analysis.Supports(conf); err != nil {
+			logger.Error().Err(err).Msg("analysis does not support the configuration")
+			return nil, err
+		}
+	}
+
 	network, err := network.NewNetwork(
 		top,
 		conf,
@@ -26,18 +49,33 @@ func Run(conf domain.SimConfig, top *topology.Topology, trafficConf []domain.Tra
 		return nil, err
 	}
 
+	if opts.LinkTraversalLog {
+		network.EnableLinkTraversalLog()
+	}
+
+	if len(opts.Faults) > 0 {
+		if err := network.InjectFaults(opts.Faults); err != nil {
+			logger.Error().Err(err).Msg("error injecting faults")
+			return nil, err
+		}
+	}
+
 	trafficFlows, err := traffic.TrafficFlows(conf, trafficConf)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("error constructing traffic flows")
 	}
+	if err := traffic.ValidateRouteBuffers(conf, top, trafficFlows); err != nil {
+		logger.Error().Err(err).Msg("error validating traffic flow route buffers")
+		return nil, err
+	}
 
 	var wg sync.WaitGroup
 	analysisResultsChan := make(chan domain.AnalysisResults, 1)
 	analysisErrChan := make(chan error, 1)
-	ctx, cancelFunc := context.WithCancel(context.Background())
+	ctx, cancelFunc := context.WithCancel(ctx)
 	defer cancelFunc()
 
-	if runAnalysisFlag {
+	if opts.Analysis {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -75,7 +113,9 @@ func Run(conf domain.SimConfig, top *topology.Topology, trafficConf []domain.Tra
 		ctx,
 		network,
 		trafficFlows,
+		opts.Modes,
 		conf.CycleLimit,
+		opts.Progress,
 		logger,
 	)
 	if err != nil {
@@ -84,14 +124,41 @@ func Run(conf domain.SimConfig, top *topology.Topology, trafficConf []domain.Tra
 		return nil, err
 	}
 
+	if !opts.Analysis {
+		resultsSet, err := results.NewResults(simResults, trafficConf)
+		if err != nil {
+			logger.Error().Err(err).Msg("error constructing results")
+			return nil, err
+		}
+		return resultsSet, nil
+	}
+
+	wg.Wait()
+
+	var analysisResults domain.AnalysisResults
+	select {
+	case analysisResults = <-analysisResultsChan:
+	case err = <-analysisErrChan:
+	}
+
+	return combineResults(simResults, analysisResults, err, trafficConf, logger)
+}
+
+// Combines the simulation results with the analysis results, or the error the analysis returned. Simulation results
+// are kept when the analysis was interrupted by the context, such as by the time budget expiring after the simulation
+// finished, with the analysis marked as interrupted.
+func combineResults(simResults domain.SimResults, analysisResults domain.AnalysisResults, analysisErr error, trafficConf []domain.TrafficFlowConfig, logger zerolog.Logger) (results.Results, error) {
 	var resultsSet results.Results
-	if runAnalysisFlag {
-		wg.Wait()
-		var analysisResults domain.AnalysisResults = <-analysisResultsChan
+	var err error
 
+	switch {
+	case analysisErr == nil:
 		resultsSet, err = results.NewResultsWithAnalysis(simResults, analysisResults, trafficConf)
-	} else {
-		resultsSet, err = results.NewResults(simResults, trafficConf)
+	case errors.Is(analysisErr, context.Canceled) || errors.Is(analysisErr, context.DeadlineExceeded):
+		logger.Warn().Err(analysisErr).Msg("analysis interrupted, results exclude analysis")
+		resultsSet, err = results.NewResultsWithInterruptedAnalysis(simResults, trafficConf)
+	default:
+		return nil, analysisErr
 	}
 	if err != nil {
 		logger.Error().Err(err).Msg("error constructing results")
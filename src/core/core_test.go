@@ -0,0 +1,253 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"main/src/core/analysis"
+	"main/src/core/network"
+	"main/src/core/simulation"
+	"main/src/domain"
+	"main/src/topology"
+	"main/src/traffic"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = domain.SimConfig{
+	MaxPriority:     2,
+	BufferSize:      4,
+	ProcessingDelay: 3,
+	CycleLimit:      500,
+}
+
+var testTraffic = []domain.TrafficFlowConfig{
+	{ID: "t1", Priority: 1, Period: 50, Deadline: 50, PacketSize: 2, Route: "[n0,n1,n2]"},
+	{ID: "t2", Priority: 2, Period: 60, Deadline: 60, PacketSize: 4, Route: "[n0,n1,n2]"},
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Analysis", func(t *testing.T) {
+		res, err := Run(context.Background(), testConfig, topology.ThreeNodeLine(t), testTraffic, Options{Analysis: true}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		assert.False(t, res.Truncated())
+		assert.False(t, res.AnalysisInterrupted())
+	})
+
+	t.Run("UnsupportedAnalysis", func(t *testing.T) {
+		conf := testConfig
+		conf.Arbiter = domain.RoundRobinArbitration
+
+		_, err := Run(context.Background(), conf, topology.ThreeNodeLine(t), testTraffic, Options{Analysis: true}, zerolog.New(io.Discard))
+		require.ErrorIs(t, err, domain.ErrUnsupportedAnalysis)
+
+		res, err := Run(context.Background(), conf, topology.ThreeNodeLine(t), testTraffic, Options{}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		assert.False(t, res.AnalysisInterrupted())
+	})
+
+	t.Run("BudgetExpiresDuringAnalysis", func(t *testing.T) {
+		// The simulation completes within the budget, which expires before the analysis finishes.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+
+		_, analysisErr := analysis.Analysis(ctx, testConfig, topology.ThreeNodeLine(t), testTraffic)
+		require.ErrorIs(t, analysisErr, context.DeadlineExceeded)
+
+		res, err := combineResults(simulationResults(t), nil, analysisErr, testTraffic, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		assert.False(t, res.Truncated())
+		assert.True(t, res.AnalysisInterrupted())
+
+		str, err := res.Prettify()
+		require.NoError(t, err)
+		assert.Contains(t, str, "results exclude analysis")
+	})
+
+	t.Run("AnalysisError", func(t *testing.T) {
+		analysisErr := errors.New("analysis failed")
+
+		res, err := combineResults(simulationResults(t), nil, analysisErr, testTraffic, zerolog.New(io.Discard))
+		assert.ErrorIs(t, err, analysisErr)
+		assert.Nil(t, res)
+	})
+}
+
+func simulationResults(t *testing.T) domain.SimResults {
+	t.Helper()
+
+	simRes := domain.SimResults{
+		SimHeadlineResults: domain.SimHeadlineResults{Cycles: testConfig.CycleLimit, CycleLimit: testConfig.CycleLimit},
+		TFStats:            make(map[string]domain.StatSet, len(testTraffic)),
+	}
+	for _, tf := range testTraffic {
+		simRes.TFStats[tf.ID] = domain.StatSet{}
+	}
+
+	return simRes
+}
+
+func TestBasicLatencyBoundsSimulation(t *testing.T) {
+	type testCase struct {
+		conf domain.SimConfig
+		top  func(t testing.TB) *topology.Topology
+		tfs  []domain.TrafficFlowConfig
+	}
+
+	testCases := map[string]testCase{
+		"ShallowVirtualChannels": {
+			conf: domain.SimConfig{
+				MaxPriority:     2,
+				BufferSize:      6,
+				VCDepths:        []int{1, 5},
+				ProcessingDelay: 3,
+				LinkLatency:     2,
+				CreditLatency:   4,
+				CycleLimit:      1000,
+			},
+			top: topology.FiveNodeLine,
+			tfs: []domain.TrafficFlowConfig{
+				{ID: "t1", Priority: 1, Period: 500, Deadline: 500, PacketSize: 12, Route: "[n0,n1,n2,n3]"},
+				{ID: "t2", Priority: 2, Period: 500, Deadline: 500, PacketSize: 12, Route: "[n4,n3,n2,n1]"},
+			},
+		},
+		"MultiCycleSwitchAllocation": {
+			conf: domain.SimConfig{
+				MaxPriority:     2,
+				BufferSize:      8,
+				ProcessingDelay: 1,
+				Pipeline:        domain.RouterPipeline{RoutingComputation: 1, VCAllocation: 1, SwitchAllocation: 3, SwitchTraversal: 2},
+				CycleLimit:      1000,
+			},
+			top: topology.FiveNodeLine,
+			tfs: []domain.TrafficFlowConfig{
+				{ID: "t1", Priority: 1, Period: 500, Deadline: 500, PacketSize: 11, Route: "[n0,n1,n2,n3,n4]"},
+				{ID: "t2", Priority: 2, Period: 500, Deadline: 500, PacketSize: 5, Route: "[n4,n3,n2]"},
+			},
+		},
+		"SlowEjection": {
+			conf: domain.SimConfig{
+				MaxPriority:        2,
+				BufferSize:         4,
+				ProcessingDelay:    2,
+				EjectionRate:       0.25,
+				EjectionBufferSize: 2,
+				CycleLimit:         1000,
+			},
+			top: topology.FiveNodeLine,
+			tfs: []domain.TrafficFlowConfig{
+				{ID: "t1", Priority: 1, Period: 500, Deadline: 500, PacketSize: 9, Route: "[n0,n1,n2,n3]"},
+				{ID: "t2", Priority: 2, Period: 500, Deadline: 500, PacketSize: 6, Route: "[n4,n3,n2]"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			worst, analyses := simulateAndAnalyse(t, tc.conf, tc.top(t), tc.tfs)
+			for _, tf := range tc.tfs {
+				require.Positive(t, worst[tf.ID], tf.ID)
+				assert.LessOrEqual(t, worst[tf.ID], analyses[tf.ID].Basic, tf.ID)
+			}
+		})
+	}
+}
+
+func TestShiAndBurnsBoundsSimulation(t *testing.T) {
+	type testCase struct {
+		conf domain.SimConfig
+		top  func(t testing.TB) *topology.Topology
+		tfs  []domain.TrafficFlowConfig
+	}
+
+	testCases := map[string]testCase{
+		"NarrowLinkLowerPriorityBlocking": {
+			conf: domain.SimConfig{
+				MaxPriority:     2,
+				BufferSize:      20,
+				ProcessingDelay: 1,
+				LinkBandwidth:   0.5,
+				CycleLimit:      10000,
+			},
+			top: topology.ThreeByThreeMesh,
+			tfs: []domain.TrafficFlowConfig{
+				{ID: "t1", Priority: 1, Period: 250, Deadline: 100, PacketSize: 8, Route: "[n0,n1,n2]"},
+				{ID: "t2", Priority: 2, Period: 300, Deadline: 50, PacketSize: 10, Route: "[n1,n2,n5,n8]"},
+			},
+		},
+		"NarrowLinkShallowVirtualChannels": {
+			conf: domain.SimConfig{
+				MaxPriority:     3,
+				BufferSize:      3,
+				ProcessingDelay: 1,
+				LinkBandwidth:   0.5,
+				CycleLimit:      10000,
+			},
+			top: topology.ThreeByThreeMesh,
+			tfs: []domain.TrafficFlowConfig{
+				{ID: "t1", Priority: 1, Period: 500, Deadline: 100, PacketSize: 8, Route: "[n6,n7,n8,n5]"},
+				{ID: "t2", Priority: 2, Period: 200, Deadline: 200, PacketSize: 8, Route: "[n7,n8,n5,n2]"},
+				{ID: "t3", Priority: 3, Period: 200, Deadline: 200, PacketSize: 10, Route: "[n8,n5,n2,n1,n0]"},
+			},
+		},
+		"NonPreemptiveChainedBlocking": {
+			// t3 may hold n1's output port towards n2 ahead of t1 while itself waiting on t2 at n2's output port towards n5.
+			conf: domain.SimConfig{
+				MaxPriority:     3,
+				BufferSize:      6,
+				ProcessingDelay: 1,
+				Switching:       domain.NonPreemptiveWormholeSwitching,
+				CycleLimit:      10000,
+			},
+			top: topology.ThreeByThreeMesh,
+			tfs: []domain.TrafficFlowConfig{
+				{ID: "t1", Priority: 1, Period: 300, Deadline: 300, PacketSize: 8, Route: "[n0,n1,n2]"},
+				{ID: "t2", Priority: 2, Period: 200, Deadline: 200, PacketSize: 16, Route: "[n2,n5,n8]"},
+				{ID: "t3", Priority: 3, Period: 400, Deadline: 400, PacketSize: 12, Route: "[n1,n2,n5]"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			worst, analyses := simulateAndAnalyse(t, tc.conf, tc.top(t), tc.tfs)
+			for _, tf := range tc.tfs {
+				require.Positive(t, worst[tf.ID], tf.ID)
+				assert.LessOrEqual(t, worst[tf.ID], analyses[tf.ID].ShiAndBurns, tf.ID)
+			}
+		})
+	}
+}
+
+// Simulates and analyses the traffic flows, returning each traffic flow's worst simulated latency and its analysis.
+func simulateAndAnalyse(t *testing.T, conf domain.SimConfig, top *topology.Topology, tfs []domain.TrafficFlowConfig) (map[string]int, domain.AnalysisResults) {
+	t.Helper()
+
+	logger := zerolog.New(io.Discard)
+
+	net, err := network.NewNetwork(top, conf, logger)
+	require.NoError(t, err)
+
+	trafficFlows, err := traffic.TrafficFlows(conf, tfs)
+	require.NoError(t, err)
+
+	simRes, err := simulation.Simulate(context.Background(), net, trafficFlows, nil, conf.CycleLimit, nil, logger)
+	require.NoError(t, err)
+
+	analyses, err := analysis.Analysis(context.Background(), conf, top, tfs)
+	require.NoError(t, err)
+
+	worst := make(map[string]int, len(tfs))
+	for id, stats := range simRes.TFStats {
+		worst[id] = stats.WorstLatency
+	}
+	return worst, analyses
+}
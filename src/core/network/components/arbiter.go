@@ -0,0 +1,241 @@
+package components
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+)
+
+// Orders the flits at the heads of a router's input port virtual channels, the router attempts to send the flits in
+// the returned order so earlier flits win contended output ports. Arbiters tracking packets forget them once their tail
+// flit is granted, or dropped by a fault on arrival at the router.
+type Arbiter interface {
+	order(cycle int, heads []bufferedHead) []bufferedHead
+	granted(cycle int, head bufferedHead)
+	dropped(flit packet.Flit)
+}
+
+func newArbiter(conf domain.SimConfig) (Arbiter, error) {
+	switch conf.ArbitrationPolicy() {
+	case domain.FixedPriorityArbitration:
+		return &fixedPriorityArbiter{}, nil
+	case domain.RoundRobinArbitration:
+		arbiter := newRoundRobinArbiter(conf.VCCount())
+		return &arbiter, nil
+	case domain.AgeArbitration:
+		return &ageArbiter{releases: make(map[string]int)}, nil
+	case domain.EDFArbitration:
+		return &edfArbiter{deadlines: make(map[string]int)}, nil
+	case domain.WeightedRoundRobinArbitration:
+		weights := conf.ArbitrationWeights()
+		return &weightedRoundRobinArbiter{
+			roundRobinArbiter: newRoundRobinArbiter(conf.VCCount()),
+			weights:           weights,
+			remaining:         make(map[string][]int),
+		}, nil
+	default:
+		return nil, errors.Join(domain.ErrInvalidParameter, fmt.Errorf("unknown arbitration policy %q", conf.Arbiter))
+	}
+}
+
+// Orders the heads by flit priority, ties in input port then virtual channel order.
+type fixedPriorityArbiter struct{}
+
+func (a *fixedPriorityArbiter) order(cycle int, heads []bufferedHead) []bufferedHead {
+	slices.SortStableFunc(heads, comparePriority)
+	return heads
+}
+
+func (a *fixedPriorityArbiter) granted(cycle int, head bufferedHead) {}
+
+func (a *fixedPriorityArbiter) dropped(flit packet.Flit) {}
+
+// Orders the heads in input port then virtual channel order, starting after the last virtual channel granted the
+// output port the head's flit is routed to, so each output port takes its turn separately.
+type roundRobinArbiter struct {
+	vChanCount int
+	next       map[string]int
+}
+
+func newRoundRobinArbiter(vChanCount int) roundRobinArbiter {
+	return roundRobinArbiter{vChanCount: vChanCount, next: make(map[string]int)}
+}
+
+func (a *roundRobinArbiter) order(cycle int, heads []bufferedHead) []bufferedHead {
+	slices.SortStableFunc(heads, func(x, y bufferedHead) int {
+		return a.turn(x) - a.turn(y)
+	})
+	return heads
+}
+
+func (a *roundRobinArbiter) granted(cycle int, head bufferedHead) {
+	a.next[head.output] = a.slot(head) + 1
+}
+
+func (a *roundRobinArbiter) dropped(flit packet.Flit) {}
+
+func (a *roundRobinArbiter) slot(head bufferedHead) int {
+	return head.inputPort*a.vChanCount + head.vChan - 1
+}
+
+// Returns 0 for virtual channels at or after the next slot of the head's output port, whose turn comes first, and 1
+// otherwise.
+func (a *roundRobinArbiter) turn(head bufferedHead) int {
+	if a.slot(head) < a.next[head.output] {
+		return 1
+	}
+	return 0
+}
+
+// Orders the heads by the cycle their packet was released at its source network interface, ties by flit priority.
+// Release cycles are recorded from header flits, as body flits carry no events, and forgotten once the packet's tail
+// flit is granted.
+type ageArbiter struct {
+	releases map[string]int
+}
+
+func (a *ageArbiter) order(cycle int, heads []bufferedHead) []bufferedHead {
+	for _, head := range heads {
+		if head.flit.Type() == packet.HeaderFlitType {
+			if _, exists := a.releases[head.flit.PacketID()]; !exists {
+				a.releases[head.flit.PacketID()] = releaseCycle(head.flit)
+			}
+		}
+	}
+
+	slices.SortStableFunc(heads, func(x, y bufferedHead) int {
+		if c := cmp.Compare(a.release(x.flit), a.release(y.flit)); c != 0 {
+			return c
+		}
+		return comparePriority(x, y)
+	})
+	return heads
+}
+
+func (a *ageArbiter) granted(cycle int, head bufferedHead) {
+	a.dropped(head.flit)
+}
+
+func (a *ageArbiter) dropped(flit packet.Flit) {
+	if flit.Type() == packet.TailFlitType {
+		delete(a.releases, flit.PacketID())
+	}
+}
+
+func (a *ageArbiter) release(flit packet.Flit) int {
+	if release, exists := a.releases[flit.PacketID()]; exists {
+		return release
+	}
+	return math.MaxInt
+}
+
+// Orders the heads by their packet's absolute deadline, the cycle its release period started plus its deadline, ties by
+// flit priority. Deadlines are recorded from header flits and forgotten once the packet's tail flit is granted or
+// dropped.
+type edfArbiter struct {
+	deadlines map[string]int
+}
+
+func (a *edfArbiter) order(cycle int, heads []bufferedHead) []bufferedHead {
+	for _, head := range heads {
+		if header, ok := head.flit.(packet.HeaderFlit); ok {
+			if _, exists := a.deadlines[header.PacketID()]; !exists {
+				a.deadlines[header.PacketID()] = header.GenerationCycle() + header.Deadline()
+			}
+		}
+	}
+
+	slices.SortStableFunc(heads, func(x, y bufferedHead) int {
+		if c := cmp.Compare(a.deadline(x.flit), a.deadline(y.flit)); c != 0 {
+			return c
+		}
+		return comparePriority(x, y)
+	})
+	return heads
+}
+
+func (a *edfArbiter) granted(cycle int, head bufferedHead) {
+	a.dropped(head.flit)
+}
+
+func (a *edfArbiter) dropped(flit packet.Flit) {
+	if flit.Type() == packet.TailFlitType {
+		delete(a.deadlines, flit.PacketID())
+	}
+}
+
+func (a *edfArbiter) deadline(flit packet.Flit) int {
+	if deadline, exists := a.deadlines[flit.PacketID()]; exists {
+		return deadline
+	}
+	return math.MaxInt
+}
+
+// Round robin in which heads whose priority has flits of its weight remaining this round are ordered first. Each output
+// port has its own rounds, which restart once no head routed to the output port has any remaining.
+type weightedRoundRobinArbiter struct {
+	roundRobinArbiter
+	weights   []int
+	remaining map[string][]int
+}
+
+func (a *weightedRoundRobinArbiter) order(cycle int, heads []bufferedHead) []bufferedHead {
+	heads = a.roundRobinArbiter.order(cycle, heads)
+
+	restarted := make(map[string]bool)
+	for _, head := range heads {
+		if restarted[head.output] {
+			continue
+		}
+		restarted[head.output] = true
+
+		if !slices.ContainsFunc(heads, func(other bufferedHead) bool {
+			return other.output == head.output && a.hasRemaining(other)
+		}) {
+			a.remaining[head.output] = slices.Clone(a.weights)
+		}
+	}
+
+	slices.SortStableFunc(heads, func(x, y bufferedHead) int {
+		return a.exhausted(x) - a.exhausted(y)
+	})
+	return heads
+}
+
+func (a *weightedRoundRobinArbiter) granted(cycle int, head bufferedHead) {
+	a.roundRobinArbiter.granted(cycle, head)
+
+	if a.hasRemaining(head) {
+		a.remaining[head.output][head.flit.Priority()-1]--
+	}
+}
+
+func (a *weightedRoundRobinArbiter) hasRemaining(head bufferedHead) bool {
+	remaining := a.remaining[head.output]
+	p := head.flit.Priority()
+	return p >= 1 && p <= len(remaining) && remaining[p-1] > 0
+}
+
+func (a *weightedRoundRobinArbiter) exhausted(head bufferedHead) int {
+	if a.hasRemaining(head) {
+		return 0
+	}
+	return 1
+}
+
+func comparePriority(x, y bufferedHead) int {
+	return x.flit.Priority() - y.flit.Priority()
+}
+
+// Returns the cycle the flit's packet was created at its source network interface.
+func releaseCycle(flit packet.Flit) int {
+	if event, exists := flit.Events().First(packet.FlitCreated, ""); exists {
+		return event.Cycle
+	}
+	return math.MaxInt
+}
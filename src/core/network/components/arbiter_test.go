@@ -0,0 +1,239 @@
+package components
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHead(inputPort, vChan int, flit packet.Flit) bufferedHead {
+	return bufferedHead{inputPort: inputPort, vChan: vChan, flit: flit}
+}
+
+func testOutputHead(inputPort, vChan int, output string, flit packet.Flit) bufferedHead {
+	return bufferedHead{inputPort: inputPort, vChan: vChan, flit: flit, output: output}
+}
+
+func testHeaderFlit(pktIndex string, priority, deadline, created int) packet.Flit {
+	return testGeneratedHeaderFlit(pktIndex, priority, deadline, created, created)
+}
+
+// Returns a header flit whose packet's release period started at the generated cycle, and was released at the created
+// cycle.
+func testGeneratedHeaderFlit(pktIndex string, priority, deadline, generated, created int) packet.Flit {
+	flit := packet.NewHeaderFlit("t", pktIndex, 0, priority, deadline, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+	flit.SetGenerationCycle(generated)
+	flit.RecordEvent(created, packet.FlitCreated, "n1")
+	return flit
+}
+
+func headPackets(heads []bufferedHead) []string {
+	pkts := make([]string, len(heads))
+	for i, head := range heads {
+		pkts[i] = head.flit.PacketIndex()
+	}
+	return pkts
+}
+
+func TestNewArbiter(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range append(domain.ArbitrationPolicies(), "") {
+		arbiter, err := newArbiter(domain.SimConfig{MaxPriority: 2, Arbiter: policy})
+		require.NoError(t, err)
+		assert.NotNil(t, arbiter)
+	}
+
+	_, err := newArbiter(domain.SimConfig{MaxPriority: 2, Arbiter: "lottery"})
+	require.ErrorIs(t, err, domain.ErrInvalidParameter)
+}
+
+func TestArbiterOrder(t *testing.T) {
+	t.Parallel()
+
+	heads := func() []bufferedHead {
+		return []bufferedHead{
+			testHead(0, 1, testHeaderFlit("A", 2, 50, 5)),
+			testHead(1, 1, testHeaderFlit("B", 1, 100, 10)),
+			testHead(2, 2, testHeaderFlit("C", 3, 20, 0)),
+		}
+	}
+
+	type testCase struct {
+		policy   domain.ArbitrationPolicy
+		expected []string
+	}
+
+	testCases := []testCase{
+		{domain.FixedPriorityArbitration, []string{"B", "A", "C"}},
+		{domain.RoundRobinArbitration, []string{"A", "B", "C"}},
+		{domain.AgeArbitration, []string{"C", "A", "B"}},
+		{domain.EDFArbitration, []string{"C", "A", "B"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			arbiter, err := newArbiter(domain.SimConfig{MaxPriority: 3, Arbiter: tc.policy})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, headPackets(arbiter.order(0, heads())))
+		})
+	}
+}
+
+func TestRoundRobinArbiterGranted(t *testing.T) {
+	t.Parallel()
+
+	arbiter, err := newArbiter(domain.SimConfig{MaxPriority: 2, Arbiter: domain.RoundRobinArbitration})
+	require.NoError(t, err)
+
+	heads := []bufferedHead{
+		testHead(0, 1, testHeaderFlit("A", 1, 10, 0)),
+		testHead(1, 1, testHeaderFlit("B", 1, 10, 0)),
+		testHead(1, 2, testHeaderFlit("C", 2, 10, 0)),
+	}
+
+	arbiter.granted(0, heads[1])
+	assert.Equal(t, []string{"C", "A", "B"}, headPackets(arbiter.order(1, heads)))
+}
+
+func TestRoundRobinArbiterPerOutputPort(t *testing.T) {
+	t.Parallel()
+
+	arbiter, err := newArbiter(domain.SimConfig{MaxPriority: 1, Arbiter: domain.RoundRobinArbitration})
+	require.NoError(t, err)
+
+	a := testOutputHead(0, 1, "n2", testHeaderFlit("A", 1, 10, 0))
+	b := testOutputHead(1, 1, "n2", testHeaderFlit("B", 1, 10, 0))
+	c := testOutputHead(0, 1, "n3", testHeaderFlit("C", 1, 10, 0))
+	d := testOutputHead(1, 1, "n3", testHeaderFlit("D", 1, 10, 0))
+
+	// Granting input port 0 the output to n2 passes that output's turn to input port 1, but not n3's.
+	arbiter.granted(0, a)
+	assert.Equal(t, []string{"B", "C", "D"}, headPackets(arbiter.order(1, []bufferedHead{b, c, d})))
+	assert.Equal(t, []string{"C", "D"}, headPackets(arbiter.order(1, []bufferedHead{c, d})))
+
+	arbiter.granted(1, d)
+	assert.Equal(t, []string{"C", "D"}, headPackets(arbiter.order(2, []bufferedHead{c, d})))
+	assert.Equal(t, []string{"B", "A"}, headPackets(arbiter.order(2, []bufferedHead{a, b})))
+}
+
+func TestWeightedRoundRobinArbiter(t *testing.T) {
+	t.Parallel()
+
+	arbiter, err := newArbiter(domain.SimConfig{
+		MaxPriority:    2,
+		Arbiter:        domain.WeightedRoundRobinArbitration,
+		ArbiterWeights: []int{2, 1},
+	})
+	require.NoError(t, err)
+
+	high := testHead(1, 1, testHeaderFlit("H", 1, 10, 0))
+	low := testHead(0, 2, testHeaderFlit("L", 2, 10, 0))
+
+	granted := make([]string, 0, 6)
+	for cycle := 0; cycle < 6; cycle++ {
+		winner := arbiter.order(cycle, []bufferedHead{low, high})[0]
+		arbiter.granted(cycle, winner)
+		granted = append(granted, winner.flit.PacketIndex())
+	}
+
+	// Each round grants the high priority flit twice and the low priority flit once.
+	assert.Equal(t, 4, countOf(granted, "H"))
+	assert.Equal(t, 2, countOf(granted, "L"))
+}
+
+func countOf(vals []string, val string) int {
+	count := 0
+	for _, v := range vals {
+		if v == val {
+			count++
+		}
+	}
+	return count
+}
+
+func TestAgeArbiterBodyFlits(t *testing.T) {
+	t.Parallel()
+
+	arbiter, err := newArbiter(domain.SimConfig{MaxPriority: 3, Arbiter: domain.AgeArbitration})
+	require.NoError(t, err)
+
+	// The older packets' header flits have passed through the router, their body flits now compete with the header flit
+	// of a newer, higher priority packet.
+	arbiter.order(5, []bufferedHead{
+		testHead(0, 1, testHeaderFlit("A", 3, 50, 0)),
+		testHead(1, 1, testHeaderFlit("B", 2, 50, 4)),
+	})
+
+	heads := []bufferedHead{
+		testHead(0, 1, packet.NewBodyFlit("t", "B", 1, 2, zerolog.New(io.Discard))),
+		testHead(1, 1, testHeaderFlit("C", 1, 50, 8)),
+		testHead(2, 1, packet.NewBodyFlit("t", "A", 1, 3, zerolog.New(io.Discard))),
+		testHead(3, 1, testHeaderFlit("D", 1, 50, 2)),
+	}
+	assert.Equal(t, []string{"A", "D", "B", "C"}, headPackets(arbiter.order(10, heads)))
+}
+
+func TestAgeArbiterForgetsPackets(t *testing.T) {
+	t.Parallel()
+
+	arbiter := &ageArbiter{releases: make(map[string]int)}
+
+	arbiter.order(2, []bufferedHead{
+		testHead(0, 1, testHeaderFlit("A", 1, 30, 2)),
+		testHead(1, 1, testHeaderFlit("B", 1, 30, 3)),
+	})
+	require.Len(t, arbiter.releases, 2)
+
+	arbiter.granted(4, testHead(0, 1, packet.NewBodyFlit("t", "A", 1, 1, zerolog.New(io.Discard))))
+	require.Len(t, arbiter.releases, 2)
+
+	arbiter.granted(5, testHead(0, 1, packet.NewTailFlit("t", "A", 2, 1, zerolog.New(io.Discard))))
+	arbiter.dropped(packet.NewTailFlit("t", "B", 2, 1, zerolog.New(io.Discard)))
+	assert.Empty(t, arbiter.releases)
+}
+
+func TestEDFArbiterOrdersByGeneration(t *testing.T) {
+	t.Parallel()
+
+	arbiter := &edfArbiter{deadlines: make(map[string]int)}
+
+	// A's release was jittered after B's, but its period started first, so its deadline is earlier.
+	a := testGeneratedHeaderFlit("A", 2, 30, 0, 10)
+	b := testHeaderFlit("B", 1, 30, 5)
+
+	heads := arbiter.order(10, []bufferedHead{testHead(0, 1, b), testHead(1, 1, a)})
+	assert.Equal(t, []string{"A", "B"}, headPackets(heads))
+}
+
+func TestEDFArbiterForgetsDroppedPackets(t *testing.T) {
+	t.Parallel()
+
+	arbiter := &edfArbiter{deadlines: make(map[string]int)}
+
+	arbiter.order(2, []bufferedHead{testHead(0, 1, testHeaderFlit("A", 1, 30, 2))})
+	require.Len(t, arbiter.deadlines, 1)
+
+	arbiter.dropped(packet.NewTailFlit("t", "A", 1, 1, zerolog.New(io.Discard)))
+	assert.Empty(t, arbiter.deadlines)
+}
+
+func TestEDFArbiterForgetsGrantedPackets(t *testing.T) {
+	t.Parallel()
+
+	arbiter := &edfArbiter{deadlines: make(map[string]int)}
+
+	header := testHead(0, 1, testHeaderFlit("A", 1, 30, 2))
+	arbiter.order(2, []bufferedHead{header})
+	assert.Equal(t, 32, arbiter.deadlines[header.flit.PacketID()])
+
+	tail := testHead(0, 1, packet.NewTailFlit("t", "A", 1, 1, zerolog.New(io.Discard)))
+	arbiter.granted(3, tail)
+	assert.NotContains(t, arbiter.deadlines, header.flit.PacketID())
+}
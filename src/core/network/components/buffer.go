@@ -1,6 +1,9 @@
 package components
 
 import (
+	"errors"
+	"fmt"
+
 	"main/src/domain"
 	"main/src/traffic/packet"
 
@@ -9,19 +12,35 @@ import (
 
 type buffer interface {
 	totalCapacity() int
-	vChanCapacity() int
-	peakFlit(priority int) (packet.Flit, bool)
-	popFlit(priority int) (packet.Flit, bool)
+	vChanCount() int
+	vChan(priority int) int
+	vChanCapacity(vChan int) int
+	vChanOccupancy(vChan int) int
+	peakFlit(vChan int) (packet.Flit, bool)
+	popFlit(vChan int) (packet.Flit, bool)
 	addFlit(flit packet.Flit) error
 }
 
 type bufferImpl struct {
 	bufferCap int
-	vChanCap  int
+	vChans    vChanMap
+	vChanCaps map[int]int
 	flits     map[int][]packet.Flit
 	logger    zerolog.Logger
 }
 
+// Maps each priority to the virtual channel its flits use, priority p at index p - 1. A nil map gives every priority
+// its own virtual channel.
+type vChanMap []int
+
+func (m vChanMap) vChan(priority int) int {
+	if priority < 1 || priority > len(m) {
+		return priority
+	}
+	return m[priority-1]
+}
+
+// Creates a buffer with its capacity split evenly across the priorities' virtual channels.
 func newBuffer(capacity, maxPriority int, logger zerolog.Logger) (*bufferImpl, error) {
 	if err := validBufferSize(capacity, maxPriority); err != nil {
 		logger.Error().Err(err).Msg("invalid buffer size")
@@ -34,9 +53,32 @@ func newBuffer(capacity, maxPriority int, logger zerolog.Logger) (*bufferImpl, e
 		return nil, err
 	}
 
+	vChanDepths := make([]int, maxPriority)
+	for i := range vChanDepths {
+		vChanDepths[i] = vChanCap
+	}
+
+	return newPartitionedBuffer(vChanDepths, nil, logger)
+}
+
+// Creates a buffer whose virtual channels have the given depths, with flits placed in virtual channels by vChans.
+func newPartitionedBuffer(vChanDepths []int, vChans vChanMap, logger zerolog.Logger) (*bufferImpl, error) {
+	if err := validVChanDepths(vChanDepths); err != nil {
+		logger.Error().Err(err).Ints("vc_depths", vChanDepths).Msg("invalid virtual channel depths")
+		return nil, err
+	}
+
+	bufferCap := 0
+	vChanCaps := make(map[int]int, len(vChanDepths))
+	for i, depth := range vChanDepths {
+		vChanCaps[i+1] = depth
+		bufferCap += depth
+	}
+
 	return &bufferImpl{
-		bufferCap: capacity,
-		vChanCap:  vChanCap,
+		bufferCap: bufferCap,
+		vChans:    vChans,
+		vChanCaps: vChanCaps,
 		flits:     make(map[int][]packet.Flit),
 		logger:    logger.With().Logger(),
 	}, nil
@@ -46,40 +88,60 @@ func (b *bufferImpl) totalCapacity() int {
 	return b.bufferCap
 }
 
-func (b *bufferImpl) vChanCapacity() int {
-	return b.vChanCap
+func (b *bufferImpl) vChanCount() int {
+	return len(b.vChanCaps)
+}
+
+// Returns the virtual channel holding the priority's flits.
+func (b *bufferImpl) vChan(priority int) int {
+	return b.vChans.vChan(priority)
+}
+
+func (b *bufferImpl) vChanCapacity(vChan int) int {
+	return b.vChanCaps[vChan]
+}
+
+func (b *bufferImpl) vChanOccupancy(vChan int) int {
+	return len(b.flits[vChan])
 }
 
-func (b *bufferImpl) peakFlit(priority int) (packet.Flit, bool) {
-	if len(b.flits[priority]) == 0 {
+func (b *bufferImpl) peakFlit(vChan int) (packet.Flit, bool) {
+	if len(b.flits[vChan]) == 0 {
 		return nil, false
 	}
 
-	return b.flits[priority][0], true
+	return b.flits[vChan][0], true
 }
 
-func (b *bufferImpl) popFlit(priority int) (packet.Flit, bool) {
-	flit, exists := b.peakFlit(priority)
+func (b *bufferImpl) popFlit(vChan int) (packet.Flit, bool) {
+	flit, exists := b.peakFlit(vChan)
 	if !exists {
 		return nil, false
 	} else {
-		if len(b.flits[priority]) > 1 {
-			b.flits[priority] = b.flits[priority][1:]
+		if len(b.flits[vChan]) > 1 {
+			b.flits[vChan] = b.flits[vChan][1:]
 		} else {
-			b.flits[priority] = b.flits[priority][:0]
+			b.flits[vChan] = b.flits[vChan][:0]
 		}
 		return flit, true
 	}
 }
 
+// Adds the flit to its priority's virtual channel, ahead of any lower priority flits already waiting in it.
 func (b *bufferImpl) addFlit(flit packet.Flit) error {
-	if len(b.flits[flit.Priority()]) < b.vChanCap {
-		b.flits[flit.Priority()] = append(b.flits[flit.Priority()], flit)
-
-		return nil
-	} else {
+	vChan := b.vChan(flit.Priority())
+	if len(b.flits[vChan]) >= b.vChanCaps[vChan] {
 		return domain.ErrBufferNoCapacity
 	}
+
+	flits := b.flits[vChan]
+	i := len(flits)
+	for i > 0 && flits[i-1].Priority() > flit.Priority() {
+		i--
+	}
+	b.flits[vChan] = append(flits[:i], append([]packet.Flit{flit}, flits[i:]...)...)
+
+	return nil
 }
 
 func validBufferSize(capacity, maxPriority int) error {
@@ -103,3 +165,28 @@ func bufferVChanCapacity(capacity, maxPriority int) (int, error) {
 
 	return capacity / maxPriority, nil
 }
+
+func validVChanDepths(vChanDepths []int) error {
+	if len(vChanDepths) < 1 {
+		return domain.ErrInvalidParameter
+	}
+
+	for _, depth := range vChanDepths {
+		if depth < 1 {
+			return domain.ErrInvalidParameter
+		}
+	}
+
+	return nil
+}
+
+// Returns the depths of the virtual channels of a buffer of capacity flits under the simulation configuration.
+func bufferVChanDepths(conf domain.SimConfig, capacity int) ([]int, error) {
+	vChanDepths := conf.VChanDepths(capacity)
+	if vChanDepths == nil && len(conf.VCDepths) > 0 {
+		return nil, errors.Join(domain.ErrInvalidParameter, fmt.Errorf("buffer size %d differs from the total of vc_depths %v", capacity, conf.VCDepths))
+	} else if vChanDepths == nil {
+		return nil, errors.Join(domain.ErrInvalidParameter, fmt.Errorf("buffer size %d can not be split across %d virtual channels", capacity, conf.VCCount()))
+	}
+	return vChanDepths, nil
+}
@@ -30,7 +30,9 @@ func TestNewBuffer(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, capacity, buff.bufferCap)
-		assert.Equal(t, capacity/maxPriority, buff.vChanCap)
+		for p := 1; p <= maxPriority; p++ {
+			assert.Equal(t, capacity/maxPriority, buff.vChanCapacity(p))
+		}
 		assert.NotNil(t, buff.flits)
 	})
 
@@ -178,3 +180,79 @@ func TestBufferVChanCapacity(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPartitionedBuffer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		buff, err := newPartitionedBuffer([]int{3, 1}, nil, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		assert.Equal(t, 4, buff.totalCapacity())
+		assert.Equal(t, 3, buff.vChanCapacity(1))
+		assert.Equal(t, 1, buff.vChanCapacity(2))
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, buff.addFlit(packet.NewHeaderFlit("t", "AA", i, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))))
+		}
+		require.ErrorIs(t, buff.addFlit(packet.NewHeaderFlit("t", "AB", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))), domain.ErrBufferNoCapacity)
+
+		require.NoError(t, buff.addFlit(packet.NewHeaderFlit("t", "AC", 0, 2, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))))
+		require.ErrorIs(t, buff.addFlit(packet.NewHeaderFlit("t", "AD", 0, 2, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))), domain.ErrBufferNoCapacity)
+	})
+
+	t.Run("SharedVChan", func(t *testing.T) {
+		buff, err := newPartitionedBuffer([]int{4}, vChanMap{1, 1}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		assert.Equal(t, 1, buff.vChanCount())
+		assert.Equal(t, 1, buff.vChan(2))
+
+		low := packet.NewHeaderFlit("t", "AA", 0, 2, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+		high := packet.NewHeaderFlit("t", "AB", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+		require.NoError(t, buff.addFlit(low))
+		require.NoError(t, buff.addFlit(high))
+		assert.Equal(t, 2, buff.vChanOccupancy(1))
+
+		gotFlit, exists := buff.popFlit(1)
+		assert.True(t, exists)
+		assert.Equal(t, high, gotFlit)
+		gotFlit, exists = buff.popFlit(1)
+		assert.True(t, exists)
+		assert.Equal(t, low, gotFlit)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := newPartitionedBuffer([]int{2, 0}, nil, zerolog.New(io.Discard))
+		require.ErrorIs(t, err, domain.ErrInvalidParameter)
+
+		_, err = newPartitionedBuffer(nil, nil, zerolog.New(io.Discard))
+		require.ErrorIs(t, err, domain.ErrInvalidParameter)
+	})
+}
+
+func TestBufferVChanDepths(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		conf     domain.SimConfig
+		capacity int
+		expected []int
+		err      error
+	}
+
+	testCases := []testCase{
+		{domain.SimConfig{MaxPriority: 2}, 4, []int{2, 2}, nil},
+		{domain.SimConfig{MaxPriority: 2}, 3, nil, domain.ErrInvalidParameter},
+		{domain.SimConfig{MaxPriority: 3, VCDepths: []int{4, 2, 1}}, 7, []int{4, 2, 1}, nil},
+		{domain.SimConfig{MaxPriority: 3, VCDepths: []int{4, 2, 1}}, 9, nil, domain.ErrInvalidParameter},
+		{domain.SimConfig{MaxPriority: 3, VCDepths: []int{4, 2, 1}}, 8, nil, domain.ErrInvalidParameter},
+		{domain.SimConfig{MaxPriority: 4, NumVCs: 2}, 6, []int{3, 3}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			val, err := bufferVChanDepths(tc.conf, tc.capacity)
+			require.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, val)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package components
 
 import (
+	"errors"
+
+	"main/src/domain"
 	"main/src/traffic/packet"
 
 	"github.com/rs/zerolog"
@@ -9,7 +12,28 @@ import (
 type Connection interface {
 	flitChannel() chan packet.Flit
 	creditChannels() map[int]chan int
-	creditChannel(priority int) chan int
+	creditChannel(vChan int) chan int
+	bufferSize() int
+	acceptsFlit(cycle int) bool
+	pushFlit(cycle int, flit packet.Flit)
+	receiveFlit(cycle int) (packet.Flit, bool)
+	pushCredit(cycle, vChan int)
+	receiveCredits(cycle int, credits map[int]int)
+	pushSignal(cycle int, signal flowSignal)
+	receiveSignals(cycle int) []flowSignal
+	flowControlSlack() int
+	recordFlit(cycle int, flit packet.Flit)
+	recordRetransmission()
+	injectFaults(faults *FaultInjector, link domain.Link)
+	down() (int, bool)
+	dropFlit(flit packet.Flit) bool
+	recordFaultAffected(packetID string, fault int)
+
+	FlitCount() int
+	Retransmissions() int
+	Bandwidth() float64
+	EnableTraversalLog()
+	Traversals() []domain.LinkTraversal
 
 	GetDstRouter() string
 	SetDstRouter(nodeID string)
@@ -23,20 +47,89 @@ type connectionImpl struct {
 	creditChan map[int]chan int
 	destRouter string
 	srcRouter  string
+	flitCount  int
 	logger     zerolog.Logger
+
+	// Flits resent over the connection under ack/nack flow control, included in flitCount.
+	retransmissions int
+
+	// Flits and credits in flight over a multi-cycle link, in the order they were sent. Single cycle links pass flits
+	// and credits straight through the channels.
+	latency        int
+	creditLatency  int
+	flitPipeline   []pipelinedFlit
+	creditPipeline []pipelinedCredit
+	// On/off & ack/nack flow control signals returning to the upstream component, which take the credit latency to
+	// cross the link.
+	signalPipeline []pipelinedSignal
+
+	// Size of the downstream buffer fed by the connection, the downstream router's buffer size when 0.
+	buffSize int
+
+	// Link bandwidth, either several flits every cycle or a single flit every several cycles.
+	bandwidth     float64
+	flitsPerCycle int
+	cyclesPerFlit int
+	lastSentCycle int
+	sentThisCycle int
+
+	traversalLogEnabled bool
+	traversals          []domain.LinkTraversal
+
+	faults *FaultInjector
+	link   domain.Link
+}
+
+type pipelinedFlit struct {
+	flit      packet.Flit
+	sentCycle int
 }
 
-func NewConnection(maxPriority int, logger zerolog.Logger) (*connectionImpl, error) {
-	creditChan := make(map[int]chan int, maxPriority)
-	for i := 1; i <= maxPriority; i++ {
+type pipelinedCredit struct {
+	vChan     int
+	sentCycle int
+}
+
+type pipelinedSignal struct {
+	signal    flowSignal
+	sentCycle int
+}
+
+// Creates a connection with single cycle flit and credit latencies.
+func NewConnection(numVChans int, logger zerolog.Logger) (*connectionImpl, error) {
+	return NewLinkConnection(numVChans, domain.LinkConfig{Latency: 1, CreditLatency: 1, Bandwidth: 1}, logger)
+}
+
+// Creates a connection whose flits take link.Latency cycles to cross it and whose credits take link.CreditLatency
+// cycles to return across it, carrying at most link.Bandwidth flits per cycle.
+func NewLinkConnection(numVChans int, link domain.LinkConfig, logger zerolog.Logger) (*connectionImpl, error) {
+	if link.Latency < 1 || link.CreditLatency < 1 {
+		return nil, errors.Join(domain.ErrInvalidParameter, errors.New("connection latency less than 1"))
+	}
+	if !domain.ValidBandwidth(link.Bandwidth) {
+		return nil, errors.Join(domain.ErrInvalidParameter, errors.New("connection bandwidth must be a whole number of flits per cycle or of cycles per flit"))
+	}
+
+	creditChan := make(map[int]chan int, numVChans)
+	for i := 1; i <= numVChans; i++ {
 		creditChan[i] = make(chan int, 1)
 	}
 
-	logger.Trace().Int("credit_channels", maxPriority).Msg("new connection")
+	logger.Trace().
+		Int("credit_channels", numVChans).Int("latency", link.Latency).Int("credit_latency", link.CreditLatency).
+		Float64("bandwidth", link.Bandwidth).
+		Msg("new connection")
 	return &connectionImpl{
-		flitChan:   make(chan packet.Flit, 1),
-		creditChan: creditChan,
-		logger:     logger.With().Logger(),
+		flitChan:      make(chan packet.Flit, link.FlitsPerCycle()),
+		creditChan:    creditChan,
+		logger:        logger.With().Logger(),
+		latency:       link.Latency,
+		creditLatency: link.CreditLatency,
+		buffSize:      link.BufferSize,
+		bandwidth:     link.Bandwidth,
+		flitsPerCycle: link.FlitsPerCycle(),
+		cyclesPerFlit: link.CyclesPerFlit(),
+		lastSentCycle: -link.CyclesPerFlit(),
 	}, nil
 }
 
@@ -48,12 +141,180 @@ func (c *connectionImpl) creditChannels() map[int]chan int {
 	return c.creditChan
 }
 
-func (c *connectionImpl) creditChannel(priority int) chan int {
-	if _, exists := c.creditChan[priority]; !exists {
-		c.creditChan[priority] = make(chan int, 1)
+func (c *connectionImpl) creditChannel(vChan int) chan int {
+	if _, exists := c.creditChan[vChan]; !exists {
+		c.creditChan[vChan] = make(chan int, 1)
+	}
+
+	return c.creditChan[vChan]
+}
+
+func (c *connectionImpl) bufferSize() int {
+	return c.buffSize
+}
+
+// Reports whether a flit can be sent over the connection on the cycle without exceeding the link's bandwidth.
+func (c *connectionImpl) acceptsFlit(cycle int) bool {
+	if len(c.flitChan) == cap(c.flitChan) {
+		return false
+	}
+	if cycle == c.lastSentCycle {
+		return c.sentThisCycle < c.flitsPerCycle
+	}
+	return cycle-c.lastSentCycle >= c.cyclesPerFlit
+}
+
+// Sends the flit over the connection, it can be received latency - 1 cycles after the cycle it was sent, i.e. a single
+// cycle link delivers the flit on the cycle it was sent.
+func (c *connectionImpl) pushFlit(cycle int, flit packet.Flit) {
+	if cycle == c.lastSentCycle {
+		c.sentThisCycle++
+	} else {
+		c.lastSentCycle = cycle
+		c.sentThisCycle = 1
+	}
+
+	if c.latency <= 1 {
+		c.flitChan <- flit
+		return
+	}
+	c.flitPipeline = append(c.flitPipeline, pipelinedFlit{flit: flit, sentCycle: cycle})
+}
+
+// Returns the next flit to have crossed the connection by the cycle, if any.
+func (c *connectionImpl) receiveFlit(cycle int) (packet.Flit, bool) {
+	if len(c.flitChan) > 0 {
+		return <-c.flitChan, true
+	}
+
+	if len(c.flitPipeline) > 0 && c.flitPipeline[0].sentCycle+c.latency-1 <= cycle {
+		flit := c.flitPipeline[0].flit
+		c.flitPipeline = c.flitPipeline[1:]
+		return flit, true
+	}
+
+	return nil, false
+}
+
+// Returns a credit for the virtual channel to the upstream component, it can be collected creditLatency
+// cycles after the cycle it was returned. Credits on a single cycle link are merged with any credit not yet collected
+// so the send never blocks.
+func (c *connectionImpl) pushCredit(cycle, vChan int) {
+	if c.creditLatency <= 1 {
+		creditChan := c.creditChannel(vChan)
+
+		select {
+		case creditChan <- 1:
+		default:
+			creditChan <- (<-creditChan) + 1
+		}
+		return
+	}
+	c.creditPipeline = append(c.creditPipeline, pipelinedCredit{vChan: vChan, sentCycle: cycle})
+}
+
+// Adds the credits which have crossed the connection by the cycle to credits, by virtual channel.
+func (c *connectionImpl) receiveCredits(cycle int, credits map[int]int) {
+	for vChan, creditChan := range c.creditChan {
+		for len(creditChan) > 0 {
+			credits[vChan] += <-creditChan
+		}
+	}
+
+	i := 0
+	for ; i < len(c.creditPipeline) && c.creditPipeline[i].sentCycle+c.creditLatency <= cycle; i++ {
+		credits[c.creditPipeline[i].vChan]++
+	}
+	c.creditPipeline = c.creditPipeline[i:]
+}
+
+// Returns a flow control signal to the upstream component, it can be collected creditLatency cycles after the cycle it
+// was returned, and at the earliest the following cycle.
+func (c *connectionImpl) pushSignal(cycle int, signal flowSignal) {
+	c.signalPipeline = append(c.signalPipeline, pipelinedSignal{signal: signal, sentCycle: cycle})
+}
+
+// Returns the flow control signals which have crossed the connection by the cycle, in the order they were returned.
+func (c *connectionImpl) receiveSignals(cycle int) []flowSignal {
+	signals := make([]flowSignal, 0)
+
+	i := 0
+	for ; i < len(c.signalPipeline) && c.signalPipeline[i].sentCycle+max(c.creditLatency, 1) <= cycle; i++ {
+		signals = append(signals, c.signalPipeline[i].signal)
+	}
+	c.signalPipeline = c.signalPipeline[i:]
+
+	return signals
+}
+
+// Returns the number of flits which may still arrive over the connection after the flit which made the downstream
+// component return a signal to stop sending, those arriving alongside it in the same cycle, those sent before the
+// upstream component collects the signal and those already crossing the link.
+func (c *connectionImpl) flowControlSlack() int {
+	return (max(c.creditLatency, 1)+max(c.latency, 1)-2)*c.flitsPerCycle + c.flitsPerCycle - 1
+}
+
+// Counts the flit sent over the connection, and logs its traversal if the traversal log is enabled.
+func (c *connectionImpl) recordFlit(cycle int, flit packet.Flit) {
+	c.flitCount++
+
+	if c.traversalLogEnabled {
+		c.traversals = append(c.traversals, domain.LinkTraversal{
+			Cycle:         cycle,
+			TrafficFlowID: flit.TrafficFlowID(),
+			PacketID:      flit.PacketID(),
+			FlitType:      flit.Type().String(),
+		})
 	}
+}
+
+// Returns the total number of flits sent over the connection.
+func (c *connectionImpl) FlitCount() int {
+	return c.flitCount
+}
+
+// Counts a flit resent over the connection, which must also be recorded by recordFlit.
+func (c *connectionImpl) recordRetransmission() {
+	c.retransmissions++
+}
+
+// Returns the number of flits resent over the connection under ack/nack flow control.
+func (c *connectionImpl) Retransmissions() int {
+	return c.retransmissions
+}
+
+// Returns the maximum number of flits the connection carries per cycle.
+func (c *connectionImpl) Bandwidth() float64 {
+	return c.bandwidth
+}
+
+func (c *connectionImpl) injectFaults(faults *FaultInjector, link domain.Link) {
+	c.faults = faults
+	c.link = link
+}
+
+// Returns the index of the link down fault active on the connection, if any.
+func (c *connectionImpl) down() (int, bool) {
+	return c.faults.linkDown(c.link)
+}
+
+// Applies any flit fault matching the flit received over the connection, returning true if the flit is dropped.
+func (c *connectionImpl) dropFlit(flit packet.Flit) bool {
+	return c.faults.receiveFlit(c.link, flit)
+}
+
+func (c *connectionImpl) recordFaultAffected(packetID string, fault int) {
+	c.faults.RecordAffected(packetID, fault)
+}
+
+// Enables logging of every flit sent over the connection, the log grows with the number of flits sent.
+func (c *connectionImpl) EnableTraversalLog() {
+	c.traversalLogEnabled = true
+}
 
-	return c.creditChan[priority]
+// Returns the flits sent over the connection in the order they were sent, empty unless the traversal log is enabled.
+func (c *connectionImpl) Traversals() []domain.LinkTraversal {
+	return c.traversals
 }
 
 func (c *connectionImpl) GetDstRouter() string {
@@ -4,6 +4,9 @@ import (
 	"io"
 	"testing"
 
+	"main/src/domain"
+	"main/src/traffic/packet"
+
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,6 +31,190 @@ func TestNewConnection(t *testing.T) {
 	})
 }
 
+func TestNewLinkConnection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		conn, err := NewLinkConnection(1, domain.LinkConfig{Latency: 3, CreditLatency: 2, Bandwidth: 1}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		assert.Equal(t, 3, conn.latency)
+		assert.Equal(t, 2, conn.creditLatency)
+	})
+
+	t.Run("InvalidLatency", func(t *testing.T) {
+		_, err := NewLinkConnection(1, domain.LinkConfig{Latency: 0, CreditLatency: 1}, zerolog.New(io.Discard))
+		assert.ErrorIs(t, err, domain.ErrInvalidParameter)
+	})
+
+	t.Run("InvalidCreditLatency", func(t *testing.T) {
+		_, err := NewLinkConnection(1, domain.LinkConfig{Latency: 1, CreditLatency: 0, Bandwidth: 1}, zerolog.New(io.Discard))
+		assert.ErrorIs(t, err, domain.ErrInvalidParameter)
+	})
+
+	t.Run("InvalidBandwidth", func(t *testing.T) {
+		_, err := NewLinkConnection(1, domain.LinkConfig{Latency: 1, CreditLatency: 1, Bandwidth: 0.4}, zerolog.New(io.Discard))
+		assert.ErrorIs(t, err, domain.ErrInvalidParameter)
+	})
+}
+
+func TestConnectionBandwidth(t *testing.T) {
+	t.Parallel()
+
+	flit := packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+
+	t.Run("Wide", func(t *testing.T) {
+		conn, err := NewLinkConnection(1, domain.LinkConfig{Latency: 1, CreditLatency: 1, Bandwidth: 2}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.pushFlit(5, flit)
+		require.True(t, conn.acceptsFlit(5))
+		conn.pushFlit(5, flit)
+		assert.False(t, conn.acceptsFlit(5))
+
+		for i := 0; i < 2; i++ {
+			_, ok := conn.receiveFlit(5)
+			assert.True(t, ok)
+		}
+		assert.False(t, conn.acceptsFlit(5))
+		assert.True(t, conn.acceptsFlit(6))
+	})
+
+	t.Run("Narrow", func(t *testing.T) {
+		conn, err := NewLinkConnection(1, domain.LinkConfig{Latency: 2, CreditLatency: 1, Bandwidth: 1.0 / 3}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		require.True(t, conn.acceptsFlit(0))
+		conn.pushFlit(0, flit)
+		assert.False(t, conn.acceptsFlit(1))
+		assert.False(t, conn.acceptsFlit(2))
+		assert.True(t, conn.acceptsFlit(3))
+	})
+}
+
+func TestConnectionFlitLatency(t *testing.T) {
+	t.Parallel()
+
+	flitA := packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+	flitB := packet.NewHeaderFlit("t", "AB", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+
+	t.Run("SingleCycle", func(t *testing.T) {
+		conn, err := NewConnection(1, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		require.True(t, conn.acceptsFlit(5))
+		conn.pushFlit(5, flitA)
+		assert.False(t, conn.acceptsFlit(5))
+
+		flit, ok := conn.receiveFlit(5)
+		require.True(t, ok)
+		assert.Equal(t, flitA, flit)
+		assert.True(t, conn.acceptsFlit(6))
+	})
+
+	t.Run("MultiCycle", func(t *testing.T) {
+		conn, err := NewLinkConnection(1, domain.LinkConfig{Latency: 3, CreditLatency: 1, Bandwidth: 1}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.pushFlit(5, flitA)
+		assert.False(t, conn.acceptsFlit(5), "one flit per cycle")
+		require.True(t, conn.acceptsFlit(6))
+		conn.pushFlit(6, flitB)
+
+		_, ok := conn.receiveFlit(6)
+		assert.False(t, ok)
+
+		flit, ok := conn.receiveFlit(7)
+		require.True(t, ok)
+		assert.Equal(t, flitA, flit)
+		_, ok = conn.receiveFlit(7)
+		assert.False(t, ok)
+
+		flit, ok = conn.receiveFlit(8)
+		require.True(t, ok)
+		assert.Equal(t, flitB, flit)
+	})
+}
+
+func TestConnectionCreditLatency(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SingleCycle", func(t *testing.T) {
+		conn, err := NewConnection(2, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.pushCredit(5, 1)
+		conn.pushCredit(5, 1)
+		conn.pushCredit(5, 2)
+
+		credits := make(map[int]int)
+		conn.receiveCredits(6, credits)
+		assert.Equal(t, map[int]int{1: 2, 2: 1}, credits)
+	})
+
+	t.Run("MultiCycle", func(t *testing.T) {
+		conn, err := NewLinkConnection(2, domain.LinkConfig{Latency: 1, CreditLatency: 3, Bandwidth: 1}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.pushCredit(5, 1)
+		conn.pushCredit(6, 2)
+
+		credits := make(map[int]int)
+		conn.receiveCredits(7, credits)
+		assert.Empty(t, credits)
+
+		conn.receiveCredits(8, credits)
+		assert.Equal(t, map[int]int{1: 1}, credits)
+
+		conn.receiveCredits(9, credits)
+		assert.Equal(t, map[int]int{1: 1, 2: 1}, credits)
+	})
+}
+
+func TestConnectionSignals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SingleCycle", func(t *testing.T) {
+		conn, err := NewConnection(2, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.pushSignal(5, flowSignal{vChan: 1, kind: signalOff})
+		conn.pushSignal(5, flowSignal{vChan: 1, kind: signalOn})
+
+		assert.Empty(t, conn.receiveSignals(5))
+		assert.Equal(t, []flowSignal{{vChan: 1, kind: signalOff}, {vChan: 1, kind: signalOn}}, conn.receiveSignals(6))
+		assert.Empty(t, conn.receiveSignals(7))
+	})
+
+	t.Run("MultiCycle", func(t *testing.T) {
+		conn, err := NewLinkConnection(2, domain.LinkConfig{Latency: 1, CreditLatency: 3, Bandwidth: 1}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.pushSignal(5, flowSignal{vChan: 1, kind: signalAck, flitID: "a"})
+		conn.pushSignal(6, flowSignal{vChan: 2, kind: signalNack, flitID: "b"})
+
+		assert.Empty(t, conn.receiveSignals(7))
+		assert.Equal(t, []flowSignal{{vChan: 1, kind: signalAck, flitID: "a"}}, conn.receiveSignals(8))
+		assert.Equal(t, []flowSignal{{vChan: 2, kind: signalNack, flitID: "b"}}, conn.receiveSignals(9))
+	})
+}
+
+func TestConnectionFlowControlSlack(t *testing.T) {
+	t.Parallel()
+
+	conn, err := NewConnection(1, zerolog.New(io.Discard))
+	require.NoError(t, err)
+	assert.Equal(t, 0, conn.flowControlSlack())
+
+	conn, err = NewLinkConnection(1, domain.LinkConfig{Latency: 2, CreditLatency: 3, Bandwidth: 2}, zerolog.New(io.Discard))
+	require.NoError(t, err)
+	assert.Equal(t, 7, conn.flowControlSlack())
+
+	// Flits arriving alongside the one which signals off in a single cycle link's cycle.
+	conn, err = NewLinkConnection(1, domain.LinkConfig{Latency: 1, CreditLatency: 1, Bandwidth: 3}, zerolog.New(io.Discard))
+	require.NoError(t, err)
+	assert.Equal(t, 2, conn.flowControlSlack())
+}
+
 func TestConnectionFlitChannel(t *testing.T) {
 	t.Parallel()
 
@@ -103,3 +290,32 @@ func TestConnectionSetSrcRouterID(t *testing.T) {
 	conn.SetSrcRouter(nodeID)
 	assert.Equal(t, nodeID, conn.srcRouter)
 }
+
+func TestConnectionRecordFlit(t *testing.T) {
+	t.Parallel()
+
+	flit := packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+
+	t.Run("TraversalLogDisabled", func(t *testing.T) {
+		conn, err := NewConnection(1, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.recordFlit(3, flit)
+
+		assert.Equal(t, 1, conn.FlitCount())
+		assert.Empty(t, conn.Traversals())
+	})
+
+	t.Run("TraversalLogEnabled", func(t *testing.T) {
+		conn, err := NewConnection(1, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn.EnableTraversalLog()
+		conn.recordFlit(3, flit)
+
+		assert.Equal(t, 1, conn.FlitCount())
+		assert.Equal(t, []domain.LinkTraversal{
+			{Cycle: 3, TrafficFlowID: "t", PacketID: flit.PacketID(), FlitType: "header"},
+		}, conn.Traversals())
+	})
+}
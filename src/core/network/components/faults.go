@@ -0,0 +1,176 @@
+package components
+
+import (
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+)
+
+// Applies a fault schedule to the network's components and records which packets each fault affected. A nil
+// FaultInjector injects no faults, so components need not check whether a schedule was provided.
+type FaultInjector struct {
+	faults []domain.FaultConfig
+	cycle  int
+
+	// Number of cycles link and router faults were active, and flits flit faults were applied to.
+	activations []int
+	// Flit faults applied at most once.
+	applied map[int]bool
+	// Packets, by ID, whose remaining flits are dropped on a link following their header flit being dropped there.
+	discarding map[domain.Link]map[string]int
+	// Indexes of the faults which affected each packet, by packet ID.
+	affected map[string]map[int]bool
+
+	logger zerolog.Logger
+}
+
+func NewFaultInjector(faults []domain.FaultConfig, logger zerolog.Logger) (*FaultInjector, error) {
+	for i := 0; i < len(faults); i++ {
+		if err := faults[i].Validate(); err != nil {
+			logger.Error().Err(err).Int("fault", i).Msg("invalid fault")
+			return nil, err
+		}
+	}
+
+	return &FaultInjector{
+		faults:      faults,
+		activations: make([]int, len(faults)),
+		applied:     make(map[int]bool),
+		discarding:  make(map[domain.Link]map[string]int),
+		affected:    make(map[string]map[int]bool),
+		logger:      logger.With().Str("component", "fault_injector").Logger(),
+	}, nil
+}
+
+// Sets the cycle faults are applied for, and counts the cycle against each active link and router fault.
+func (f *FaultInjector) Advance(cycle int) {
+	if f == nil {
+		return
+	}
+
+	f.cycle = cycle
+	for i := 0; i < len(f.faults); i++ {
+		if (f.faults[i].Type == domain.LinkDownFault || f.faults[i].Type == domain.RouterStallFault) && f.faults[i].Active(cycle) {
+			f.activations[i]++
+		}
+	}
+}
+
+func (f *FaultInjector) linkDown(link domain.Link) (int, bool) {
+	if f == nil {
+		return -1, false
+	}
+
+	for i := 0; i < len(f.faults); i++ {
+		if f.faults[i].Type == domain.LinkDownFault && f.faults[i].Link() == link && f.faults[i].Active(f.cycle) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (f *FaultInjector) routerStalled(nodeID string) (int, bool) {
+	if f == nil {
+		return -1, false
+	}
+
+	for i := 0; i < len(f.faults); i++ {
+		if f.faults[i].Type == domain.RouterStallFault && f.faults[i].Src == nodeID && f.faults[i].Active(f.cycle) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Applies any matching flit fault to the flit received over the link, returning true if the flit is dropped.
+func (f *FaultInjector) receiveFlit(link domain.Link, flit packet.Flit) bool {
+	if f == nil {
+		return false
+	}
+
+	if fault, exists := f.discarding[link][flit.PacketID()]; exists {
+		f.logger.Debug().Int("cycle", f.cycle).Str("flit", flit.ID()).Msg("dropped flit of packet with dropped header flit")
+		if flit.Type() == packet.TailFlitType {
+			delete(f.discarding[link], flit.PacketID())
+		}
+		f.activations[fault]++
+		return true
+	}
+
+	for i := 0; i < len(f.faults); i++ {
+		fault := f.faults[i]
+		if fault.FlitID != flit.ID() || f.applied[i] || !fault.Active(f.cycle) {
+			continue
+		}
+		if fault.Src != "" && fault.Link() != link {
+			continue
+		}
+
+		f.applied[i] = true
+		f.activations[i]++
+		f.RecordAffected(flit.PacketID(), i)
+
+		switch fault.Type {
+		case domain.FlitDropFault:
+			f.logger.Debug().Int("cycle", f.cycle).Str("flit", flit.ID()).Msg("dropped flit")
+			if flit.Type() == packet.HeaderFlitType {
+				if _, exists := f.discarding[link]; !exists {
+					f.discarding[link] = make(map[string]int)
+				}
+				f.discarding[link][flit.PacketID()] = i
+			}
+			return true
+		case domain.FlitCorruptFault:
+			f.logger.Debug().Int("cycle", f.cycle).Str("flit", flit.ID()).Msg("corrupted flit")
+			flit.Corrupt()
+		}
+	}
+
+	return false
+}
+
+func (f *FaultInjector) RecordAffected(packetID string, fault int) {
+	if f == nil || fault < 0 {
+		return
+	}
+
+	if _, exists := f.affected[packetID]; !exists {
+		f.affected[packetID] = make(map[int]bool)
+	}
+	f.affected[packetID][fault] = true
+}
+
+func (f *FaultInjector) Faults() []domain.FaultConfig {
+	if f == nil {
+		return nil
+	}
+	return f.faults
+}
+
+// Returns the number of cycles each link and router fault was active, and the number of flits each flit fault was
+// applied to.
+func (f *FaultInjector) Activations() []int {
+	if f == nil {
+		return nil
+	}
+	return f.activations
+}
+
+// Returns the indexes of the faults which affected each packet, by packet ID.
+func (f *FaultInjector) AffectedPackets() map[string][]int {
+	if f == nil {
+		return nil
+	}
+
+	affected := make(map[string][]int, len(f.affected))
+	for packetID, faults := range f.affected {
+		for i := 0; i < len(f.faults); i++ {
+			if faults[i] {
+				affected[packetID] = append(affected[packetID], i)
+			}
+		}
+	}
+
+	return affected
+}
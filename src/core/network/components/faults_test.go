@@ -0,0 +1,117 @@
+package components
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFaultInjector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		injector, err := NewFaultInjector([]domain.FaultConfig{{Type: domain.RouterStallFault, Src: "n1"}}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		assert.Len(t, injector.Faults(), 1)
+	})
+
+	t.Run("InvalidFault", func(t *testing.T) {
+		_, err := NewFaultInjector([]domain.FaultConfig{{Type: "unknown"}}, zerolog.New(io.Discard))
+		assert.ErrorIs(t, err, domain.ErrInvalidFault)
+	})
+}
+
+func TestNilFaultInjector(t *testing.T) {
+	t.Parallel()
+
+	var injector *FaultInjector
+
+	injector.Advance(1)
+	_, down := injector.linkDown(domain.Link{Src: "n1", Dst: "n2"})
+	assert.False(t, down)
+	_, stalled := injector.routerStalled("n1")
+	assert.False(t, stalled)
+	assert.False(t, injector.receiveFlit(domain.Link{}, packet.NewTailFlit("t", "AA", 1, 1, zerolog.New(io.Discard))))
+	assert.Nil(t, injector.AffectedPackets())
+}
+
+func TestFaultInjectorLinkAndRouterFaults(t *testing.T) {
+	t.Parallel()
+
+	link := domain.Link{Src: "n1", Dst: "n2"}
+	injector, err := NewFaultInjector([]domain.FaultConfig{
+		{Type: domain.LinkDownFault, StartCycle: 5, EndCycle: 6, Src: "n1", Dst: "n2"},
+		{Type: domain.RouterStallFault, StartCycle: 6, Src: "n3"},
+	}, zerolog.New(io.Discard))
+	require.NoError(t, err)
+
+	for c := 0; c < 10; c++ {
+		injector.Advance(c)
+
+		fault, down := injector.linkDown(link)
+		assert.Equal(t, c == 5 || c == 6, down, "cycle %d", c)
+		if down {
+			assert.Equal(t, 0, fault)
+		}
+
+		_, stalled := injector.routerStalled("n3")
+		assert.Equal(t, c >= 6, stalled, "cycle %d", c)
+	}
+
+	assert.Equal(t, []int{2, 4}, injector.Activations())
+}
+
+func TestFaultInjectorReceiveFlit(t *testing.T) {
+	t.Parallel()
+
+	link := domain.Link{Src: "n1", Dst: "n2"}
+	flits := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 3, zerolog.New(io.Discard)).Flits()
+
+	t.Run("DropHeader", func(t *testing.T) {
+		injector, err := NewFaultInjector([]domain.FaultConfig{{Type: domain.FlitDropFault, FlitID: flits[0].ID()}}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		// The dropped header flit's remaining flits are dropped on the same link only.
+		assert.True(t, injector.receiveFlit(link, flits[0]))
+		assert.False(t, injector.receiveFlit(domain.Link{Src: "n2", Dst: "n3"}, flits[1]))
+		assert.True(t, injector.receiveFlit(link, flits[1]))
+		assert.True(t, injector.receiveFlit(link, flits[2]))
+
+		assert.Equal(t, []int{3}, injector.Activations())
+		assert.Equal(t, map[string][]int{flits[0].PacketID(): {0}}, injector.AffectedPackets())
+	})
+
+	t.Run("CorruptOnLink", func(t *testing.T) {
+		flit := packet.NewBodyFlit("t", "AB", 1, 1, zerolog.New(io.Discard))
+		injector, err := NewFaultInjector([]domain.FaultConfig{
+			{Type: domain.FlitCorruptFault, Src: "n1", Dst: "n2", FlitID: flit.ID()},
+		}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		assert.False(t, injector.receiveFlit(domain.Link{Src: "n0", Dst: "n1"}, flit))
+		assert.False(t, flit.Corrupted())
+
+		assert.False(t, injector.receiveFlit(link, flit))
+		assert.True(t, flit.Corrupted())
+	})
+
+	t.Run("OutsideCycleRange", func(t *testing.T) {
+		flit := packet.NewBodyFlit("t", "AC", 1, 1, zerolog.New(io.Discard))
+		injector, err := NewFaultInjector([]domain.FaultConfig{
+			{Type: domain.FlitDropFault, StartCycle: 10, FlitID: flit.ID()},
+		}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		injector.Advance(9)
+		assert.False(t, injector.receiveFlit(link, flit))
+		injector.Advance(10)
+		assert.True(t, injector.receiveFlit(link, flit))
+		assert.False(t, injector.receiveFlit(link, flit))
+	})
+}
@@ -0,0 +1,50 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+
+	"main/src/domain"
+)
+
+// A flow control signal returned by a downstream input port to its upstream output port.
+type flowSignal struct {
+	vChan int
+	kind  flowSignalKind
+	// Flit acknowledged or rejected, ack/nack flow control only.
+	flitID string
+}
+
+type flowSignalKind int
+
+const (
+	signalOn flowSignalKind = iota
+	signalOff
+	signalAck
+	signalNack
+)
+
+// Returns the free flits of a virtual channel at or below which it signals off, and at or above which it signals on,
+// under on/off flow control. The off threshold must leave space for every flit which may arrive over the connection
+// before the upstream component collects the off signal.
+func onOffThresholds(conf domain.SimConfig, conn Connection, depth int) (int, int, error) {
+	slack := conn.flowControlSlack()
+
+	off := conf.OffThreshold
+	if off == 0 {
+		off = slack
+	}
+	on := conf.OnThreshold
+	if on == 0 {
+		on = off + 1
+	}
+
+	if off < slack {
+		return 0, 0, errors.Join(domain.ErrInvalidParameter, fmt.Errorf("on/off off threshold %d less than the %d flits which may arrive after the off signal", off, slack))
+	}
+	if on <= off || on > depth {
+		return 0, 0, errors.Join(domain.ErrInvalidParameter, fmt.Errorf("on/off on threshold %d must be greater than the off threshold %d and at most the virtual channel depth %d", on, off, depth))
+	}
+
+	return off, on, nil
+}
@@ -0,0 +1,36 @@
+package components
+
+// Counts, per victim traffic flow, the cycles each interfering traffic flow's flits occupied an output the victim's
+// flits were waiting to be sent through.
+type interferenceRecord map[string]map[string]int
+
+func (i interferenceRecord) record(victimTF, interferingTF string) {
+	if victimTF == interferingTF {
+		return
+	}
+
+	if _, exists := i[victimTF]; !exists {
+		i[victimTF] = make(map[string]int)
+	}
+	i[victimTF][interferingTF]++
+}
+
+// Records interference against the waiting flit's traffic flow if another traffic flow's flit was sent through the
+// output port during the cycle.
+func (i interferenceRecord) recordBlocked(cycle int, waitingTF string, port outputPort) {
+	if occupant, exists := port.sentFlit(cycle); exists {
+		i.record(waitingTF, occupant.TrafficFlowID())
+	}
+}
+
+// Merges the interference records into the destination, summing counts.
+func MergeInterference(dst, src map[string]map[string]int) {
+	for victimTF, interferers := range src {
+		if _, exists := dst[victimTF]; !exists {
+			dst[victimTF] = make(map[string]int, len(interferers))
+		}
+		for interferingTF, count := range interferers {
+			dst[victimTF][interferingTF] += count
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package components
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterferenceRecordRecordBlocked(t *testing.T) {
+	t.Parallel()
+
+	port := testOutputPort(t, 2)
+	port.credits[1] = 1
+	require.NoError(t, port.sendFlit(5, packet.NewHeaderFlit("t1", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))))
+
+	interference := make(interferenceRecord)
+	interference.recordBlocked(5, "t2", port)
+	interference.recordBlocked(5, "t2", port)
+	interference.recordBlocked(5, "t1", port)
+	interference.recordBlocked(6, "t3", port)
+
+	assert.Equal(t, interferenceRecord{"t2": {"t1": 2}}, interference)
+}
+
+func TestMergeInterference(t *testing.T) {
+	t.Parallel()
+
+	dst := map[string]map[string]int{"t2": {"t1": 1}}
+	MergeInterference(dst, interferenceRecord{"t2": {"t1": 2}, "t3": {"t2": 1}})
+
+	assert.Equal(t, map[string]map[string]int{"t2": {"t1": 3}, "t3": {"t2": 1}}, dst)
+}
@@ -1,6 +1,11 @@
 package components
 
 import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+
 	"main/src/domain"
 	"main/src/traffic/packet"
 
@@ -15,42 +20,131 @@ type NetworkInterface interface {
 
 	RoutePacket(cycle int, packet packet.Packet) error
 	PopArrivedPackets(cycle int) []packet.Packet
+	PopDroppedPackets(cycle int) []packet.Flit
+	TrafficFlowBlocked(trafficFlowID string) bool
 
 	TransmitPendingPackets(cycle int) error
 	HandleArrivingFlits(cycle int) error
+
+	Stats(cycles int) domain.NetworkInterfaceStats
+	InjectionQueues() map[string]domain.InjectionQueueStats
+	Interference() map[string]map[string]int
+	DiscardedPackets() map[string]error
 }
 
 type networkInterfaceImpl struct {
 	// Core Attributes
 	nodeID      string
 	bufferSize  int
+	vChanDepths []int
+	vChans      vChanMap
 	maxPriority int
+	preemptive  bool
+	wholePacket bool
+	simConf     domain.SimConfig
 
 	flitsInTransit map[int][]packet.Flit
 	outputPort     outputPort
+	// Header flit of the packet holding the injection link, non-preemptive switching only.
+	injecting packet.Flit
+	// Flits the injection queue holds, in total or per priority, unbounded if 0, and the handling of released packets
+	// which do not fit.
+	queueDepth  int
+	queueScope  domain.InjectionQueueScope
+	queuePolicy domain.InjectionQueuePolicy
+	// Released packets waiting, in release order, for space in the injection queue, block policy only. A traffic flow's
+	// blocked packets hold at most the injection queue depth's flits, its further releases are dropped.
+	blockedPackets []packet.Packet
+	// Header flits of the packets dropped from, or instead of entering, the injection queue, until popped.
+	droppedHeaders []packet.Flit
+	// Flits of each traffic flow's packets in the injection queue.
+	queuedFlits map[string]int
+
+	inputPort inputPort
+	// Flits consumed per cycle, or cycles between flits consumed, from the ejection buffer, unlimited if rateLimited is
+	// unset, and the cycles spent servicing each packet once its tail flit has been consumed.
+	rateLimited   bool
+	flitsPerCycle int
+	cyclesPerFlit int
+	serviceTime   int
+	// Cycle the last flit was consumed, the flits consumed that cycle, and the first cycle after the current packet's
+	// service.
+	lastConsumedCycle int
+	consumedThisCycle int
+	serviceEndCycle   int
 
-	inputPort      inputPort
 	flitsArriving  map[string]packet.Reconstructor
 	arrivedPackets []packet.Packet
+	// Packets which could not be reconstructed, by packet ID, due to missing or corrupted flits.
+	discardedPackets map[string]error
+
+	// Statistics
+	interference         interferenceRecord
+	injectionQueues      map[string]domain.InjectionQueueStats
+	ejectionSamples      int
+	ejectionOccupancySum int
+	ejectionOccupancyMax int
 
 	// Utility
 	logger zerolog.Logger
 }
 
+// Creates a network interface whose input buffer is split evenly across the priorities' virtual channels.
 func newNetworkInterface(nodeID string, bufferSize, maxPriority int, logger zerolog.Logger) (*networkInterfaceImpl, error) {
 	if err := validBufferSize(bufferSize, maxPriority); err != nil {
 		logger.Error().Err(err).Msg("invalid buffer size")
 		return nil, err
 	}
 
+	return newConfiguredNetworkInterface(nodeID, domain.SimConfig{BufferSize: bufferSize, MaxPriority: maxPriority}, logger)
+}
+
+// Creates a network interface whose input buffer's virtual channels and priority to virtual channel mapping follow the
+// simulation configuration.
+func newConfiguredNetworkInterface(nodeID string, conf domain.SimConfig, logger zerolog.Logger) (*networkInterfaceImpl, error) {
+	vChanDepths, err := bufferVChanDepths(conf, conf.EjectionBuffer())
+	if err != nil {
+		logger.Error().Err(err).Msg("invalid buffer size")
+		return nil, err
+	}
+	if err := validVChanDepths(vChanDepths); err != nil {
+		logger.Error().Err(err).Ints("vc_depths", vChanDepths).Msg("invalid virtual channel depths")
+		return nil, err
+	}
+
+	bufferSize := 0
+	for _, depth := range vChanDepths {
+		bufferSize += depth
+	}
+
 	logger.Trace().Str("id", nodeID).Msg("new network interface")
 	return &networkInterfaceImpl{
 		nodeID:         nodeID,
 		bufferSize:     bufferSize,
-		maxPriority:    maxPriority,
+		vChanDepths:    vChanDepths,
+		vChans:         conf.VChanMap(),
+		maxPriority:    conf.MaxPriority,
+		preemptive:     conf.Preemptive(),
+		wholePacket:    conf.WholePacketSwitching(),
+		simConf:        conf,
 		flitsInTransit: make(map[int][]packet.Flit),
+		queueDepth:     conf.InjectionQueueDepth,
+		queueScope:     conf.InjectionQueueLimitScope(),
+		queuePolicy:    conf.InjectionQueueOverflowPolicy(),
+		queuedFlits:    make(map[string]int),
+
+		rateLimited:       conf.EjectionRate > 0,
+		flitsPerCycle:     domain.FlitsPerCycle(conf.EjectionRate),
+		cyclesPerFlit:     domain.CyclesPerFlit(conf.EjectionRate),
+		serviceTime:       conf.EjectionServiceTime,
+		lastConsumedCycle: -domain.CyclesPerFlit(conf.EjectionRate),
+
 		flitsArriving:  make(map[string]packet.Reconstructor),
 		arrivedPackets: make([]packet.Packet, 0),
+		interference:   make(interferenceRecord),
+
+		injectionQueues:  make(map[string]domain.InjectionQueueStats),
+		discardedPackets: make(map[string]error),
 
 		logger: logger.With().Str("component", "network_interface").Str("node_id", nodeID).Logger(),
 	}, nil
@@ -67,13 +161,21 @@ func (n *networkInterfaceImpl) SetInputPort(conn Connection) error {
 
 	conn.SetDstRouter(n.NodeID())
 
-	buff, err := newBuffer(n.bufferSize, n.maxPriority, n.logger)
+	buff, err := newPartitionedBuffer(n.vChanDepths, n.vChans, n.logger)
+	if err != nil {
+		return err
+	}
+
+	port, err := newInputPort(conn, buff, n.logger)
 	if err != nil {
 		return err
 	}
+	if err := port.setFlowControl(n.simConf); err != nil {
+		return err
+	}
 
-	n.inputPort, err = newInputPort(conn, buff, n.logger)
-	return err
+	n.inputPort = port
+	return nil
 }
 
 func (n *networkInterfaceImpl) SetOutputPort(conn Connection) error {
@@ -83,9 +185,17 @@ func (n *networkInterfaceImpl) SetOutputPort(conn Connection) error {
 
 	conn.SetSrcRouter(n.NodeID())
 
-	var err error
-	n.outputPort, err = newOutputPort(conn, n.maxPriority, n.logger)
-	return err
+	port, err := newOutputPort(conn, len(n.vChanDepths), n.logger)
+	if err != nil {
+		return err
+	}
+	port.vChans = n.vChans
+	port.wholePacket = n.wholePacket
+	port.flowControl = n.simConf.FlowControlScheme()
+	port.window = n.simConf.RetransmissionWindow
+
+	n.outputPort = port
+	return nil
 }
 
 func (n *networkInterfaceImpl) RoutePacket(cycle int, pkt packet.Packet) error {
@@ -102,38 +212,200 @@ func (n *networkInterfaceImpl) RoutePacket(cycle int, pkt packet.Packet) error {
 		logger.Trace().
 			Str("flit", flits[i].ID()).Str("type", flits[i].Type().String()).
 			Msg("flit created at network interface")
-		n.flitsInTransit[pkt.Priority()] = append(n.flitsInTransit[pkt.Priority()], flits[i])
+		flits[i].RecordEvent(cycle, packet.FlitCreated, n.nodeID)
+	}
+
+	if !n.TrafficFlowBlocked(pkt.TrafficFlowID()) && n.queueSpace(pkt.Priority()) >= len(flits) {
+		n.enqueuePacket(pkt)
+		return nil
+	}
+
+	stats := n.injectionQueues[pkt.TrafficFlowID()]
+	stats.Overflows++
+	n.injectionQueues[pkt.TrafficFlowID()] = stats
+
+	switch n.queuePolicy {
+	case domain.BlockInjectionQueuePolicy:
+		logger.Debug().Str("packet", pkt.ID()).Msg("injection queue full, blocking traffic flow")
+		n.blockedPackets = append(n.blockedPackets, pkt)
+	case domain.DropOldestInjectionQueuePolicy:
+		if n.dropOldestPackets(cycle, pkt) {
+			n.enqueuePacket(pkt)
+			return nil
+		}
+		n.dropPacket(cycle, pkt)
+	default:
+		n.dropPacket(cycle, pkt)
 	}
 
 	return nil
 }
 
+// Returns the number of flits the injection queue has space for, in total or for the priority.
+func (n *networkInterfaceImpl) queueSpace(priority int) int {
+	if n.queueDepth == 0 {
+		return math.MaxInt
+	}
+
+	if n.queueScope == domain.PriorityInjectionQueueScope {
+		return n.queueDepth - len(n.flitsInTransit[priority])
+	}
+
+	queued := 0
+	for _, flits := range n.flitsInTransit {
+		queued += len(flits)
+	}
+	return n.queueDepth - queued
+}
+
+// Reports whether the traffic flow has a packet waiting for space in the injection queue.
+func (n *networkInterfaceImpl) TrafficFlowBlocked(trafficFlowID string) bool {
+	return slices.ContainsFunc(n.blockedPackets, func(pkt packet.Packet) bool {
+		return pkt.TrafficFlowID() == trafficFlowID
+	})
+}
+
+func (n *networkInterfaceImpl) enqueuePacket(pkt packet.Packet) {
+	n.flitsInTransit[pkt.Priority()] = append(n.flitsInTransit[pkt.Priority()], pkt.Flits()...)
+	n.queuedFlits[pkt.TrafficFlowID()] += len(pkt.Flits())
+
+	stats := n.injectionQueues[pkt.TrafficFlowID()]
+	stats.MaxQueueDepth = max(stats.MaxQueueDepth, n.queuedFlits[pkt.TrafficFlowID()])
+	n.injectionQueues[pkt.TrafficFlowID()] = stats
+}
+
+// Moves blocked packets into the injection queue, in release order, once they fit. A traffic flow's packets stay
+// blocked behind its earlier blocked packets.
+func (n *networkInterfaceImpl) admitBlockedPackets() {
+	if len(n.blockedPackets) == 0 {
+		return
+	}
+
+	blockedTFs := make(map[string]bool)
+	remaining := n.blockedPackets[:0]
+	for _, pkt := range n.blockedPackets {
+		if !blockedTFs[pkt.TrafficFlowID()] && n.queueSpace(pkt.Priority()) >= len(pkt.Flits()) {
+			n.enqueuePacket(pkt)
+			continue
+		}
+
+		blockedTFs[pkt.TrafficFlowID()] = true
+		remaining = append(remaining, pkt)
+	}
+	n.blockedPackets = remaining
+}
+
+// A queued packet whose header flit has not been sent, and its number of flits.
+type queuedPacket struct {
+	header packet.Flit
+	flits  int
+}
+
+// Returns the queued packets whose header flits have not been sent, in total or for the priority, oldest first.
+func (n *networkInterfaceImpl) unsentPackets(priority int) []queuedPacket {
+	pkts := make([]queuedPacket, 0)
+	for p := 1; p <= n.maxPriority; p++ {
+		if n.queueScope == domain.PriorityInjectionQueueScope && p != priority {
+			continue
+		}
+
+		for _, flit := range n.flitsInTransit[p] {
+			if flit.Type() == packet.HeaderFlitType {
+				pkts = append(pkts, queuedPacket{header: flit})
+			}
+			if len(pkts) > 0 && pkts[len(pkts)-1].header.PacketID() == flit.PacketID() {
+				pkts[len(pkts)-1].flits++
+			}
+		}
+	}
+
+	sort.SliceStable(pkts, func(i, j int) bool {
+		return releaseCycle(pkts[i].header) < releaseCycle(pkts[j].header)
+	})
+	return pkts
+}
+
+// Drops the oldest queued packets whose header flits have not been sent until the packet fits in the injection queue.
+// Drops nothing, and returns false, if the packet cannot be made to fit.
+func (n *networkInterfaceImpl) dropOldestPackets(cycle int, pkt packet.Packet) bool {
+	victims := n.unsentPackets(pkt.Priority())
+
+	space := n.queueSpace(pkt.Priority())
+	for _, victim := range victims {
+		space += victim.flits
+	}
+	if space < len(pkt.Flits()) {
+		return false
+	}
+
+	for i := 0; n.queueSpace(pkt.Priority()) < len(pkt.Flits()); i++ {
+		victim := victims[i]
+		n.flitsInTransit[victim.header.Priority()] = slices.DeleteFunc(n.flitsInTransit[victim.header.Priority()], func(flit packet.Flit) bool {
+			return flit.PacketID() == victim.header.PacketID()
+		})
+		n.queuedFlits[victim.header.TrafficFlowID()] -= victim.flits
+
+		stats := n.injectionQueues[victim.header.TrafficFlowID()]
+		stats.PacketsDropped++
+		n.injectionQueues[victim.header.TrafficFlowID()] = stats
+		n.droppedHeaders = append(n.droppedHeaders, victim.header)
+
+		n.logger.Debug().Int("cycle", cycle).Str("packet", victim.header.PacketID()).Msg("injection queue full, dropping oldest packet")
+	}
+
+	return true
+}
+
+func (n *networkInterfaceImpl) dropPacket(cycle int, pkt packet.Packet) {
+	stats := n.injectionQueues[pkt.TrafficFlowID()]
+	stats.PacketsDropped++
+	n.injectionQueues[pkt.TrafficFlowID()] = stats
+	n.droppedHeaders = append(n.droppedHeaders, pkt.Flits()[0])
+
+	n.logger.Debug().Int("cycle", cycle).Str("packet", pkt.ID()).Msg("injection queue full, dropping packet")
+}
+
 func (n *networkInterfaceImpl) PopArrivedPackets(cycle int) []packet.Packet {
 	pkts := n.arrivedPackets
 	n.arrivedPackets = n.arrivedPackets[:0]
 	return pkts
 }
 
+// Returns the header flits of the packets dropped by the injection queue since the last call.
+func (n *networkInterfaceImpl) PopDroppedPackets(cycle int) []packet.Flit {
+	headers := n.droppedHeaders
+	n.droppedHeaders = nil
+	return headers
+}
+
 func (n *networkInterfaceImpl) HandleArrivingFlits(cycle int) error {
 	logger := n.logger.With().Int("cycle", cycle).Logger()
 
 	if err := n.inputPort.readIntoBuffer(cycle); err != nil {
 		return err
 	}
+	defer n.sampleEjectionOccupancy()
 
 	actionFlag := true
 	for actionFlag {
 		actionFlag = false
 
-		for p := 1; p <= n.maxPriority; p++ {
+		for vc := 1; vc <= n.inputPort.vChanCount(); vc++ {
 			for b := 0; b < n.bufferSize; b++ {
-				flit, exists := n.inputPort.readOutOfBuffer(cycle, p)
+				if !n.canConsume(cycle) {
+					return nil
+				}
+
+				flit, exists := n.inputPort.readOutOfBuffer(cycle, vc)
 
 				if !exists {
 					break
 				}
 
 				actionFlag = true
+				n.consumed(cycle, flit)
+
+				flit.RecordEvent(cycle, packet.FlitArrived, n.nodeID)
 
 				var err error
 				if headerFlit, ok := flit.(packet.HeaderFlit); ok && flit.Type() == packet.HeaderFlitType {
@@ -162,6 +434,42 @@ func (n *networkInterfaceImpl) HandleArrivingFlits(cycle int) error {
 	return nil
 }
 
+// Reports whether a flit can be consumed from the ejection buffer on the cycle without exceeding the ejection rate or
+// interrupting a packet's service.
+func (n *networkInterfaceImpl) canConsume(cycle int) bool {
+	if cycle < n.serviceEndCycle {
+		return false
+	}
+	if !n.rateLimited {
+		return true
+	}
+	if cycle == n.lastConsumedCycle {
+		return n.consumedThisCycle < n.flitsPerCycle
+	}
+	return cycle-n.lastConsumedCycle >= n.cyclesPerFlit
+}
+
+func (n *networkInterfaceImpl) consumed(cycle int, flit packet.Flit) {
+	if cycle != n.lastConsumedCycle {
+		n.lastConsumedCycle = cycle
+		n.consumedThisCycle = 0
+	}
+	n.consumedThisCycle++
+
+	if flit.Type() == packet.TailFlitType && n.serviceTime > 0 {
+		n.serviceEndCycle = cycle + n.serviceTime + 1
+	}
+}
+
+// Samples the number of flits waiting in the ejection buffer, expected to be called once per cycle.
+func (n *networkInterfaceImpl) sampleEjectionOccupancy() {
+	occupancy := n.inputPort.occupancy()
+
+	n.ejectionSamples++
+	n.ejectionOccupancySum += occupancy
+	n.ejectionOccupancyMax = max(n.ejectionOccupancyMax, occupancy)
+}
+
 func (n *networkInterfaceImpl) arrivedHeaderFlit(flit packet.HeaderFlit) error {
 	_, exists := n.flitsArriving[flit.PacketID()]
 	if exists {
@@ -202,7 +510,12 @@ func (n *networkInterfaceImpl) arrivedTailFlit(flit packet.TailFlit) error {
 	}
 
 	packet, err := reconstructor.Reconstruct()
-	if err != nil {
+	if errors.Is(err, domain.ErrIncompletePacket) || errors.Is(err, domain.ErrCorruptPacket) {
+		n.logger.Warn().Err(err).Str("packet", flit.PacketID()).Msg("discarding packet which could not be reconstructed")
+		n.discardedPackets[flit.PacketID()] = err
+		delete(n.flitsArriving, flit.PacketID())
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -215,10 +528,20 @@ func (n *networkInterfaceImpl) arrivedTailFlit(flit packet.TailFlit) error {
 func (n *networkInterfaceImpl) TransmitPendingPackets(cycle int) error {
 	logger := n.logger.With().Int("cycle", cycle).Logger()
 
-	n.outputPort.updateCredits()
+	n.outputPort.updateCredits(cycle)
+	n.outputPort.transmitBuffered(cycle)
+
+	n.admitBlockedPackets()
 
 	for p := 1; p <= n.maxPriority; p++ {
-		for len(n.flitsInTransit[p]) > 0 && n.outputPort.allowedToSend(n.flitsInTransit[p][0].Priority()) {
+		if n.injecting != nil && n.injecting.Priority() != p {
+			if len(n.flitsInTransit[p]) > 0 {
+				n.interference.record(n.flitsInTransit[p][0].TrafficFlowID(), n.injecting.TrafficFlowID())
+			}
+			continue
+		}
+
+		for len(n.flitsInTransit[p]) > 0 && n.outputPort.canAllocate(n.flitsInTransit[p][0]) && n.outputPort.allowedToSend(cycle, n.flitsInTransit[p][0].Priority()) {
 			if err := n.outputPort.sendFlit(cycle, n.flitsInTransit[p][0]); err != nil {
 				logger.Error().Err(err).
 					Str("flit", n.flitsInTransit[p][0].ID()).Str("type", n.flitsInTransit[p][0].Type().String()).
@@ -230,10 +553,79 @@ func (n *networkInterfaceImpl) TransmitPendingPackets(cycle int) error {
 			logger.Trace().
 				Str("flit", n.flitsInTransit[p][0].ID()).Str("type", n.flitsInTransit[p][0].Type().String()).
 				Msg("flit sent from network interface")
+			n.flitsInTransit[p][0].RecordEvent(cycle, packet.FlitTransmitted, n.nodeID)
+			n.updateInjecting(n.flitsInTransit[p][0])
+			n.queuedFlits[n.flitsInTransit[p][0].TrafficFlowID()]--
 
 			n.flitsInTransit[p] = n.flitsInTransit[p][1:]
 		}
+
+		if len(n.flitsInTransit[p]) > 0 && !n.outputPort.faultBlocked(n.flitsInTransit[p][0]) && n.outputPort.credit(p) > 0 {
+			n.interference.recordBlocked(cycle, n.flitsInTransit[p][0].TrafficFlowID(), n.outputPort)
+		}
 	}
 
 	return nil
 }
+
+// Holds the injection link for the packet of a sent header flit until its tail flit has been sent, when switching is
+// non-preemptive.
+func (n *networkInterfaceImpl) updateInjecting(flit packet.Flit) {
+	if n.preemptive {
+		return
+	}
+
+	switch flit.Type() {
+	case packet.HeaderFlitType:
+		n.injecting = flit
+	case packet.TailFlitType:
+		n.injecting = nil
+	}
+}
+
+// Returns the flits sent over the network interface's injection and ejection links over the simulated cycles, the
+// links' utilisation, and the occupancy of its ejection buffer.
+func (n *networkInterfaceImpl) Stats(cycles int) domain.NetworkInterfaceStats {
+	stats := domain.NetworkInterfaceStats{ID: n.NodeID()}
+
+	if n.outputPort != nil {
+		stats.Router = n.outputPort.connection().GetDstRouter()
+		stats.InjectedFlits = n.outputPort.connection().FlitCount()
+	}
+	if n.inputPort != nil {
+		stats.EjectedFlits = n.inputPort.connection().FlitCount()
+	}
+
+	if cycles > 0 {
+		stats.InjectionUtilisation = float64(stats.InjectedFlits) / float64(cycles)
+		stats.EjectionUtilisation = float64(stats.EjectedFlits) / float64(cycles)
+	}
+	if n.ejectionSamples > 0 {
+		stats.MeanEjectionOccupancy = float64(n.ejectionOccupancySum) / float64(n.ejectionSamples)
+		stats.MaxEjectionOccupancy = n.ejectionOccupancyMax
+	}
+
+	return stats
+}
+
+// Returns each traffic flow's injection queue overflows, drops and maximum depth.
+func (n *networkInterfaceImpl) InjectionQueues() map[string]domain.InjectionQueueStats {
+	injectionQueues := make(map[string]domain.InjectionQueueStats, len(n.injectionQueues))
+	for tf, stats := range n.injectionQueues {
+		injectionQueues[tf] = stats
+	}
+	return injectionQueues
+}
+
+// Returns, per victim traffic flow, the number of cycles each other traffic flow's flits held the injection link
+// while the victim's flits were waiting to be injected.
+func (n *networkInterfaceImpl) Interference() map[string]map[string]int {
+	interference := make(map[string]map[string]int, len(n.interference))
+	MergeInterference(interference, n.interference)
+	return interference
+}
+
+// Returns the packets which could not be reconstructed, by packet ID, with the reason.
+func (n *networkInterfaceImpl) DiscardedPackets() map[string]error {
+	return n.discardedPackets
+}
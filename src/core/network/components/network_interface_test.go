@@ -88,7 +88,7 @@ func TestNetworkInterfaceSetInputPort(t *testing.T) {
 		netIntfc, err := newNetworkInterface("i", 1, 1, zerolog.New(io.Discard))
 		require.NoError(t, err)
 
-		netIntfc.bufferSize = 0
+		netIntfc.vChanDepths = []int{0}
 
 		err = netIntfc.SetInputPort(&connectionImpl{})
 		require.Error(t, err)
@@ -218,6 +218,76 @@ func TestNetworkInterfaceHandleArrivingFlits(t *testing.T) {
 		require.Len(t, netIntfc.arrivedPackets, 1)
 		require.NoError(t, packet.EqualPackets(pkt, netIntfc.arrivedPackets[0]))
 	})
+
+	// Delivers each packet's flits a cycle apart, from cycle 0, handling the arriving flits every cycle until the cycle
+	// limit and returning the number of packets arrived by the end of each cycle.
+	arrive := func(t *testing.T, netIntfc *networkInterfaceImpl, inConn *connectionImpl, cycles int, pkts ...packet.Packet) []int {
+		flits := make([]packet.Flit, 0)
+		for _, pkt := range pkts {
+			flits = append(flits, pkt.Flits()...)
+		}
+
+		arrived := make([]int, cycles)
+		for c := 0; c < cycles; c++ {
+			if c < len(flits) {
+				inConn.flitChannel() <- flits[c]
+			}
+			require.NoError(t, netIntfc.HandleArrivingFlits(c))
+			arrived[c] = len(netIntfc.arrivedPackets)
+		}
+		return arrived
+	}
+
+	t.Run("EjectionRate", func(t *testing.T) {
+		conf := domain.SimConfig{BufferSize: 4, MaxPriority: 1, EjectionRate: 0.5}
+		netIntfc, err := newConfiguredNetworkInterface("n2", conf, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		inConn, err := NewConnection(conf.MaxPriority, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.NoError(t, netIntfc.SetInputPort(inConn))
+
+		pkt := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 4, zerolog.New(io.Discard))
+
+		// A flit is consumed every other cycle, the remaining flits waiting in the ejection buffer.
+		arrived := arrive(t, netIntfc, inConn, 7, pkt)
+		assert.Equal(t, []int{0, 0, 0, 0, 0, 0, 1}, arrived)
+
+		stats := netIntfc.Stats(7)
+		assert.InDelta(t, 6.0/7.0, stats.MeanEjectionOccupancy, 1e-9)
+		assert.Equal(t, 2, stats.MaxEjectionOccupancy)
+
+		// Credits are only returned for consumed flits, initial credits aside.
+		credits := make(map[int]int)
+		inConn.receiveCredits(7, credits)
+		assert.Equal(t, conf.BufferSize+4, credits[1])
+	})
+
+	t.Run("EjectionServiceTime", func(t *testing.T) {
+		conf := domain.SimConfig{BufferSize: 4, MaxPriority: 1, EjectionServiceTime: 3}
+		netIntfc, err := newConfiguredNetworkInterface("n2", conf, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		inConn, err := NewConnection(conf.MaxPriority, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.NoError(t, netIntfc.SetInputPort(inConn))
+
+		first := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 2, zerolog.New(io.Discard))
+		second := packet.NewPacket("t", "AB", 1, 100, domain.Route{"n1", "n2"}, 2, zerolog.New(io.Discard))
+
+		// The second packet's flits wait out the first packet's service, cycles 2 to 4.
+		arrived := arrive(t, netIntfc, inConn, 6, first, second)
+		assert.Equal(t, []int{0, 1, 1, 1, 1, 2}, arrived)
+	})
+
+	t.Run("EjectionBufferSize", func(t *testing.T) {
+		conf := domain.SimConfig{BufferSize: 8, MaxPriority: 2, EjectionBufferSize: 4}
+		netIntfc, err := newConfiguredNetworkInterface("n2", conf, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, netIntfc.bufferSize)
+		assert.Equal(t, []int{2, 2}, netIntfc.vChanDepths)
+	})
 }
 
 func TestNetworkInterfaceArrivedHeaderFlit(t *testing.T) {
@@ -387,4 +457,202 @@ func TestNetworkInterfaceTransmitPendingPackets(t *testing.T) {
 		assert.Equal(t, pkt.Flits()[0].PacketIndex(), gotFlit.PacketIndex())
 		assert.Equal(t, pkt.Flits()[0].Type(), gotFlit.Type())
 	})
+
+	t.Run("NonPreemptive", func(t *testing.T) {
+		var route domain.Route = domain.Route{"n1", "n2"}
+
+		conf := domain.SimConfig{BufferSize: 8, MaxPriority: 2, Switching: domain.NonPreemptiveWormholeSwitching}
+		netIntfc, err := newConfiguredNetworkInterface("i", conf, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn, err := NewConnection(conf.MaxPriority, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		buff, err := newBuffer(conf.BufferSize, conf.MaxPriority, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		newInputPort(conn, buff, zerolog.New(io.Discard))
+
+		err = netIntfc.SetOutputPort(conn)
+		require.NoError(t, err)
+
+		low := packet.NewPacket("tl", "AA", 2, 100, route, 3, zerolog.New(io.Discard))
+		high := packet.NewPacket("th", "AB", 1, 100, route, 3, zerolog.New(io.Discard))
+
+		require.NoError(t, netIntfc.RoutePacket(0, low))
+		require.NoError(t, netIntfc.TransmitPendingPackets(0))
+		assert.Equal(t, low.Flits()[0].ID(), (<-conn.flitChan).ID())
+
+		// The higher priority packet waits for the lower priority packet's tail flit.
+		require.NoError(t, netIntfc.RoutePacket(1, high))
+		expected := []packet.Flit{low.Flits()[1], low.Flits()[2], high.Flits()[0]}
+		for i, flit := range expected {
+			require.NoError(t, netIntfc.TransmitPendingPackets(i+1))
+			assert.Equal(t, flit.ID(), (<-conn.flitChan).ID())
+		}
+		assert.Equal(t, map[string]map[string]int{"th": {"tl": 2}}, netIntfc.Interference())
+	})
+}
+
+func TestNetworkInterfaceInjectionQueue(t *testing.T) {
+	t.Parallel()
+
+	var route domain.Route = domain.Route{"n1", "n2"}
+
+	newQueuedNetworkInterface := func(t *testing.T, conf domain.SimConfig) (*networkInterfaceImpl, Connection) {
+		netIntfc, err := newConfiguredNetworkInterface("i", conf, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn, err := NewConnection(conf.MaxPriority, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		buff, err := newBuffer(conf.BufferSize, conf.MaxPriority, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		newInputPort(conn, buff, zerolog.New(io.Discard))
+
+		require.NoError(t, netIntfc.SetOutputPort(conn))
+		return netIntfc, conn
+	}
+
+	queuedIDs := func(netIntfc *networkInterfaceImpl, priority int) []string {
+		ids := make([]string, 0)
+		for _, flit := range netIntfc.flitsInTransit[priority] {
+			ids = append(ids, flit.ID())
+		}
+		return ids
+	}
+
+	flitIDs := func(pkts ...packet.Packet) []string {
+		ids := make([]string, 0)
+		for _, pkt := range pkts {
+			for _, flit := range pkt.Flits() {
+				ids = append(ids, flit.ID())
+			}
+		}
+		return ids
+	}
+
+	t.Run("Unbounded", func(t *testing.T) {
+		netIntfc, _ := newQueuedNetworkInterface(t, domain.SimConfig{BufferSize: 8, MaxPriority: 1})
+
+		for i, idx := range []string{"AA", "AB", "AC"} {
+			require.NoError(t, netIntfc.RoutePacket(i, packet.NewPacket("t", idx, 1, 100, route, 4, zerolog.New(io.Discard))))
+		}
+
+		assert.Len(t, netIntfc.flitsInTransit[1], 12)
+		assert.Equal(t, map[string]domain.InjectionQueueStats{"t": {MaxQueueDepth: 12}}, netIntfc.InjectionQueues())
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		conf := domain.SimConfig{BufferSize: 8, MaxPriority: 1, InjectionQueueDepth: 4}
+		netIntfc, conn := newQueuedNetworkInterface(t, conf)
+
+		first := packet.NewPacket("t", "AA", 1, 100, route, 2, zerolog.New(io.Discard))
+		second := packet.NewPacket("t", "AB", 1, 100, route, 2, zerolog.New(io.Discard))
+		third := packet.NewPacket("t", "AC", 1, 100, route, 2, zerolog.New(io.Discard))
+		require.NoError(t, netIntfc.RoutePacket(0, first))
+		require.NoError(t, netIntfc.RoutePacket(0, second))
+		require.NoError(t, netIntfc.RoutePacket(0, third))
+
+		assert.Equal(t, flitIDs(first, second), queuedIDs(netIntfc, 1))
+		assert.Equal(t, []packet.Packet{third}, netIntfc.blockedPackets)
+
+		// The blocked packet waits for the first packet's flits to leave the injection queue.
+		for c := 0; c < 2; c++ {
+			require.NoError(t, netIntfc.TransmitPendingPackets(c))
+			<-conn.(*connectionImpl).flitChan
+			assert.Len(t, netIntfc.blockedPackets, 1)
+		}
+		require.NoError(t, netIntfc.TransmitPendingPackets(2))
+
+		assert.Empty(t, netIntfc.blockedPackets)
+		assert.Equal(t, flitIDs(second, third)[1:], queuedIDs(netIntfc, 1))
+		assert.Equal(t, map[string]domain.InjectionQueueStats{"t": {Overflows: 1, MaxQueueDepth: 4}}, netIntfc.InjectionQueues())
+	})
+
+	t.Run("BlockKeepsTrafficFlowOrder", func(t *testing.T) {
+		conf := domain.SimConfig{BufferSize: 8, MaxPriority: 1, InjectionQueueDepth: 5}
+		netIntfc, _ := newQueuedNetworkInterface(t, conf)
+
+		require.NoError(t, netIntfc.RoutePacket(0, packet.NewPacket("t1", "AA", 1, 100, route, 3, zerolog.New(io.Discard))))
+		blocked := packet.NewPacket("t1", "AB", 1, 100, route, 3, zerolog.New(io.Discard))
+		require.NoError(t, netIntfc.RoutePacket(0, blocked))
+
+		// A later, smaller packet of the blocked traffic flow stays behind the blocked packet, other traffic flows'
+		// packets which fit are queued.
+		later := packet.NewPacket("t1", "AC", 1, 100, route, 2, zerolog.New(io.Discard))
+		other := packet.NewPacket("t2", "AA", 1, 100, route, 2, zerolog.New(io.Discard))
+		require.NoError(t, netIntfc.RoutePacket(1, later))
+		require.NoError(t, netIntfc.RoutePacket(1, other))
+
+		assert.Equal(t, []packet.Packet{blocked, later}, netIntfc.blockedPackets)
+		assert.True(t, netIntfc.TrafficFlowBlocked("t1"))
+		assert.False(t, netIntfc.TrafficFlowBlocked("t2"))
+		assert.Len(t, netIntfc.flitsInTransit[1], 5)
+		assert.Equal(t, 2, netIntfc.InjectionQueues()["t1"].Overflows)
+	})
+
+	t.Run("DropNew", func(t *testing.T) {
+		conf := domain.SimConfig{BufferSize: 8, MaxPriority: 1, InjectionQueueDepth: 4, InjectionQueuePolicy: domain.DropNewInjectionQueuePolicy}
+		netIntfc, _ := newQueuedNetworkInterface(t, conf)
+
+		first := packet.NewPacket("t", "AA", 1, 100, route, 3, zerolog.New(io.Discard))
+		second := packet.NewPacket("t", "AB", 1, 100, route, 3, zerolog.New(io.Discard))
+		require.NoError(t, netIntfc.RoutePacket(0, first))
+		require.NoError(t, netIntfc.RoutePacket(1, second))
+
+		assert.Equal(t, flitIDs(first), queuedIDs(netIntfc, 1))
+		assert.Empty(t, netIntfc.blockedPackets)
+		assert.Equal(t, []packet.Flit{second.Flits()[0]}, netIntfc.PopDroppedPackets(1))
+		assert.Equal(t, map[string]domain.InjectionQueueStats{"t": {Overflows: 1, PacketsDropped: 1, MaxQueueDepth: 3}}, netIntfc.InjectionQueues())
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		conf := domain.SimConfig{BufferSize: 8, MaxPriority: 1, InjectionQueueDepth: 4, InjectionQueuePolicy: domain.DropOldestInjectionQueuePolicy}
+		netIntfc, conn := newQueuedNetworkInterface(t, conf)
+
+		oldest := packet.NewPacket("t1", "AA", 1, 100, route, 2, zerolog.New(io.Discard))
+		older := packet.NewPacket("t2", "AA", 1, 100, route, 2, zerolog.New(io.Discard))
+		newest := packet.NewPacket("t3", "AA", 1, 100, route, 2, zerolog.New(io.Discard))
+		require.NoError(t, netIntfc.RoutePacket(0, oldest))
+		require.NoError(t, netIntfc.RoutePacket(1, older))
+		require.NoError(t, netIntfc.RoutePacket(2, newest))
+
+		assert.Equal(t, flitIDs(older, newest), queuedIDs(netIntfc, 1))
+		assert.Equal(t, []packet.Flit{oldest.Flits()[0]}, netIntfc.PopDroppedPackets(2))
+
+		// Once the older packet's header flit is sent only the newest packet may be dropped, which leaves too little
+		// space for a whole queue's worth of flits, so the released packet is dropped instead.
+		require.NoError(t, netIntfc.TransmitPendingPackets(3))
+		<-conn.(*connectionImpl).flitChan
+		require.NoError(t, netIntfc.RoutePacket(3, packet.NewPacket("t4", "AA", 1, 100, route, 4, zerolog.New(io.Discard))))
+
+		assert.Equal(t, flitIDs(older, newest)[1:], queuedIDs(netIntfc, 1))
+		assert.Equal(t, map[string]domain.InjectionQueueStats{
+			"t1": {PacketsDropped: 1, MaxQueueDepth: 2},
+			"t2": {MaxQueueDepth: 2},
+			"t3": {Overflows: 1, MaxQueueDepth: 2},
+			"t4": {Overflows: 1, PacketsDropped: 1},
+		}, netIntfc.InjectionQueues())
+	})
+
+	t.Run("PriorityScope", func(t *testing.T) {
+		conf := domain.SimConfig{
+			BufferSize:           8,
+			MaxPriority:          2,
+			InjectionQueueDepth:  2,
+			InjectionQueueScope:  domain.PriorityInjectionQueueScope,
+			InjectionQueuePolicy: domain.DropNewInjectionQueuePolicy,
+		}
+		netIntfc, _ := newQueuedNetworkInterface(t, conf)
+
+		high := packet.NewPacket("th", "AA", 1, 100, route, 2, zerolog.New(io.Discard))
+		low := packet.NewPacket("tl", "AA", 2, 100, route, 2, zerolog.New(io.Discard))
+		require.NoError(t, netIntfc.RoutePacket(0, high))
+		require.NoError(t, netIntfc.RoutePacket(0, low))
+		require.NoError(t, netIntfc.RoutePacket(1, packet.NewPacket("th", "AB", 1, 100, route, 2, zerolog.New(io.Discard))))
+
+		assert.Equal(t, flitIDs(high), queuedIDs(netIntfc, 1))
+		assert.Equal(t, flitIDs(low), queuedIDs(netIntfc, 2))
+		assert.Equal(t, 1, netIntfc.InjectionQueues()["th"].PacketsDropped)
+	})
 }
@@ -1,6 +1,8 @@
 package components
 
 import (
+	"slices"
+
 	"main/src/domain"
 	"main/src/traffic/packet"
 
@@ -10,27 +12,104 @@ import (
 type inputPort interface {
 	connection() Connection
 	readIntoBuffer(cycle int) error
-	peakBuffer(priority int) (packet.Flit, bool)
-	readOutOfBuffer(cycle, priority int) (packet.Flit, bool)
+	vChanCount() int
+	peakBuffer(vChan int) (packet.Flit, bool)
+	readOutOfBuffer(cycle, vChan int) (packet.Flit, bool)
+	packetBuffered(packetID string) bool
+	setFlowControl(conf domain.SimConfig) error
+
+	occupancy() int
+	sampleOccupancy()
+	recordCreditBlocked(vChan int)
+	statistics() inputPortStats
 }
 
 type outputPort interface {
 	connection() Connection
-	allowedToSend(priority int) bool
+	credit(priority int) int
+	allowedToSend(cycle, priority int) bool
+	canAllocate(flit packet.Flit) bool
+	sentFlit(cycle int) (packet.Flit, bool)
+	faultBlocked(flit packet.Flit) bool
 	sendFlit(cycle int, flit packet.Flit) error
-	updateCredits()
+	transmitBuffered(cycle int)
+	updateCredits(cycle int)
 }
 
 type inputPortImpl struct {
-	conn   Connection
-	buff   buffer
+	conn Connection
+	buff buffer
+
+	// Location recorded against flits entering and leaving the buffer, events are not recorded when unset.
+	location string
+	// Called with each flit dropped by a fault on arrival, if set.
+	onDrop func(flit packet.Flit)
+	// Called with each flit added to the buffer and the cycle it arrived, if set.
+	onBuffer func(cycle int, flit packet.Flit)
+
+	// Packets whose tail flit is in the buffer, by packet ID.
+	bufferedTails map[string]bool
+
+	// Link flow control scheme, credit based when unset. Under on/off flow control the free flits at or below which a
+	// virtual channel signals off and at or above which it signals on, and the virtual channels signalled off. Under
+	// ack/nack flow control the virtual channels discarding arriving flits until their rejected flit, by flit ID, is
+	// retransmitted.
+	flowControl  domain.FlowControl
+	offThreshold int
+	onThreshold  int
+	signalledOff map[int]bool
+	rejecting    map[int]string
+
+	stats inputPortStats
+
 	logger zerolog.Logger
 }
 
+type inputPortStats struct {
+	samples int
+	vChans  map[int]*vChanStats
+}
+
+type vChanStats struct {
+	occupancySum  int
+	occupancyMax  int
+	creditBlocked int
+}
+
 type outputPortImpl struct {
-	conn    Connection
+	conn Connection
+	// Credits by downstream virtual channel, flits use the virtual channel vChans maps their priority to.
 	credits map[int]int
-	logger  zerolog.Logger
+	vChans  vChanMap
+
+	// Virtual cut-through & store-and-forward only, a header flit is only sent once its downstream virtual channel
+	// can hold the whole packet. Credits reserved, by downstream virtual channel, for the unsent flits of packets whose
+	// header flit has been sent.
+	wholePacket bool
+	reserved    map[int]int
+
+	// Output buffering only, flits which have crossed the router's crossbar queue by downstream virtual channel until
+	// the link carries them, up to bufferDepth flits per virtual channel and up to speedup flits entering per cycle.
+	bufferDepth      int
+	speedup          int
+	buffered         map[int][]packet.Flit
+	crossedCycle     int
+	crossedThisCycle int
+
+	// Link flow control scheme, credit based when unset. Virtual channels signalled off under on/off flow control.
+	// Under ack/nack flow control the flits sent and not yet acknowledged, and the flits awaiting retransmission after
+	// a rejection, by downstream virtual channel, with at most window flits unacknowledged per virtual channel, the
+	// downstream virtual channel's depth when unset.
+	flowControl    domain.FlowControl
+	signalledOff   map[int]bool
+	unacked        map[int][]packet.Flit
+	retransmitting map[int][]packet.Flit
+	window         int
+
+	lastSentFlit  packet.Flit
+	lastSentCycle int
+
+	logger zerolog.Logger
 }
 
 func newInputPort(conn Connection, buff buffer, logger zerolog.Logger) (*inputPortImpl, error) {
@@ -43,22 +122,26 @@ func newInputPort(conn Connection, buff buffer, logger zerolog.Logger) (*inputPo
 
 	for priority, credChan := range conn.creditChannels() {
 		localLogger.Trace().
-			Int("priority", priority).Int("capacity", cap(credChan)).Int("Credit", buff.vChanCapacity()).
+			Int("priority", priority).Int("capacity", cap(credChan)).Int("Credit", buff.vChanCapacity(priority)).
 			Msg("publishing input port virtual channel credits to connection source object")
 
-		credChan <- buff.vChanCapacity()
+		credChan <- buff.vChanCapacity(priority)
 	}
 	localLogger.Trace().Msg("published input port buffer capacity to connection source")
 
 	localLogger.Trace().Msg("new input port")
 	return &inputPortImpl{
-		conn:   conn,
-		buff:   buff,
-		logger: localLogger,
+		conn:          conn,
+		buff:          buff,
+		bufferedTails: make(map[string]bool),
+		signalledOff:  make(map[int]bool),
+		rejecting:     make(map[int]string),
+		stats:         inputPortStats{vChans: make(map[int]*vChanStats)},
+		logger:        localLogger,
 	}, nil
 }
 
-func newOutputPort(conn Connection, maxPriority int, logger zerolog.Logger) (*outputPortImpl, error) {
+func newOutputPort(conn Connection, numVChans int, logger zerolog.Logger) (*outputPortImpl, error) {
 	localLogger := logger.With().Str("port", "output_port").Logger()
 
 	if conn == nil {
@@ -68,9 +151,17 @@ func newOutputPort(conn Connection, maxPriority int, logger zerolog.Logger) (*ou
 
 	localLogger.Trace().Msg("new output port")
 	return &outputPortImpl{
-		conn:    conn,
-		credits: make(map[int]int, maxPriority),
-		logger:  localLogger,
+		conn:     conn,
+		credits:  make(map[int]int, numVChans),
+		reserved: make(map[int]int, numVChans),
+		speedup:  1,
+		buffered: make(map[int][]packet.Flit, numVChans),
+
+		signalledOff:   make(map[int]bool),
+		unacked:        make(map[int][]packet.Flit, numVChans),
+		retransmitting: make(map[int][]packet.Flit, numVChans),
+
+		logger: localLogger,
 	}, nil
 }
 
@@ -79,12 +170,43 @@ func (i *inputPortImpl) connection() Connection {
 }
 
 func (i *inputPortImpl) readIntoBuffer(cycle int) (err error) {
-	for len(i.conn.flitChannel()) > 0 {
-		flit := <-i.conn.flitChannel()
+	for {
+		flit, received := i.conn.receiveFlit(cycle)
+		if !received {
+			break
+		}
+
+		vChan := i.buff.vChan(flit.Priority())
+		if i.conn.dropFlit(flit) {
+			i.releaseDropped(cycle, vChan, flit)
+			if i.onDrop != nil {
+				i.onDrop(flit)
+			}
+			continue
+		}
+
+		if i.flowControl == domain.AckNackFlowControl && !i.acknowledge(cycle, vChan, flit) {
+			continue
+		}
 
 		if err = i.buff.addFlit(flit); err != nil {
 			return err
 		}
+		if flit.Type() == packet.TailFlitType {
+			i.bufferedTails[flit.PacketID()] = true
+		}
+		if i.onBuffer != nil {
+			i.onBuffer(cycle, flit)
+		}
+
+		if i.flowControl == domain.OnOffFlowControl && !i.signalledOff[vChan] && i.vChanFree(vChan) <= i.offThreshold {
+			i.signalledOff[vChan] = true
+			i.conn.pushSignal(cycle, flowSignal{vChan: vChan, kind: signalOff})
+		}
+
+		if i.location != "" {
+			flit.RecordEvent(cycle, packet.FlitBuffered, i.location)
+		}
 
 		i.logger.Debug().
 			Int("cycle", cycle).Str("flit", flit.ID()).Str("type", flit.Type().String()).
@@ -93,44 +215,359 @@ func (i *inputPortImpl) readIntoBuffer(cycle int) (err error) {
 	return nil
 }
 
-func (i *inputPortImpl) peakBuffer(priority int) (packet.Flit, bool) {
-	return i.buff.peakFlit(priority)
+func (i *inputPortImpl) vChanCount() int {
+	return i.buff.vChanCount()
+}
+
+func (i *inputPortImpl) peakBuffer(vChan int) (packet.Flit, bool) {
+	return i.buff.peakFlit(vChan)
 }
 
-func (i *inputPortImpl) readOutOfBuffer(cycle, priority int) (packet.Flit, bool) {
-	flit, exists := i.buff.popFlit(priority)
+func (i *inputPortImpl) readOutOfBuffer(cycle, vChan int) (packet.Flit, bool) {
+	flit, exists := i.buff.popFlit(vChan)
 	if exists {
 		i.logger.Trace().
 			Int("cycle", cycle).Str("flit", flit.ID()).Str("type", flit.Type().String()).
 			Msg("flit read out of buffer")
 
-		i.conn.creditChannel(flit.Priority()) <- 1
+		switch i.flowControl {
+		case domain.OnOffFlowControl:
+			if i.signalledOff[vChan] && i.vChanFree(vChan) >= i.onThreshold {
+				delete(i.signalledOff, vChan)
+				i.conn.pushSignal(cycle, flowSignal{vChan: vChan, kind: signalOn})
+			}
+		case domain.AckNackFlowControl:
+		default:
+			i.conn.pushCredit(cycle, vChan)
+		}
+
+		if flit.Type() == packet.TailFlitType {
+			delete(i.bufferedTails, flit.PacketID())
+		}
+
+		if i.location != "" {
+			flit.RecordEvent(cycle, packet.FlitForwarded, i.location)
+		}
 	}
 	return flit, exists
 }
 
+// Returns the number of free flits in the virtual channel.
+func (i *inputPortImpl) vChanFree(vChan int) int {
+	return i.buff.vChanCapacity(vChan) - i.buff.vChanOccupancy(vChan)
+}
+
+// Configures the port's link flow control scheme, failing if the on/off thresholds do not suit the port's connection
+// and virtual channel depths.
+func (i *inputPortImpl) setFlowControl(conf domain.SimConfig) error {
+	i.flowControl = conf.FlowControlScheme()
+	if i.flowControl != domain.OnOffFlowControl {
+		return nil
+	}
+
+	depth := i.buff.vChanCapacity(1)
+	for vc := 2; vc <= i.buff.vChanCount(); vc++ {
+		depth = min(depth, i.buff.vChanCapacity(vc))
+	}
+
+	off, on, err := onOffThresholds(conf, i.conn, depth)
+	if err != nil {
+		i.logger.Error().Err(err).Msg("invalid on/off flow control thresholds")
+		return err
+	}
+	i.offThreshold, i.onThreshold = off, on
+
+	return nil
+}
+
+// Releases a flit dropped by a fault: its credit is immediately returned under credit based flow control, and it is
+// acknowledged under ack/nack flow control unless the virtual channel is discarding it until a retransmission.
+func (i *inputPortImpl) releaseDropped(cycle, vChan int, flit packet.Flit) {
+	switch i.flowControl {
+	case domain.OnOffFlowControl:
+	case domain.AckNackFlowControl:
+		if rejected, rejecting := i.rejecting[vChan]; rejecting {
+			if flit.ID() != rejected {
+				return
+			}
+			delete(i.rejecting, vChan)
+		}
+		i.conn.pushSignal(cycle, flowSignal{vChan: vChan, kind: signalAck, flitID: flit.ID()})
+	default:
+		i.conn.pushCredit(cycle, vChan)
+	}
+}
+
+// Acknowledges the arriving flit if its virtual channel has space for it, otherwise rejects it, discarding every
+// following flit until it is retransmitted. Returns true if the flit is to be buffered.
+func (i *inputPortImpl) acknowledge(cycle, vChan int, flit packet.Flit) bool {
+	if rejected, rejecting := i.rejecting[vChan]; rejecting {
+		if flit.ID() != rejected {
+			return false
+		}
+		delete(i.rejecting, vChan)
+	}
+
+	if i.vChanFree(vChan) < 1 {
+		i.rejecting[vChan] = flit.ID()
+		i.conn.pushSignal(cycle, flowSignal{vChan: vChan, kind: signalNack, flitID: flit.ID()})
+		return false
+	}
+
+	i.conn.pushSignal(cycle, flowSignal{vChan: vChan, kind: signalAck, flitID: flit.ID()})
+	return true
+}
+
+// Reports whether the packet's tail flit, and so the whole packet, is in the buffer.
+func (i *inputPortImpl) packetBuffered(packetID string) bool {
+	return i.bufferedTails[packetID]
+}
+
+// Returns the number of flits buffered across every virtual channel.
+func (i *inputPortImpl) occupancy() int {
+	occupancy := 0
+	for vc := 1; vc <= i.buff.vChanCount(); vc++ {
+		occupancy += i.buff.vChanOccupancy(vc)
+	}
+	return occupancy
+}
+
+// Samples each virtual channel's buffer occupancy, expected to be called once per cycle.
+func (i *inputPortImpl) sampleOccupancy() {
+	i.stats.samples++
+
+	for vc := 1; vc <= i.buff.vChanCount(); vc++ {
+		vChan := i.stats.vChan(vc)
+
+		occupancy := i.buff.vChanOccupancy(vc)
+		vChan.occupancySum += occupancy
+		vChan.occupancyMax = max(vChan.occupancyMax, occupancy)
+	}
+}
+
+func (i *inputPortImpl) recordCreditBlocked(vChan int) {
+	i.stats.vChan(vChan).creditBlocked++
+}
+
+func (i *inputPortImpl) statistics() inputPortStats {
+	return i.stats
+}
+
+func (s *inputPortStats) vChan(vChan int) *vChanStats {
+	if _, exists := s.vChans[vChan]; !exists {
+		s.vChans[vChan] = &vChanStats{}
+	}
+	return s.vChans[vChan]
+}
+
+func (s *vChanStats) meanOccupancy(samples int) float64 {
+	if samples == 0 {
+		return 0
+	}
+	return float64(s.occupancySum) / float64(samples)
+}
+
 func (o *outputPortImpl) connection() Connection {
 	return o.conn
 }
 
-func (o *outputPortImpl) allowedToSend(priority int) bool {
-	return o.credits[priority] > 0 && len(o.conn.flitChannel()) < cap(o.conn.flitChannel())
+// Returns the credits of the downstream virtual channel the priority's flits use, or with output buffering the space
+// left in the priority's output virtual channel buffer.
+func (o *outputPortImpl) credit(priority int) int {
+	vChan := o.vChans.vChan(priority)
+	if o.bufferDepth > 0 {
+		return o.bufferDepth - len(o.buffered[vChan])
+	}
+	return o.linkCredit(vChan)
 }
 
+// Returns the number of flits the port's flow control allows it to send over its link on the downstream virtual
+// channel: its credits, 1 while signalled on under on/off flow control, or under ack/nack flow control the flits left in
+// its window, none while it has flits awaiting retransmission.
+func (o *outputPortImpl) linkCredit(vChan int) int {
+	switch o.flowControl {
+	case domain.OnOffFlowControl:
+		if o.signalledOff[vChan] {
+			return 0
+		}
+		return 1
+	case domain.AckNackFlowControl:
+		if len(o.retransmitting[vChan]) > 0 {
+			return 0
+		}
+		window := o.window
+		if window < 1 {
+			window = o.credits[vChan]
+		}
+		return window - len(o.unacked[vChan])
+	default:
+		return o.credits[vChan]
+	}
+}
+
+func (o *outputPortImpl) allowedToSend(cycle, priority int) bool {
+	if _, down := o.conn.down(); down {
+		return false
+	}
+	if o.bufferDepth > 0 {
+		return o.credit(priority) > 0 && (cycle != o.crossedCycle || o.crossedThisCycle < o.speedup)
+	}
+	return o.credit(priority) > 0 && o.conn.acceptsFlit(cycle)
+}
+
+// Reports whether the flit may be sent once allowed to, a header flit under whole packet switching requires the
+// downstream virtual channel's unreserved credits to cover its packet.
+func (o *outputPortImpl) canAllocate(flit packet.Flit) bool {
+	if !o.wholePacket || flit.Type() != packet.HeaderFlitType {
+		return true
+	}
+
+	// Output buffered flits have yet to consume their downstream credits.
+	vChan := o.vChans.vChan(flit.Priority())
+	return o.credits[vChan]-len(o.buffered[vChan])-o.reserved[vChan] >= packetSize(flit)
+}
+
+// Sends the flit over the port's link, or with output buffering queues it in the port's output buffer.
 func (o *outputPortImpl) sendFlit(cycle int, flit packet.Flit) error {
-	if o.allowedToSend(flit.Priority()) {
-		o.credits[flit.Priority()]--
-		o.conn.flitChannel() <- flit
+	if o.allowedToSend(cycle, flit.Priority()) {
+		vChan := o.vChans.vChan(flit.Priority())
+		if o.wholePacket {
+			if flit.Type() == packet.HeaderFlitType {
+				o.reserved[vChan] += packetSize(flit) - 1
+			} else if o.reserved[vChan] > 0 {
+				o.reserved[vChan]--
+			}
+		}
+
+		if o.bufferDepth > 0 {
+			o.buffered[vChan] = append(o.buffered[vChan], flit)
+			if cycle == o.crossedCycle {
+				o.crossedThisCycle++
+			} else {
+				o.crossedCycle = cycle
+				o.crossedThisCycle = 1
+			}
+		} else {
+			o.transmit(cycle, vChan, flit)
+		}
+
+		o.lastSentFlit = flit
+		o.lastSentCycle = cycle
 		return nil
 	} else {
 		return domain.ErrPortNoCredit
 	}
 }
 
-func (o *outputPortImpl) updateCredits() {
-	for priority := range o.conn.creditChannels() {
-		for len(o.conn.creditChannel(priority)) > 0 {
-			o.credits[priority] += <-o.conn.creditChannel(priority)
+// Resends the flits awaiting retransmission, then sends the output buffered flits, the link can carry during the cycle,
+// highest priority first, output buffered flits once their flow control allows.
+func (o *outputPortImpl) transmitBuffered(cycle int) {
+	if _, down := o.conn.down(); down {
+		return
+	}
+
+	for o.conn.acceptsFlit(cycle) {
+		vChan := highestPriorityQueue(o.retransmitting, func(int) bool { return true })
+		if vChan == 0 {
+			break
+		}
+
+		flit := o.retransmitting[vChan][0]
+		o.retransmitting[vChan] = o.retransmitting[vChan][1:]
+		o.transmit(cycle, vChan, flit)
+		o.conn.recordRetransmission()
+	}
+
+	if o.bufferDepth < 1 {
+		return
+	}
+
+	for o.conn.acceptsFlit(cycle) {
+		vChan := highestPriorityQueue(o.buffered, func(vc int) bool { return o.linkCredit(vc) > 0 })
+		if vChan == 0 {
+			return
+		}
+
+		flit := o.buffered[vChan][0]
+		o.buffered[vChan] = o.buffered[vChan][1:]
+		o.transmit(cycle, vChan, flit)
+	}
+}
+
+// Returns the virtual channel whose queue's head flit has the highest priority, ties by lowest virtual channel, among
+// the non empty queues whose virtual channel is ready, 0 if there are none.
+func highestPriorityQueue(queues map[int][]packet.Flit, ready func(vChan int) bool) int {
+	vChan := 0
+	for vc, flits := range queues {
+		if len(flits) == 0 || !ready(vc) {
+			continue
+		}
+		if vChan == 0 || flits[0].Priority() < queues[vChan][0].Priority() ||
+			(flits[0].Priority() == queues[vChan][0].Priority() && vc < vChan) {
+			vChan = vc
+		}
+	}
+	return vChan
+}
+
+// Sends the flit over the port's link, consuming a credit of its downstream virtual channel, or under ack/nack flow
+// control keeping it until it is acknowledged.
+func (o *outputPortImpl) transmit(cycle, vChan int, flit packet.Flit) {
+	switch o.flowControl {
+	case domain.OnOffFlowControl:
+	case domain.AckNackFlowControl:
+		o.unacked[vChan] = append(o.unacked[vChan], flit)
+	default:
+		o.credits[vChan]--
+	}
+
+	o.conn.pushFlit(cycle, flit)
+	o.conn.recordFlit(cycle, flit)
+}
+
+// Returns true if the port's link is down, recording the waiting flit's packet as affected by the fault.
+func (o *outputPortImpl) faultBlocked(flit packet.Flit) bool {
+	fault, down := o.conn.down()
+	if down {
+		o.conn.recordFaultAffected(flit.PacketID(), fault)
+	}
+	return down
+}
+
+// Returns the flit sent through the port during the cycle, if any.
+func (o *outputPortImpl) sentFlit(cycle int) (packet.Flit, bool) {
+	if o.lastSentFlit == nil || o.lastSentCycle != cycle {
+		return nil, false
+	}
+	return o.lastSentFlit, true
+}
+
+// Returns the number of flits in the header flit's packet, 1 if unknown.
+func packetSize(flit packet.Flit) int {
+	if header, ok := flit.(packet.HeaderFlit); ok && header.PacketSize() > 0 {
+		return header.PacketSize()
+	}
+	return 1
+}
+
+// Collects the credits and flow control signals which have returned over the port's link by the cycle.
+func (o *outputPortImpl) updateCredits(cycle int) {
+	o.conn.receiveCredits(cycle, o.credits)
+
+	for _, signal := range o.conn.receiveSignals(cycle) {
+		switch signal.kind {
+		case signalOn:
+			delete(o.signalledOff, signal.vChan)
+		case signalOff:
+			o.signalledOff[signal.vChan] = true
+		case signalAck:
+			o.unacked[signal.vChan] = slices.DeleteFunc(o.unacked[signal.vChan], func(flit packet.Flit) bool {
+				return flit.ID() == signal.flitID
+			})
+		case signalNack:
+			// Go back to the rejected flit, every flit sent after it has been discarded.
+			o.retransmitting[signal.vChan] = append(o.unacked[signal.vChan], o.retransmitting[signal.vChan]...)
+			o.unacked[signal.vChan] = nil
 		}
 	}
 }
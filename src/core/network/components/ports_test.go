@@ -64,6 +64,21 @@ func TestNewInputPort(t *testing.T) {
 		assert.Equal(t, buff, port.buff)
 	})
 
+	t.Run("PartitionedBufferCredits", func(t *testing.T) {
+		buff, err := newPartitionedBuffer([]int{8, 4, 2}, nil, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		conn, err := NewConnection(3, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		_, err = newInputPort(conn, buff, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		credits := make(map[int]int)
+		conn.receiveCredits(0, credits)
+		assert.Equal(t, map[int]int{1: 8, 2: 4, 3: 2}, credits)
+	})
+
 	t.Run("NilConnection", func(t *testing.T) {
 		buff, err := newBuffer(1, 1, zerolog.New(io.Discard))
 		require.NoError(t, err)
@@ -174,6 +189,58 @@ func TestInputPortReadIntoBuffer(t *testing.T) {
 		err = port.readIntoBuffer(0)
 		require.Error(t, err)
 	})
+
+	t.Run("RecordsEvents", func(t *testing.T) {
+		port := testInputPort(t, 2, 1)
+		port.location = "n1"
+
+		flit := packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+		port.conn.flitChannel() <- flit
+
+		err := port.readIntoBuffer(4)
+		require.NoError(t, err)
+
+		_, exists := port.readOutOfBuffer(6, 1)
+		require.True(t, exists)
+
+		assert.Equal(t, packet.FlitEvents{
+			{Cycle: 4, Event: packet.FlitBuffered, Location: "n1"},
+			{Cycle: 6, Event: packet.FlitForwarded, Location: "n1"},
+		}, flit.Events())
+	})
+
+	t.Run("NoLocationNoEvents", func(t *testing.T) {
+		port := testInputPort(t, 2, 1)
+
+		flit := packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+		port.conn.flitChannel() <- flit
+
+		err := port.readIntoBuffer(4)
+		require.NoError(t, err)
+
+		assert.Empty(t, flit.Events())
+	})
+}
+
+func TestInputPortPacketBuffered(t *testing.T) {
+	t.Parallel()
+
+	port := testInputPort(t, 2, 1)
+	pkt := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 2, zerolog.New(io.Discard))
+
+	port.conn.flitChannel() <- pkt.Flits()[0]
+	require.NoError(t, port.readIntoBuffer(0))
+	assert.False(t, port.packetBuffered(pkt.ID()))
+
+	port.conn.flitChannel() <- pkt.Flits()[1]
+	require.NoError(t, port.readIntoBuffer(1))
+	assert.True(t, port.packetBuffered(pkt.ID()))
+
+	for i := 0; i < 2; i++ {
+		_, exists := port.readOutOfBuffer(2+i, 1)
+		require.True(t, exists)
+	}
+	assert.False(t, port.packetBuffered(pkt.ID()))
 }
 
 func TestInputPortPeakBuffer(t *testing.T) {
@@ -259,7 +326,7 @@ func TestOutputPortAllowedToSend(t *testing.T) {
 
 		port.credits[priority] = 1
 
-		assert.True(t, port.allowedToSend(priority))
+		assert.True(t, port.allowedToSend(0, priority))
 		port.credits[priority]--
 		port.conn.flitChannel() <- packet.NewTailFlit("t", "AA", 2, priority, zerolog.New(io.Discard))
 	})
@@ -271,7 +338,19 @@ func TestOutputPortAllowedToSend(t *testing.T) {
 
 		port.credits[priority] = 0
 
-		assert.False(t, port.allowedToSend(priority))
+		assert.False(t, port.allowedToSend(0, priority))
+	})
+
+	t.Run("SharedVChan", func(t *testing.T) {
+		port := testOutputPort(t, 1)
+		port.vChans = vChanMap{1, 1}
+
+		port.credits[1] = 1
+
+		assert.True(t, port.allowedToSend(0, 2))
+		require.NoError(t, port.sendFlit(0, packet.NewTailFlit("t", "AA", 2, 2, zerolog.New(io.Discard))))
+		assert.Equal(t, 0, port.credits[1])
+		assert.False(t, port.allowedToSend(1, 1))
 	})
 }
 
@@ -310,6 +389,196 @@ func TestOutputPortSendFlit(t *testing.T) {
 	})
 }
 
+func TestOutputPortBuffered(t *testing.T) {
+	t.Parallel()
+
+	port := testOutputPort(t, 2)
+	port.bufferDepth = 2
+	port.speedup = 2
+	port.credits[1] = 1
+	port.credits[2] = 1
+
+	low := packet.NewPacket("tl", "AA", 2, 100, domain.Route{"n1", "n2"}, 2, zerolog.New(io.Discard)).Flits()
+	high := packet.NewPacket("th", "AB", 1, 100, domain.Route{"n1", "n2"}, 2, zerolog.New(io.Discard)).Flits()
+
+	// Up to the speedup's flits cross into the output buffer per cycle, regardless of the link.
+	require.NoError(t, port.sendFlit(0, low[0]))
+	require.NoError(t, port.sendFlit(0, low[1]))
+	assert.False(t, port.allowedToSend(0, 1))
+	assert.Equal(t, 0, port.credit(2))
+	assert.Empty(t, port.conn.flitChannel())
+
+	require.NoError(t, port.sendFlit(1, high[0]))
+	require.NoError(t, port.sendFlit(1, high[1]))
+
+	// The link carries the highest priority buffered flit with a downstream credit.
+	port.transmitBuffered(1)
+	gotFlit := <-port.conn.flitChannel()
+	assert.Equal(t, high[0].ID(), gotFlit.ID())
+
+	port.transmitBuffered(2)
+	gotFlit = <-port.conn.flitChannel()
+	assert.Equal(t, low[0].ID(), gotFlit.ID())
+
+	// Both downstream virtual channels are out of credits.
+	port.transmitBuffered(3)
+	assert.Empty(t, port.conn.flitChannel())
+	assert.Equal(t, 1, port.credit(1))
+	assert.Equal(t, 1, port.credit(2))
+}
+
+func TestInputPortOnOffFlowControl(t *testing.T) {
+	t.Parallel()
+
+	port := testInputPort(t, 3, 1)
+	require.NoError(t, port.setFlowControl(domain.SimConfig{FlowControl: domain.OnOffFlowControl, OnThreshold: 2}))
+	conn := port.conn.(*connectionImpl)
+
+	flits := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 3, zerolog.New(io.Discard)).Flits()
+	for cycle, flit := range flits {
+		conn.flitChannel() <- flit
+		require.NoError(t, port.readIntoBuffer(cycle))
+	}
+	assert.Equal(t, []flowSignal{{vChan: 1, kind: signalOff}}, conn.receiveSignals(3))
+
+	// The virtual channel signals on once its free space reaches the on threshold, not on every freed flit.
+	_, exists := port.readOutOfBuffer(3, 1)
+	require.True(t, exists)
+	assert.Empty(t, conn.receiveSignals(4))
+
+	_, exists = port.readOutOfBuffer(4, 1)
+	require.True(t, exists)
+	assert.Equal(t, []flowSignal{{vChan: 1, kind: signalOn}}, conn.receiveSignals(5))
+	assert.Empty(t, conn.creditChannel(1))
+
+	t.Run("InvalidThresholds", func(t *testing.T) {
+		port := testInputPort(t, 3, 1)
+		require.Error(t, port.setFlowControl(domain.SimConfig{FlowControl: domain.OnOffFlowControl, OnThreshold: 4}))
+	})
+}
+
+func TestInputPortAckNackFlowControl(t *testing.T) {
+	t.Parallel()
+
+	port := testInputPort(t, 1, 1)
+	require.NoError(t, port.setFlowControl(domain.SimConfig{FlowControl: domain.AckNackFlowControl}))
+	conn := port.conn.(*connectionImpl)
+
+	flits := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 3, zerolog.New(io.Discard)).Flits()
+	for _, flit := range flits {
+		conn.flitChannel() <- flit
+		require.NoError(t, port.readIntoBuffer(0))
+	}
+
+	// The header flit is buffered, the body flit rejected and the tail flit discarded until the body flit returns.
+	assert.Equal(t, []flowSignal{
+		{vChan: 1, kind: signalAck, flitID: flits[0].ID()},
+		{vChan: 1, kind: signalNack, flitID: flits[1].ID()},
+	}, conn.receiveSignals(1))
+
+	_, exists := port.readOutOfBuffer(1, 1)
+	require.True(t, exists)
+
+	conn.flitChannel() <- flits[2]
+	require.NoError(t, port.readIntoBuffer(1))
+	conn.flitChannel() <- flits[1]
+	require.NoError(t, port.readIntoBuffer(2))
+
+	assert.Equal(t, []flowSignal{{vChan: 1, kind: signalAck, flitID: flits[1].ID()}}, conn.receiveSignals(3))
+	gotFlit, exists := port.peakBuffer(1)
+	require.True(t, exists)
+	assert.Equal(t, flits[1].ID(), gotFlit.ID())
+}
+
+func TestOutputPortOnOffFlowControl(t *testing.T) {
+	t.Parallel()
+
+	port := testOutputPort(t, 1)
+	port.flowControl = domain.OnOffFlowControl
+
+	flits := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 2, zerolog.New(io.Discard)).Flits()
+	require.True(t, port.allowedToSend(0, 1))
+	require.NoError(t, port.sendFlit(0, flits[0]))
+	<-port.conn.flitChannel()
+
+	port.conn.pushSignal(0, flowSignal{vChan: 1, kind: signalOff})
+	port.updateCredits(1)
+	assert.False(t, port.allowedToSend(1, 1))
+	assert.Equal(t, 0, port.credit(1))
+
+	port.conn.pushSignal(1, flowSignal{vChan: 1, kind: signalOn})
+	port.updateCredits(2)
+	assert.True(t, port.allowedToSend(2, 1))
+}
+
+func TestOutputPortAckNackFlowControl(t *testing.T) {
+	t.Parallel()
+
+	port := testOutputPort(t, 1)
+	port.flowControl = domain.AckNackFlowControl
+	port.credits[1] = 2
+
+	flits := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 3, zerolog.New(io.Discard)).Flits()
+	for cycle := 0; cycle < 2; cycle++ {
+		require.True(t, port.allowedToSend(cycle, 1))
+		require.NoError(t, port.sendFlit(cycle, flits[cycle]))
+		<-port.conn.flitChannel()
+	}
+
+	// The window of 2 unacknowledged flits is full.
+	assert.False(t, port.allowedToSend(2, 1))
+
+	port.conn.pushSignal(1, flowSignal{vChan: 1, kind: signalAck, flitID: flits[0].ID()})
+	port.conn.pushSignal(1, flowSignal{vChan: 1, kind: signalNack, flitID: flits[1].ID()})
+	port.updateCredits(2)
+
+	// New flits wait for the rejected flit's retransmission.
+	assert.Equal(t, 0, port.credit(1))
+	port.transmitBuffered(2)
+	gotFlit := <-port.conn.flitChannel()
+	assert.Equal(t, flits[1].ID(), gotFlit.ID())
+	assert.Equal(t, 1, port.conn.Retransmissions())
+	assert.Equal(t, 3, port.conn.FlitCount())
+
+	assert.True(t, port.allowedToSend(3, 1))
+	assert.Equal(t, 2, port.credits[1])
+}
+
+func TestOutputPortCanAllocate(t *testing.T) {
+	t.Parallel()
+
+	pktA := packet.NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 3, zerolog.New(io.Discard))
+	pktB := packet.NewPacket("t", "AB", 1, 100, domain.Route{"n1", "n2"}, 3, zerolog.New(io.Discard))
+
+	t.Run("Wormhole", func(t *testing.T) {
+		port := testOutputPort(t, 1)
+		port.credits[1] = 1
+
+		assert.True(t, port.canAllocate(pktA.Flits()[0]))
+	})
+
+	t.Run("WholePacket", func(t *testing.T) {
+		port := testOutputPort(t, 1)
+		port.wholePacket = true
+		port.credits[1] = 2
+
+		assert.False(t, port.canAllocate(pktA.Flits()[0]))
+
+		port.credits[1] = 5
+		require.True(t, port.canAllocate(pktA.Flits()[0]))
+		require.NoError(t, port.sendFlit(0, pktA.Flits()[0]))
+		<-port.conn.flitChannel()
+
+		// Packet A's remaining 2 flits are reserved, leaving 2 credits for packet B.
+		assert.Equal(t, 2, port.reserved[1])
+		assert.False(t, port.canAllocate(pktB.Flits()[0]))
+		assert.True(t, port.canAllocate(pktA.Flits()[1]))
+
+		require.NoError(t, port.sendFlit(1, pktA.Flits()[1]))
+		assert.Equal(t, 1, port.reserved[1])
+	})
+}
+
 func TestOutputPortUpdateCredits(t *testing.T) {
 	t.Parallel()
 
@@ -321,9 +590,62 @@ func TestOutputPortUpdateCredits(t *testing.T) {
 
 		for i := 0; i < credits; i++ {
 			port.conn.creditChannel(priority) <- 1
-			port.updateCredits()
+			port.updateCredits(0)
 		}
 
 		assert.Equal(t, credits, port.credits[priority])
 	})
 }
+
+func TestInputPortSampleOccupancy(t *testing.T) {
+	t.Parallel()
+
+	port := testInputPort(t, 2, 1)
+
+	port.sampleOccupancy()
+
+	port.buff.addFlit(packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard)))
+	port.buff.addFlit(packet.NewTailFlit("t", "AA", 1, 1, zerolog.New(io.Discard)))
+	port.sampleOccupancy()
+
+	port.recordCreditBlocked(1)
+
+	stats := port.statistics()
+	assert.Equal(t, 2, stats.samples)
+	assert.Equal(t, 2, stats.vChan(1).occupancyMax)
+	assert.InDelta(t, 1.0, stats.vChan(1).meanOccupancy(stats.samples), 0.0001)
+	assert.Equal(t, 1, stats.vChan(1).creditBlocked)
+}
+
+func TestOutputPortSendFlitCountsFlit(t *testing.T) {
+	t.Parallel()
+
+	port := testOutputPort(t, 1)
+	port.credits[1] = 1
+
+	err := port.sendFlit(0, packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard)))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, port.conn.FlitCount())
+	assert.Equal(t, 0, port.credit(1))
+}
+
+func TestOutputPortSentFlit(t *testing.T) {
+	t.Parallel()
+
+	port := testOutputPort(t, 2)
+	port.credits[1] = 1
+
+	_, exists := port.sentFlit(3)
+	assert.False(t, exists)
+
+	flit := packet.NewHeaderFlit("t", "AA", 0, 1, 100, domain.Route{"n1", "n2"}, zerolog.New(io.Discard))
+	require.NoError(t, port.sendFlit(3, flit))
+
+	sent, exists := port.sentFlit(3)
+	require.True(t, exists)
+	assert.Equal(t, flit, sent)
+
+	_, exists = port.sentFlit(4)
+	assert.False(t, exists)
+}
@@ -17,9 +17,17 @@ type Router interface {
 	SetNetworkInterface(netIntfc NetworkInterface) error
 
 	UpdateOutputMap()
-	UpdateOutputPortsCredit() error
+	UpdateOutputPortsCredit(cycle int) error
 	ReadFromInputPorts(cycle int) error
 	RouteBufferedFlits(cycle int) error
+
+	BufferStats() []domain.BufferStats
+	Interference() map[string]map[string]int
+
+	EnableLinkTraversalLog()
+	LinkTraversals() map[domain.Link][]domain.LinkTraversal
+
+	InjectFaults(faults *FaultInjector)
 }
 
 type routerImpl struct {
@@ -30,14 +38,28 @@ type routerImpl struct {
 
 	outputMapSync sync.Once
 	outputMap     map[string]outputPort
+	// IDs of the network interfaces attached to the router's local ports.
+	netIntfcs map[string]bool
 
 	// Configuration Constants
 	simConf domain.SimConfig
+	vChans  vChanMap
 
 	// Internal Operation
-	headerFlitsProcessings       map[string]int
-	headerFlitsProcessedPerCycle map[string]bool
-	packetsNextRouter            map[string]string
+	arbiter Arbiter
+	// Cycles each buffered flit has spent in the router pipeline's stages at the head of its virtual channel.
+	flitStageCycles map[string]int
+	// Cycle each flit was buffered in, as body flits record no events.
+	flitBufferedCycles     map[string]int
+	flitsProcessedPerCycle map[string]bool
+	packetsNextRouter      map[string]string
+	// Header flit of the packet holding each output port, non-preemptive switching only.
+	outputLocks map[outputPort]packet.Flit
+
+	// Statistics
+	interference interferenceRecord
+
+	faults *FaultInjector
 
 	// Utility
 	logger zerolog.Logger
@@ -45,30 +67,44 @@ type routerImpl struct {
 
 type RouterConfig struct {
 	NodeID string
+	// Number of network interfaces the router creates for itself, each attached to its own local port.
+	LocalPorts int
 	domain.SimConfig
 }
 
 func newRouter(conf RouterConfig, logger zerolog.Logger) (*routerImpl, error) {
-	if err := validBufferSize(conf.BufferSize, conf.MaxPriority); err != nil {
+	if _, err := bufferVChanDepths(conf.SimConfig, conf.BufferSize); err != nil {
 		return nil, err
 	}
 
-	if conf.ProcessingDelay < 1 {
+	if latency, _ := conf.PipelineDelay(true); latency < 1 {
 		return nil, errors.Join(domain.ErrInvalidParameter, errors.New("router processing delay less then 1"))
 	}
 
+	arbiter, err := newArbiter(conf.SimConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	rtr := routerImpl{
 		nodeID:      conf.NodeID,
 		inputPorts:  make([]inputPort, 0),
 		outputPorts: make([]outputPort, 0),
 
 		outputMap: make(map[string]outputPort),
+		netIntfcs: make(map[string]bool),
 
 		simConf: conf.SimConfig,
+		vChans:  conf.VChanMap(),
 
-		headerFlitsProcessings:       make(map[string]int),
-		headerFlitsProcessedPerCycle: make(map[string]bool),
-		packetsNextRouter:            make(map[string]string),
+		arbiter:                arbiter,
+		flitStageCycles:        make(map[string]int),
+		flitBufferedCycles:     make(map[string]int),
+		flitsProcessedPerCycle: make(map[string]bool),
+		packetsNextRouter:      make(map[string]string),
+		outputLocks:            make(map[outputPort]packet.Flit),
+
+		interference: make(interferenceRecord),
 
 		logger: logger.With().Str("component", "router").Str("node_id", conf.NodeID).Logger(),
 	}
@@ -82,7 +118,17 @@ func (r *routerImpl) NodeID() string {
 }
 
 func (r *routerImpl) RegisterInputPort(conn Connection) error {
-	buff, err := newBuffer(r.simConf.BufferSize, r.simConf.MaxPriority, r.logger)
+	bufferSize := r.simConf.BufferSize
+	if conn != nil && conn.bufferSize() > 0 {
+		bufferSize = conn.bufferSize()
+	}
+
+	vChanDepths, err := bufferVChanDepths(r.simConf, bufferSize)
+	if err != nil {
+		return err
+	}
+
+	buff, err := newPartitionedBuffer(vChanDepths, r.vChans, r.logger)
 	if err != nil {
 		return err
 	}
@@ -91,6 +137,14 @@ func (r *routerImpl) RegisterInputPort(conn Connection) error {
 	if err != nil {
 		return err
 	}
+	port.location = r.NodeID()
+	port.onDrop = r.arbiter.dropped
+	port.onBuffer = func(cycle int, flit packet.Flit) {
+		r.flitBufferedCycles[flit.ID()] = cycle
+	}
+	if err := port.setFlowControl(r.simConf); err != nil {
+		return err
+	}
 
 	conn.SetDstRouter(r.NodeID())
 
@@ -100,10 +154,16 @@ func (r *routerImpl) RegisterInputPort(conn Connection) error {
 }
 
 func (r *routerImpl) RegisterOutputPort(conn Connection) error {
-	port, err := newOutputPort(conn, r.simConf.MaxPriority, r.logger)
+	port, err := newOutputPort(conn, r.simConf.VCCount(), r.logger)
 	if err != nil {
 		return err
 	}
+	port.vChans = r.vChans
+	port.wholePacket = r.simConf.WholePacketSwitching()
+	port.bufferDepth = r.simConf.OutputBufferDepth
+	port.speedup = r.simConf.Speedup()
+	port.flowControl = r.simConf.FlowControlScheme()
+	port.window = r.simConf.RetransmissionWindow
 	conn.SetSrcRouter(r.NodeID())
 
 	r.outputPorts = append(r.outputPorts, port)
@@ -125,7 +185,7 @@ func (r *routerImpl) SetNetworkInterface(netIntfc NetworkInterface) error {
 		return domain.ErrNilParameter
 	}
 
-	inConn, err := NewConnection(r.simConf.MaxPriority, r.logger)
+	inConn, err := NewConnection(r.simConf.VCCount(), r.logger)
 	if err != nil {
 		return err
 	}
@@ -140,7 +200,7 @@ func (r *routerImpl) SetNetworkInterface(netIntfc NetworkInterface) error {
 		return err
 	}
 
-	outConn, err := NewConnection(r.simConf.MaxPriority, r.logger)
+	outConn, err := NewConnection(r.simConf.VCCount(), r.logger)
 	if err != nil {
 		return err
 	}
@@ -155,80 +215,140 @@ func (r *routerImpl) SetNetworkInterface(netIntfc NetworkInterface) error {
 		return err
 	}
 
+	r.netIntfcs[netIntfc.NodeID()] = true
 	return nil
 }
 
-func (r *routerImpl) UpdateOutputPortsCredit() error {
+func (r *routerImpl) UpdateOutputPortsCredit(cycle int) error {
 	for i := 0; i < len(r.outputPorts); i++ {
-		r.outputPorts[i].updateCredits()
+		r.outputPorts[i].updateCredits(cycle)
 	}
 
 	return nil
 }
 
 func (r *routerImpl) RouteBufferedFlits(cycle int) error {
-	r.headerFlitsProcessedPerCycle = make(map[string]bool)
-
-	for p := 1; p <= r.simConf.MaxPriority; p++ {
-		for i := 0; i < len(r.inputPorts); i++ {
-			if flit, exists := r.inputPorts[i].peakBuffer(p); exists {
-				// Routing Header Flits
-				if flit.Type() == packet.HeaderFlitType {
-					ready, err := r.processHeaderFlit(flit.(packet.HeaderFlit))
-					if err != nil {
-						r.logger.Error().Err(err).Int("cycle", cycle).Str("flit", flit.ID()).Msg("error routing header flit")
-						return err
-					}
-					if !ready {
-						continue
-					}
-				}
+	r.flitsProcessedPerCycle = make(map[string]bool)
 
-				// Performing Arbitration
-				if err := r.arbitrateFlit(cycle, i, flit); err != nil {
-					return err
-				}
-			}
+	if fault, stalled := r.faults.routerStalled(r.NodeID()); stalled {
+		for _, head := range r.bufferedHeads(cycle) {
+			r.faults.RecordAffected(head.flit.PacketID(), fault)
+		}
+
+		r.logger.Trace().Int("cycle", cycle).Msg("router stalled")
+		return nil
+	}
+
+	for _, head := range r.bufferedHeads(cycle) {
+		flit := head.flit
+
+		if flit.Type() == packet.HeaderFlitType && r.simConf.SwitchingMode() == domain.StoreAndForwardSwitching &&
+			!r.inputPorts[head.inputPort].packetBuffered(flit.PacketID()) {
+			continue
 		}
+
+		// Pipeline Stages, Routing Header Flits
+		ready, err := r.processFlit(cycle, flit)
+		if err != nil {
+			r.logger.Error().Err(err).Int("cycle", cycle).Str("flit", flit.ID()).Msg("error routing header flit")
+			return err
+		}
+		if !ready {
+			continue
+		}
+
+		// Performing Arbitration
+		if err := r.arbitrateFlit(cycle, head); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(r.outputPorts); i++ {
+		r.outputPorts[i].transmitBuffered(cycle)
 	}
 
 	return nil
 }
 
-func (r *routerImpl) processHeaderFlit(flit packet.HeaderFlit) (bool, error) {
-	if _, previouslyProcessed := r.headerFlitsProcessedPerCycle[flit.ID()]; !previouslyProcessed {
-		if _, exists := r.headerFlitsProcessings[flit.ID()]; exists {
-			r.headerFlitsProcessings[flit.ID()]++
-		} else {
-			r.headerFlitsProcessings[flit.ID()] = 1
+// The flit at the head of an input port's virtual channel, and the ID of the router or network interface its output
+// port leads to, empty if it cannot be routed.
+type bufferedHead struct {
+	inputPort int
+	vChan     int
+	flit      packet.Flit
+	output    string
+}
+
+// Returns the flits at the head of every input port's virtual channels, in the router's arbitration order.
+func (r *routerImpl) bufferedHeads(cycle int) []bufferedHead {
+	heads := make([]bufferedHead, 0, len(r.inputPorts))
+	for i := 0; i < len(r.inputPorts); i++ {
+		for vc := 1; vc <= r.inputPorts[i].vChanCount(); vc++ {
+			if flit, exists := r.inputPorts[i].peakBuffer(vc); exists {
+				heads = append(heads, bufferedHead{inputPort: i, vChan: vc, flit: flit, output: r.flitOutput(flit)})
+			}
 		}
+	}
 
-		r.headerFlitsProcessedPerCycle[flit.ID()] = true
+	return r.arbiter.order(cycle, heads)
+}
 
-		if r.headerFlitsProcessings[flit.ID()] >= r.simConf.ProcessingDelay {
-			outPort, err := r.routeFlit(flit)
-			if err != nil {
-				return false, err
-			}
+// Returns the ID of the router or network interface the flit's output port leads to, routing header flits which have
+// not yet been routed, or empty if the flit cannot be routed.
+func (r *routerImpl) flitOutput(flit packet.Flit) string {
+	if header, isHeader := flit.(packet.HeaderFlit); isHeader {
+		if outPort, err := r.routeFlit(header); err == nil {
+			return outPort.connection().GetDstRouter()
+		}
+		return ""
+	}
+	return r.packetsNextRouter[flit.PacketID()]
+}
 
-			r.packetsNextRouter[flit.PacketID()] = outPort.connection().GetDstRouter()
+// Advances the flit through the router pipeline's stages, routing header flits once they complete their allocation
+// stages, and reports whether the flit is ready to be sent.
+func (r *routerImpl) processFlit(cycle int, flit packet.Flit) (bool, error) {
+	if _, previouslyProcessed := r.flitsProcessedPerCycle[flit.ID()]; previouslyProcessed {
+		return false, nil
+	}
+	r.flitsProcessedPerCycle[flit.ID()] = true
+	r.flitStageCycles[flit.ID()]++
 
-			return true, nil
+	latency, atHead := r.simConf.PipelineDelay(flit.Type() == packet.HeaderFlitType)
+	if r.flitStageCycles[flit.ID()] < atHead {
+		return false, nil
+	}
+	// Stages performed away from the head of the virtual channel, e.g. switch traversal, overlap with the flits ahead.
+	if latency > atHead {
+		if buffered, exists := r.flitBufferedCycles[flit.ID()]; exists && cycle < buffered+latency {
+			return false, nil
 		}
 	}
-	return false, nil
+
+	if header, isHeader := flit.(packet.HeaderFlit); isHeader {
+		outPort, err := r.routeFlit(header)
+		if err != nil {
+			return false, err
+		}
+
+		r.packetsNextRouter[flit.PacketID()] = outPort.connection().GetDstRouter()
+	}
+
+	return true, nil
 }
 
 func (r *routerImpl) routeFlit(flit packet.HeaderFlit) (outputPort, error) {
 	route := flit.Route()
 	for i := 0; i < len(route); i++ {
 		if route[i] == r.NodeID() {
-			if i == len(route)-1 {
-				if _, exists := r.outputMap[route[i]]; !exists {
+			// Packets are ejected to their destination network interface at the route's last router, which a route
+			// between two network interfaces of the same router repeats.
+			if i == len(route)-1 || route[i+1] == r.NodeID() {
+				if _, exists := r.outputMap[flit.Destination()]; !exists {
 					return nil, domain.ErrNoPort
 				}
 
-				return r.outputMap[route[i]], nil
+				return r.outputMap[flit.Destination()], nil
 			} else {
 				if _, exists := r.outputMap[route[i+1]]; !exists {
 					return nil, domain.ErrNoPort
@@ -242,7 +362,8 @@ func (r *routerImpl) routeFlit(flit packet.HeaderFlit) (outputPort, error) {
 	return nil, domain.ErrNoPort
 }
 
-func (r *routerImpl) arbitrateFlit(cycle int, inputPortIndex int, flit packet.Flit) error {
+func (r *routerImpl) arbitrateFlit(cycle int, head bufferedHead) error {
+	flit := head.flit
 	logger := r.logger.With().Int("cycle", cycle).Str("flit", flit.ID()).Str("type", flit.Type().String()).Logger()
 
 	if _, exists := r.outputMap[r.packetsNextRouter[flit.PacketID()]]; !exists {
@@ -255,23 +376,33 @@ func (r *routerImpl) arbitrateFlit(cycle int, inputPortIndex int, flit packet.Fl
 		}
 	}
 
-	_, err := r.sendFlit(cycle, inputPortIndex, flit)
+	sent, err := r.sendFlit(cycle, head.inputPort, head.vChan, flit)
 	if err != nil {
 		logger.Error().Err(err).Msg("error sending buffered flit")
 		return err
 	}
+	if sent {
+		delete(r.flitStageCycles, flit.ID())
+		delete(r.flitBufferedCycles, flit.ID())
+		r.arbiter.granted(cycle, head)
+	}
 
 	return nil
 }
 
-func (r *routerImpl) sendFlit(cycle int, inputPortIndex int, flit packet.Flit) (bool, error) {
+func (r *routerImpl) sendFlit(cycle, inputPortIndex, vChan int, flit packet.Flit) (bool, error) {
 	outPort, exists := r.outputMap[r.packetsNextRouter[flit.PacketID()]]
 	if !exists {
 		return false, domain.ErrInvalidParameter
 	}
 
-	if outPort.allowedToSend(flit.Priority()) {
-		flit, exists := r.inputPorts[inputPortIndex].readOutOfBuffer(cycle, flit.Priority())
+	if holder, locked := r.outputLocks[outPort]; locked && holder.PacketID() != flit.PacketID() {
+		r.interference.record(flit.TrafficFlowID(), holder.TrafficFlowID())
+		return false, nil
+	}
+
+	if outPort.canAllocate(flit) && outPort.allowedToSend(cycle, flit.Priority()) {
+		flit, exists := r.inputPorts[inputPortIndex].readOutOfBuffer(cycle, vChan)
 		if !exists {
 			return false, domain.ErrInvalidParameter
 		}
@@ -279,6 +410,7 @@ func (r *routerImpl) sendFlit(cycle int, inputPortIndex int, flit packet.Flit) (
 		if err := outPort.sendFlit(cycle, flit); err != nil {
 			return false, err
 		}
+		r.updateOutputLock(outPort, flit)
 
 		r.logger.Trace().
 			Int("cycle", cycle).Str("flit", flit.ID()).Str("type", flit.Type().String()).
@@ -286,17 +418,125 @@ func (r *routerImpl) sendFlit(cycle int, inputPortIndex int, flit packet.Flit) (
 
 		return true, nil
 	} else {
+		if outPort.faultBlocked(flit) {
+			r.logger.Trace().Int("cycle", cycle).Str("flit", flit.ID()).Msg("output link down")
+		} else if outPort.credit(flit.Priority()) < 1 || !outPort.canAllocate(flit) {
+			r.inputPorts[inputPortIndex].recordCreditBlocked(vChan)
+		} else {
+			r.interference.recordBlocked(cycle, flit.TrafficFlowID(), outPort)
+		}
+
 		return false, nil
 	}
 }
 
+// Locks the output port to the packet of a sent header flit, and unlocks it once the packet's tail flit has been sent,
+// when switching is non-preemptive.
+func (r *routerImpl) updateOutputLock(outPort outputPort, flit packet.Flit) {
+	if r.simConf.Preemptive() {
+		return
+	}
+
+	switch flit.Type() {
+	case packet.HeaderFlitType:
+		r.outputLocks[outPort] = flit
+	case packet.TailFlitType:
+		delete(r.outputLocks, outPort)
+	}
+}
+
 func (r *routerImpl) ReadFromInputPorts(cycle int) error {
 	for i := 0; i < len(r.inputPorts); i++ {
 		err := r.inputPorts[i].readIntoBuffer(cycle)
 		if err != nil {
 			return err
 		}
+
+		r.inputPorts[i].sampleOccupancy()
 	}
 
 	return nil
 }
+
+func (r *routerImpl) BufferStats() []domain.BufferStats {
+	bufferStats := make([]domain.BufferStats, 0, len(r.inputPorts)*r.simConf.VCCount())
+
+	for i := 0; i < len(r.inputPorts); i++ {
+		portID := r.inputPorts[i].connection().GetSrcRouter()
+		if r.netIntfcs[portID] {
+			portID = domain.NetworkInterfacePort(portID, r.NodeID())
+		}
+
+		stats := r.inputPorts[i].statistics()
+		for vc := 1; vc <= r.inputPorts[i].vChanCount(); vc++ {
+			vChan := stats.vChan(vc)
+			bufferStats = append(bufferStats, domain.BufferStats{
+				Router:              r.NodeID(),
+				InputPort:           portID,
+				VirtualChannel:      vc,
+				MeanOccupancy:       vChan.meanOccupancy(stats.samples),
+				MaxOccupancy:        vChan.occupancyMax,
+				CreditBlockedCycles: vChan.creditBlocked,
+			})
+		}
+	}
+
+	return bufferStats
+}
+
+// Returns, per victim traffic flow, the number of cycles each other traffic flow's flits held an output port the
+// victim's flits were waiting to be sent through.
+func (r *routerImpl) Interference() map[string]map[string]int {
+	interference := make(map[string]map[string]int, len(r.interference))
+	MergeInterference(interference, r.interference)
+	return interference
+}
+
+// Enables the traversal log of the router's output links and its network interfaces' injection links.
+func (r *routerImpl) EnableLinkTraversalLog() {
+	for _, conn := range r.links() {
+		conn.EnableTraversalLog()
+	}
+}
+
+// Returns the flits sent over the router's output links and its network interfaces' injection links.
+func (r *routerImpl) LinkTraversals() map[domain.Link][]domain.LinkTraversal {
+	links := r.links()
+
+	traversals := make(map[domain.Link][]domain.LinkTraversal, len(links))
+	for link, conn := range links {
+		traversals[link] = conn.Traversals()
+	}
+
+	return traversals
+}
+
+// Returns the connections the router sends flits over, and the connections its network interfaces inject flits over.
+func (r *routerImpl) links() map[domain.Link]Connection {
+	links := make(map[domain.Link]Connection, len(r.outputPorts)+len(r.netIntfcs))
+
+	for i := 0; i < len(r.outputPorts); i++ {
+		link := domain.Link{Src: r.NodeID(), Dst: r.outputPorts[i].connection().GetDstRouter()}
+		if r.netIntfcs[link.Dst] {
+			link.Dst = domain.NetworkInterfacePort(link.Dst, r.NodeID())
+		}
+		links[link] = r.outputPorts[i].connection()
+	}
+
+	for i := 0; i < len(r.inputPorts); i++ {
+		if src := r.inputPorts[i].connection().GetSrcRouter(); r.netIntfcs[src] {
+			links[domain.Link{Src: domain.NetworkInterfacePort(src, r.NodeID()), Dst: r.NodeID()}] = r.inputPorts[i].connection()
+		}
+	}
+
+	return links
+}
+
+// Injects the faults into the router and the links returned by links.
+func (r *routerImpl) InjectFaults(faults *FaultInjector) {
+	r.faults = faults
+
+	for link, conn := range r.links() {
+		conn.injectFaults(faults, link)
+	}
+}
@@ -1,19 +1,23 @@
 package components
 
 import (
+	"main/src/domain"
+
 	"github.com/rs/zerolog"
 )
 
 type RouterNode struct {
-	nodeID           string
-	Router           Router
-	NetworkInterface NetworkInterface
+	nodeID            string
+	Router            Router
+	NetworkInterfaces []NetworkInterface
 }
 
 func (r *RouterNode) NodeID() string {
 	return r.nodeID
 }
 
+// Creates a router and its own network interfaces, each network interface connected to its own local input and output
+// port of the router.
 func NewRouterNode(conf RouterConfig, logger zerolog.Logger) (RouterNode, error) {
 	router, err := newRouter(conf, logger)
 	if err != nil {
@@ -21,21 +25,35 @@ func NewRouterNode(conf RouterConfig, logger zerolog.Logger) (RouterNode, error)
 		return RouterNode{}, err
 	}
 
-	netIntfc, err := newNetworkInterface(conf.NodeID, conf.BufferSize, conf.MaxPriority, logger)
+	rNode := RouterNode{
+		nodeID:            conf.NodeID,
+		Router:            router,
+		NetworkInterfaces: make([]NetworkInterface, 0, conf.LocalPorts),
+	}
+
+	for i := 0; i < conf.LocalPorts; i++ {
+		if err := rNode.AttachNetworkInterface(domain.NetworkInterfaceID(conf.NodeID, i), conf.SimConfig, logger); err != nil {
+			return RouterNode{}, err
+		}
+	}
+
+	logger.Trace().Str("id", router.NodeID()).Int("network_interfaces", conf.LocalPorts).Msg("new router and network interfaces")
+	return rNode, nil
+}
+
+// Creates a network interface and connects it to a new local input and output port of the router.
+func (r *RouterNode) AttachNetworkInterface(id string, conf domain.SimConfig, logger zerolog.Logger) error {
+	netIntfc, err := newConfiguredNetworkInterface(id, conf, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("error creating new network interface")
-		return RouterNode{}, err
+		return err
 	}
 
-	if err := router.SetNetworkInterface(netIntfc); err != nil {
-		logger.Error().Err(err).Str("id", router.NodeID()).Msg("error setting router network interface")
-		return RouterNode{}, err
+	if err := r.Router.SetNetworkInterface(netIntfc); err != nil {
+		logger.Error().Err(err).Str("id", r.Router.NodeID()).Str("network_interface", id).Msg("error setting router network interface")
+		return err
 	}
 
-	logger.Trace().Str("id", router.NodeID()).Msg("new router and network interface")
-	return RouterNode{
-		nodeID:           conf.NodeID,
-		Router:           router,
-		NetworkInterface: netIntfc,
-	}, nil
+	r.NetworkInterfaces = append(r.NetworkInterfaces, netIntfc)
+	return nil
 }
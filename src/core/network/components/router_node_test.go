@@ -16,7 +16,8 @@ func TestNewRouterNode(t *testing.T) {
 
 	t.Run("Valid", func(t *testing.T) {
 		conf := RouterConfig{
-			NodeID: "n1",
+			NodeID:     "n1",
+			LocalPorts: 1,
 			SimConfig: domain.SimConfig{
 				BufferSize:      16,
 				MaxPriority:     4,
@@ -28,7 +29,51 @@ func TestNewRouterNode(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, conf.NodeID, routerNode.NodeID())
 		assert.NotNil(t, routerNode.Router)
-		assert.NotNil(t, routerNode.NetworkInterface)
+		require.Len(t, routerNode.NetworkInterfaces, 1)
+		assert.Equal(t, conf.NodeID, routerNode.NetworkInterfaces[0].NodeID())
+	})
+
+	t.Run("MultipleNetworkInterfaces", func(t *testing.T) {
+		conf := RouterConfig{
+			NodeID:     "n1",
+			LocalPorts: 3,
+			SimConfig: domain.SimConfig{
+				BufferSize:      16,
+				MaxPriority:     4,
+				ProcessingDelay: 5,
+			},
+		}
+
+		routerNode, err := NewRouterNode(conf, zerolog.New(io.Discard).With().Logger())
+		require.NoError(t, err)
+		require.Len(t, routerNode.NetworkInterfaces, 3)
+		for i, id := range []string{"n1", "n1:1", "n1:2"} {
+			assert.Equal(t, id, routerNode.NetworkInterfaces[i].NodeID())
+		}
+
+		router := routerNode.Router.(*routerImpl)
+		assert.Len(t, router.inputPorts, 3)
+		assert.Len(t, router.outputPorts, 3)
+	})
+
+	t.Run("RouterOnly", func(t *testing.T) {
+		conf := RouterConfig{
+			NodeID: "n1",
+			SimConfig: domain.SimConfig{
+				BufferSize:      16,
+				MaxPriority:     4,
+				ProcessingDelay: 5,
+			},
+		}
+
+		routerNode, err := NewRouterNode(conf, zerolog.New(io.Discard).With().Logger())
+		require.NoError(t, err)
+		assert.Empty(t, routerNode.NetworkInterfaces)
+
+		require.NoError(t, routerNode.AttachNetworkInterface("e1", conf.SimConfig, zerolog.New(io.Discard)))
+		require.Len(t, routerNode.NetworkInterfaces, 1)
+		assert.Equal(t, "e1", routerNode.NetworkInterfaces[0].NodeID())
+		assert.Len(t, routerNode.Router.(*routerImpl).inputPorts, 1)
 	})
 
 	t.Run("NewRouterError", func(t *testing.T) {
@@ -130,7 +130,7 @@ func TestNewRouter(t *testing.T) {
 
 		assert.Equal(t, conf.SimConfig, router.simConf)
 
-		assert.NotNil(t, router.headerFlitsProcessings)
+		assert.NotNil(t, router.flitStageCycles)
 	})
 
 	t.Run("InvalidBufferSize", func(t *testing.T) {
@@ -197,6 +197,17 @@ func TestRouterRegisterInputPort(t *testing.T) {
 		assert.Equal(t, router.inputPorts[0].connection(), conn)
 	})
 
+	t.Run("ConnectionBufferSize", func(t *testing.T) {
+		router := testRouter(t)
+
+		conn, err := NewLinkConnection(router.simConf.MaxPriority, domain.LinkConfig{Latency: 1, CreditLatency: 1, Bandwidth: 1, BufferSize: 4}, zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		err = router.RegisterInputPort(conn)
+		require.NoError(t, err)
+		assert.Equal(t, 4, router.inputPorts[0].(*inputPortImpl).buff.totalCapacity())
+	})
+
 	t.Run("NewInputPortError", func(t *testing.T) {
 		router := testRouter(t)
 
@@ -327,7 +338,7 @@ func TestRouterUpdateOutputPortsCredit(t *testing.T) {
 
 	conn.creditChannel(1) <- 1
 
-	err = router.UpdateOutputPortsCredit()
+	err = router.UpdateOutputPortsCredit(0)
 	require.NoError(t, err)
 }
 
@@ -344,16 +355,16 @@ func TestRouterRouteBufferedFlits(t *testing.T) {
 
 		flits := pkt.Flits()
 		for i := 0; i < len(flits); i++ {
-			err = testRouterPair.rA.UpdateOutputPortsCredit()
+			err = testRouterPair.rA.UpdateOutputPortsCredit(i)
 			require.NoError(t, err)
 
-			err = testRouterPair.niA.TransmitPendingPackets(0)
+			err = testRouterPair.niA.TransmitPendingPackets(i)
 			require.NoError(t, err)
 
-			err = testRouterPair.rA.ReadFromInputPorts(0)
+			err = testRouterPair.rA.ReadFromInputPorts(i)
 			require.NoError(t, err)
 
-			err = testRouterPair.rA.RouteBufferedFlits(0)
+			err = testRouterPair.rA.RouteBufferedFlits(i)
 			require.NoError(t, err)
 
 			gotFlit := <-testRouterPair.AtoB.flitChannel()
@@ -365,6 +376,193 @@ func TestRouterRouteBufferedFlits(t *testing.T) {
 	})
 }
 
+func TestRouterNonPreemptiveOutputLock(t *testing.T) {
+	t.Parallel()
+
+	testRouterPair := newTestRouterPair(t, 8, 1, 2)
+	testRouterPair.rA.simConf.Switching = domain.NonPreemptiveWormholeSwitching
+	outPort := testRouterPair.rA.outputMap[testRouterPair.rB.NodeID()]
+
+	// A lower priority packet holds the output port.
+	low := packet.NewPacket("tl", "AA", 2, 100, domain.Route{testRouterPair.rA.NodeID(), testRouterPair.rB.NodeID()}, 3, zerolog.New(io.Discard))
+	testRouterPair.rA.updateOutputLock(outPort, low.Flits()[0])
+
+	high := packet.NewPacket("th", "AB", 1, 100, domain.Route{testRouterPair.rA.NodeID(), testRouterPair.rB.NodeID()}, 3, zerolog.New(io.Discard))
+	require.NoError(t, testRouterPair.niA.RoutePacket(0, high))
+
+	for cycle := 0; cycle < 2; cycle++ {
+		require.NoError(t, testRouterPair.rA.UpdateOutputPortsCredit(cycle))
+		require.NoError(t, testRouterPair.niA.TransmitPendingPackets(cycle))
+		require.NoError(t, testRouterPair.rA.ReadFromInputPorts(cycle))
+		require.NoError(t, testRouterPair.rA.RouteBufferedFlits(cycle))
+	}
+	assert.Empty(t, testRouterPair.AtoB.flitChannel())
+	assert.Equal(t, map[string]map[string]int{"th": {"tl": 2}}, testRouterPair.rA.Interference())
+
+	// The lower priority packet's tail flit releases the output port.
+	testRouterPair.rA.updateOutputLock(outPort, low.Flits()[2])
+
+	require.NoError(t, testRouterPair.rA.UpdateOutputPortsCredit(2))
+	require.NoError(t, testRouterPair.rA.RouteBufferedFlits(2))
+
+	gotFlit := <-testRouterPair.AtoB.flitChannel()
+	assert.Equal(t, high.Flits()[0].ID(), gotFlit.ID())
+	assert.Equal(t, high.Flits()[0], testRouterPair.rA.outputLocks[outPort])
+}
+
+func TestRouterPipeline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pipeline domain.RouterPipeline
+		expected []int
+	}{
+		{
+			name:     "ProcessingDelay",
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "Stages",
+			pipeline: domain.RouterPipeline{RoutingComputation: 1, VCAllocation: 1, SwitchAllocation: 1, SwitchTraversal: 1},
+			expected: []int{4, 5, 6},
+		},
+		{
+			name:     "LookaheadSpeculative",
+			pipeline: domain.RouterPipeline{RoutingComputation: 1, VCAllocation: 1, SwitchAllocation: 1, SwitchTraversal: 1, Lookahead: true, Speculative: true},
+			expected: []int{2, 3, 4},
+		},
+		{
+			name:     "MultiCycleSwitchTraversal",
+			pipeline: domain.RouterPipeline{RoutingComputation: 1, VCAllocation: 1, SwitchAllocation: 1, SwitchTraversal: 3},
+			expected: []int{6, 7, 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRouterPair := newTestRouterPair(t, 8, 1, 1)
+			testRouterPair.rA.simConf.Pipeline = tt.pipeline
+
+			pkt := packet.NewPacket("t", "AA", 1, 100, domain.Route{testRouterPair.rA.NodeID(), testRouterPair.rB.NodeID()}, 3, zerolog.New(io.Discard))
+			require.NoError(t, testRouterPair.niA.RoutePacket(0, pkt))
+
+			// Cycle each of the packet's flits is sent from router A to router B.
+			sent := make([]int, 0, len(tt.expected))
+			for cycle := 0; cycle < 10; cycle++ {
+				require.NoError(t, testRouterPair.rA.UpdateOutputPortsCredit(cycle))
+				require.NoError(t, testRouterPair.niA.TransmitPendingPackets(cycle))
+				require.NoError(t, testRouterPair.rA.RouteBufferedFlits(cycle))
+				require.NoError(t, testRouterPair.rA.ReadFromInputPorts(cycle))
+
+				for len(testRouterPair.AtoB.flitChannel()) > 0 {
+					<-testRouterPair.AtoB.flitChannel()
+					sent = append(sent, cycle)
+				}
+			}
+			assert.Equal(t, tt.expected, sent)
+			assert.Empty(t, testRouterPair.rA.flitStageCycles)
+		})
+	}
+}
+
+func TestRouterPipelineBodyFlitSwitchTraversal(t *testing.T) {
+	t.Parallel()
+
+	testRouterPair := newTestRouterPair(t, 8, 1, 1)
+	testRouterPair.rA.simConf.Pipeline = domain.RouterPipeline{SwitchAllocation: 1, SwitchTraversal: 3}
+
+	pkt := packet.NewPacket("t", "AA", 1, 100, domain.Route{testRouterPair.rA.NodeID(), testRouterPair.rB.NodeID()}, 4, zerolog.New(io.Discard))
+	require.NoError(t, testRouterPair.niA.RoutePacket(0, pkt))
+
+	// The packet's flits are buffered in cycles 0 to 3 without being routed.
+	for cycle := 0; cycle < 4; cycle++ {
+		require.NoError(t, testRouterPair.niA.TransmitPendingPackets(cycle))
+		require.NoError(t, testRouterPair.rA.ReadFromInputPorts(cycle))
+	}
+
+	// The body flit buffered in cycle 2, which records no events, traverses the switch for 3 cycles after its
+	// allocation cycle.
+	body := pkt.Flits()[2]
+	require.Equal(t, packet.BodyFlitType, body.Type())
+	for cycle := 4; cycle < 6; cycle++ {
+		testRouterPair.rA.flitsProcessedPerCycle = make(map[string]bool)
+		ready, err := testRouterPair.rA.processFlit(cycle, body)
+		require.NoError(t, err)
+		assert.False(t, ready, "cycle %d", cycle)
+	}
+
+	testRouterPair.rA.flitsProcessedPerCycle = make(map[string]bool)
+	ready, err := testRouterPair.rA.processFlit(6, body)
+	require.NoError(t, err)
+	assert.True(t, ready)
+}
+
+func TestRouterCrossbarSpeedup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		speedup int
+		crossed int
+	}{
+		{name: "NoSpeedup", speedup: 1, crossed: 1},
+		{name: "Speedup", speedup: 2, crossed: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := domain.SimConfig{
+				BufferSize:        8,
+				ProcessingDelay:   1,
+				MaxPriority:       2,
+				OutputBufferDepth: 2,
+				CrossbarSpeedup:   tt.speedup,
+			}
+
+			rA, err := newRouter(RouterConfig{NodeID: "n-a", SimConfig: conf}, zerolog.New(io.Discard))
+			require.NoError(t, err)
+			niA, err := newConfiguredNetworkInterface("i-a", conf, zerolog.New(io.Discard))
+			require.NoError(t, err)
+			require.NoError(t, rA.SetNetworkInterface(niA))
+
+			rB, err := newRouter(RouterConfig{NodeID: "n-b", SimConfig: conf}, zerolog.New(io.Discard))
+			require.NoError(t, err)
+
+			AtoB, err := NewConnection(conf.MaxPriority, zerolog.New(io.Discard))
+			require.NoError(t, err)
+			require.NoError(t, rA.RegisterOutputPort(AtoB))
+			require.NoError(t, rB.RegisterInputPort(AtoB))
+			rA.UpdateOutputMap()
+
+			route := domain.Route{rA.NodeID(), rB.NodeID()}
+			require.NoError(t, niA.RoutePacket(0, packet.NewPacket("th", "AA", 1, 100, route, 2, zerolog.New(io.Discard))))
+			require.NoError(t, niA.RoutePacket(0, packet.NewPacket("tl", "AB", 2, 100, route, 2, zerolog.New(io.Discard))))
+
+			// Both packets are buffered in their own virtual channels without being routed.
+			for cycle := 0; cycle < 4; cycle++ {
+				require.NoError(t, rA.UpdateOutputPortsCredit(cycle))
+				require.NoError(t, niA.TransmitPendingPackets(cycle))
+				require.NoError(t, rA.ReadFromInputPorts(cycle))
+			}
+
+			// Each virtual channel's header flit may cross the crossbar in the same cycle, up to the speedup, while the
+			// link carries one of them.
+			require.NoError(t, rA.UpdateOutputPortsCredit(4))
+			require.NoError(t, rA.RouteBufferedFlits(4))
+
+			outPort, ok := rA.outputMap[rB.NodeID()].(*outputPortImpl)
+			require.True(t, ok)
+			buffered := 0
+			for _, flits := range outPort.buffered {
+				buffered += len(flits)
+			}
+			assert.Len(t, AtoB.flitChannel(), 1)
+			assert.Equal(t, tt.crossed, buffered+len(AtoB.flitChannel()))
+		})
+	}
+}
+
 func TestRouterReadFromInputPorts(t *testing.T) {
 	t.Parallel()
 
@@ -408,4 +606,37 @@ func TestRouterRouteFlit(t *testing.T) {
 		_, err := router.routeFlit(flit)
 		require.ErrorIs(t, err, domain.ErrNoPort)
 	})
+
+	t.Run("DestinationNetworkInterface", func(t *testing.T) {
+		router := testRouter(t)
+		for _, id := range []string{"n", "n:1"} {
+			netIntfc, err := newNetworkInterface(id, 1, 1, zerolog.New(io.Discard))
+			require.NoError(t, err)
+			require.NoError(t, router.SetNetworkInterface(netIntfc))
+		}
+		router.UpdateOutputMap()
+
+		for _, tc := range []struct {
+			route       domain.Route
+			destination string
+		}{
+			{route: domain.Route{"m", "n"}, destination: "n:1"},
+			{route: domain.Route{"m", "n"}, destination: "n"},
+			{route: domain.Route{"n", "n"}, destination: "n:1"},
+		} {
+			pkt := packet.NewPacket("t", "AA", 1, 100, tc.route, 2, zerolog.New(io.Discard))
+			pkt.SetEndpoints(tc.route[0], tc.destination)
+
+			outPort, err := router.routeFlit(pkt.Flits()[0].(packet.HeaderFlit))
+			require.NoError(t, err)
+			assert.Equal(t, tc.destination, outPort.connection().GetDstRouter())
+		}
+
+		assert.Equal(t, []string{domain.LocalPort, "n:1"}, []string{
+			router.BufferStats()[0].InputPort,
+			router.BufferStats()[1].InputPort,
+		})
+		assert.Contains(t, router.links(), domain.Link{Src: "n", Dst: "n:1"})
+		assert.Contains(t, router.links(), domain.Link{Src: "n:1", Dst: "n"})
+	})
 }
@@ -1,6 +1,10 @@
 package network
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
 	"main/src/core/network/components"
 	"main/src/domain"
 	"main/src/topology"
@@ -21,6 +25,14 @@ type Network interface {
 	Topology() *topology.Topology
 
 	Cycle(cycle int) error
+
+	Stats(cycles int) domain.NetworkStats
+
+	EnableLinkTraversalLog()
+	LinkTraversals() map[domain.Link][]domain.LinkTraversal
+
+	InjectFaults(faults []domain.FaultConfig) error
+	FaultLog() domain.FaultLog
 }
 
 type networkImpl struct {
@@ -29,39 +41,51 @@ type networkImpl struct {
 
 	netwrkIntfcMap map[string]components.NetworkInterface
 	routerMap      map[string]components.Router
+	// ID of the router each network interface is attached to.
+	netwrkIntfcRouters map[string]string
 
 	netwrkIntfcIDMap map[string]components.NetworkInterface
 	routerIDMap      map[string]components.Router
 
+	links []components.Connection
+
+	faults *components.FaultInjector
+
 	top *topology.Topology
 
 	logger zerolog.Logger
 }
 
 func NewNetwork(top *topology.Topology, conf domain.SimConfig, logger zerolog.Logger) (Network, error) {
-	routerNodes, err := buildNetwork(top, conf, logger)
+	if err := validateBufferSizes(top, conf, logger); err != nil {
+		return nil, err
+	}
+
+	routerNodes, links, err := buildNetwork(top, conf, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("error building network")
 		return nil, err
 	}
 
-	netwrkIntfcs := make([]components.NetworkInterface, len(routerNodes))
-	index := 0
+	netwrkIntfcs := make([]components.NetworkInterface, 0, len(routerNodes))
+	netwrkIntfcRouters := make(map[string]string)
 	for id := range routerNodes {
-		netwrkIntfcs[index] = routerNodes[id].NetworkInterface
-		index++
+		netwrkIntfcs = append(netwrkIntfcs, routerNodes[id].NetworkInterfaces...)
+		for _, netwrkIntfc := range routerNodes[id].NetworkInterfaces {
+			netwrkIntfcRouters[netwrkIntfc.NodeID()] = id
+		}
 	}
 
 	routers := make([]components.Router, len(routerNodes))
-	index = 0
+	index := 0
 	for id := range routerNodes {
 		routers[index] = routerNodes[id].Router
 		index++
 	}
 
 	netwrkIntfcMap := make(map[string]components.NetworkInterface)
-	for id := range routerNodes {
-		netwrkIntfcMap[routerNodes[id].NetworkInterface.NodeID()] = routerNodes[id].NetworkInterface
+	for i := 0; i < len(netwrkIntfcs); i++ {
+		netwrkIntfcMap[netwrkIntfcs[i].NodeID()] = netwrkIntfcs[i]
 	}
 
 	routerMap := make(map[string]components.Router)
@@ -70,8 +94,8 @@ func NewNetwork(top *topology.Topology, conf domain.SimConfig, logger zerolog.Lo
 	}
 
 	netwrkIntfcIDMap := make(map[string]components.NetworkInterface)
-	for id := range routerNodes {
-		netwrkIntfcIDMap[routerNodes[id].NetworkInterface.NodeID()] = routerNodes[id].NetworkInterface
+	for i := 0; i < len(netwrkIntfcs); i++ {
+		netwrkIntfcIDMap[netwrkIntfcs[i].NodeID()] = netwrkIntfcs[i]
 	}
 
 	routerIDMap := make(map[string]components.Router)
@@ -83,95 +107,157 @@ func NewNetwork(top *topology.Topology, conf domain.SimConfig, logger zerolog.Lo
 		netwrkIntfcs: netwrkIntfcs,
 		routers:      routers,
 
-		netwrkIntfcMap: netwrkIntfcMap,
-		routerMap:      routerMap,
+		netwrkIntfcMap:     netwrkIntfcMap,
+		routerMap:          routerMap,
+		netwrkIntfcRouters: netwrkIntfcRouters,
 
 		netwrkIntfcIDMap: netwrkIntfcIDMap,
 		routerIDMap:      routerIDMap,
 
+		links: links,
+
 		top: top,
+
+		logger: logger,
 	}, nil
 }
 
-func buildNetwork(top *topology.Topology, conf domain.SimConfig, logger zerolog.Logger) (map[string]components.RouterNode, error) {
+// Checks every router, edge and network interface buffer size set by the topology can be split into the configured
+// virtual channel depths, which must total each buffer's size when set.
+func validateBufferSizes(top *topology.Topology, conf domain.SimConfig, logger zerolog.Logger) error {
+	for id := range top.Nodes() {
+		nodeConf := conf.ForNode(top.NodeConfig(id))
+		for _, size := range []int{nodeConf.BufferSize, nodeConf.EjectionBuffer()} {
+			if conf.VChanDepths(size) == nil {
+				err := errors.Join(domain.ErrInvalidTopology, fmt.Errorf("node %s buffer size %d can not be split into its virtual channels", id, size))
+				logger.Error().Err(err).Str("node_id", id).Int("buffer_size", size).Int("num_vcs", conf.VCCount()).Ints("vc_depths", conf.VCDepths).Msg("buffer size must be a multiple of the number of virtual channels, or total vc_depths when set")
+				return err
+			}
+		}
+	}
+
+	for id, edge := range top.Edges() {
+		size := edge.LinkConfig().BufferSize
+		if size > 0 && conf.VChanDepths(size) == nil {
+			err := errors.Join(domain.ErrInvalidTopology, fmt.Errorf("edge %s buffer size %d can not be split into its virtual channels", id, size))
+			logger.Error().Err(err).Str("edge_id", id).Int("buffer_size", size).Int("num_vcs", conf.VCCount()).Ints("vc_depths", conf.VCDepths).Msg("buffer size must be a multiple of the number of virtual channels, or total vc_depths when set")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func buildNetwork(top *topology.Topology, conf domain.SimConfig, logger zerolog.Logger) (map[string]components.RouterNode, []components.Connection, error) {
 	logger.Debug().Msg("constructing network from topology")
 
 	routerNodes := make(map[string]components.RouterNode)
+	links := make([]components.Connection, 0, 2*len(top.Edges()))
 
 	logger.Debug().Msg("creating routers")
 	for id := range top.Nodes() {
 		node, exists := top.Node(id)
 		if !exists {
 			logger.Error().Err(domain.ErrInvalidTopology).Str("node_id", id).Msg("node does not exist")
-			return nil, domain.ErrInvalidTopology
+			return nil, nil, domain.ErrInvalidTopology
+		}
+		if !top.NodeConfig(node.NodeID()).HasRouter() {
+			continue
 		}
 
 		rNode, err := components.NewRouterNode(
 			components.RouterConfig{
-				NodeID:    node.NodeID(),
-				SimConfig: conf,
+				NodeID:     node.NodeID(),
+				LocalPorts: top.NodeConfig(node.NodeID()).NetworkInterfaceCount(),
+				SimConfig:  conf.ForNode(top.NodeConfig(node.NodeID())),
 			},
 			logger,
 		)
 		if err != nil {
 			logger.Error().Err(err).Str("node_id", node.NodeID()).Msg("error creating router")
-			return nil, err
+			return nil, nil, err
 		}
 
 		routerNodes[rNode.NodeID()] = rNode
 	}
 
+	logger.Debug().Msg("attaching endpoints")
+	for id := range top.Nodes() {
+		if top.NodeConfig(id).HasRouter() {
+			continue
+		}
+
+		routerID, exists := top.AttachedRouter(id)
+		if !exists {
+			logger.Error().Err(domain.ErrInvalidTopology).Str("node_id", id).Msg("endpoint not attached to a router")
+			return nil, nil, domain.ErrInvalidTopology
+		}
+
+		rNode := routerNodes[routerID]
+		if err := rNode.AttachNetworkInterface(id, conf.ForNode(top.NodeConfig(id)), logger); err != nil {
+			logger.Error().Err(err).Str("node_id", id).Str("router", routerID).Msg("error attaching endpoint")
+			return nil, nil, err
+		}
+		routerNodes[routerID] = rNode
+	}
+
 	logger.Debug().Msg("connecting routers")
 	for id := range top.Edges() {
 		edge, exists := top.Edge(id)
 		if !exists {
 			logger.Error().Err(domain.ErrInvalidTopology).Str("node_id", id).Msg("edge does not exist")
-			return nil, domain.ErrInvalidTopology
+			return nil, nil, domain.ErrInvalidTopology
+		}
+		// An endpoint's edge attaches it to its router's local port.
+		if !top.NodeConfig(edge.A()).HasRouter() || !top.NodeConfig(edge.B()).HasRouter() {
+			continue
 		}
 
 		aRouterNode, exists := routerNodes[edge.A()]
 		if !exists {
 			logger.Error().Err(domain.ErrInvalidTopology).Str("edge_id", edge.ID()).Str("node_id", edge.A()).Msg("edge A router does not exist")
-			return nil, domain.ErrInvalidTopology
+			return nil, nil, domain.ErrInvalidTopology
 		}
 
 		bRouterNode, exists := routerNodes[edge.B()]
 		if !exists {
 			logger.Error().Err(domain.ErrInvalidTopology).Str("edge_id", edge.ID()).Str("node_id", edge.B()).Msg("edge B router does not exist")
-			return nil, domain.ErrInvalidTopology
+			return nil, nil, domain.ErrInvalidTopology
 		}
 
-		aToB, err := components.NewConnection(conf.MaxPriority, logger)
+		aToB, err := components.NewLinkConnection(conf.VCCount(), conf.ResolveLink(edge.LinkConfig()), logger)
 		if err != nil {
 			logger.Error().Err(err).Str("id", edge.ID()).Msg("error creating connection")
-			return nil, err
+			return nil, nil, err
 		}
 		if err := aRouterNode.Router.RegisterOutputPort(aToB); err != nil {
 			logger.Error().Err(err).Str("edge_id", edge.ID()).Str("node_id", aRouterNode.NodeID()).Msg("error registering output port")
-			return nil, err
+			return nil, nil, err
 		}
 		if err := bRouterNode.Router.RegisterInputPort(aToB); err != nil {
 			logger.Error().Err(err).Str("edge_id", edge.ID()).Str("node_id", bRouterNode.NodeID()).Msg("error registering input port")
-			return nil, err
+			return nil, nil, err
 		}
+		links = append(links, aToB)
 
-		bToA, err := components.NewConnection(conf.MaxPriority, logger)
+		bToA, err := components.NewLinkConnection(conf.VCCount(), conf.ResolveLink(edge.LinkConfig()), logger)
 		if err != nil {
 			logger.Error().Err(err).Str("id", edge.ID()).Msg("error creating connection")
-			return nil, err
+			return nil, nil, err
 		}
 		if err := bRouterNode.Router.RegisterOutputPort(bToA); err != nil {
 			logger.Error().Err(err).Str("edge_id", edge.ID()).Str("node_id", bRouterNode.NodeID()).Msg("error registering output port")
-			return nil, err
+			return nil, nil, err
 		}
 		if err := aRouterNode.Router.RegisterInputPort(bToA); err != nil {
 			logger.Error().Err(err).Str("edge_id", edge.ID()).Str("node_id", aRouterNode.NodeID()).Msg("error registering input port")
-			return nil, err
+			return nil, nil, err
 		}
+		links = append(links, bToA)
 	}
 
 	logger.Info().Msg("build network from topology")
-	return routerNodes, nil
+	return routerNodes, links, nil
 }
 
 func (n *networkImpl) NetworkInterfaces() []components.NetworkInterface {
@@ -199,6 +285,8 @@ func (n *networkImpl) RoutersIDMap() map[string]components.Router {
 }
 
 func (n *networkImpl) Cycle(cycle int) error {
+	n.faults.Advance(cycle)
+
 	for i := 0; i < len(n.netwrkIntfcs); i++ {
 		if err := n.netwrkIntfcs[i].TransmitPendingPackets(cycle); err != nil {
 			n.logger.Error().Err(err).Str("id", n.netwrkIntfcs[i].NodeID()).Msg("error transmitting network interface's pending packets")
@@ -221,6 +309,161 @@ func (n *networkImpl) Cycle(cycle int) error {
 	return nil
 }
 
+// Returns the router to router link, router input buffer, network interface, injection queue and traffic flow
+// interference statistics gathered over the simulated cycles.
+func (n *networkImpl) Stats(cycles int) domain.NetworkStats {
+	stats := domain.NetworkStats{
+		Links:             make([]domain.LinkStats, len(n.links)),
+		NetworkInterfaces: make([]domain.NetworkInterfaceStats, len(n.netwrkIntfcs)),
+		Interference:      make(map[string]map[string]int),
+		InjectionQueues:   make(map[string]domain.InjectionQueueStats),
+	}
+
+	for i := 0; i < len(n.links); i++ {
+		stats.Links[i] = domain.LinkStats{
+			Src:             n.links[i].GetSrcRouter(),
+			Dst:             n.links[i].GetDstRouter(),
+			Flits:           n.links[i].FlitCount(),
+			Retransmissions: n.links[i].Retransmissions(),
+		}
+		if cycles > 0 {
+			stats.Links[i].Utilisation = float64(n.links[i].FlitCount()) / (float64(cycles) * n.links[i].Bandwidth())
+		}
+	}
+
+	for i := 0; i < len(n.routers); i++ {
+		stats.Buffers = append(stats.Buffers, n.routers[i].BufferStats()...)
+		components.MergeInterference(stats.Interference, n.routers[i].Interference())
+	}
+
+	for i := 0; i < len(n.netwrkIntfcs); i++ {
+		stats.NetworkInterfaces[i] = n.netwrkIntfcs[i].Stats(cycles)
+		components.MergeInterference(stats.Interference, n.netwrkIntfcs[i].Interference())
+		for tf, queueStats := range n.netwrkIntfcs[i].InjectionQueues() {
+			stats.InjectionQueues[tf] = queueStats
+		}
+	}
+
+	sort.Slice(stats.Links, func(i, j int) bool {
+		if stats.Links[i].Src == stats.Links[j].Src {
+			return stats.Links[i].Dst < stats.Links[j].Dst
+		}
+		return stats.Links[i].Src < stats.Links[j].Src
+	})
+	sort.SliceStable(stats.Buffers, func(i, j int) bool {
+		if stats.Buffers[i].Router == stats.Buffers[j].Router {
+			return stats.Buffers[i].InputPort < stats.Buffers[j].InputPort
+		}
+		return stats.Buffers[i].Router < stats.Buffers[j].Router
+	})
+	sort.Slice(stats.NetworkInterfaces, func(i, j int) bool {
+		return stats.NetworkInterfaces[i].ID < stats.NetworkInterfaces[j].ID
+	})
+
+	return stats
+}
+
+// Enables logging of every flit sent over every link, including network interface injection and ejection links.
+func (n *networkImpl) EnableLinkTraversalLog() {
+	for i := 0; i < len(n.routers); i++ {
+		n.routers[i].EnableLinkTraversalLog()
+	}
+}
+
+func (n *networkImpl) LinkTraversals() map[domain.Link][]domain.LinkTraversal {
+	traversals := make(map[domain.Link][]domain.LinkTraversal)
+	for i := 0; i < len(n.routers); i++ {
+		for link, linkTraversals := range n.routers[i].LinkTraversals() {
+			traversals[link] = linkTraversals
+		}
+	}
+
+	return traversals
+}
+
+// Injects the fault schedule into the network's routers and links, validating each fault targets an existing component.
+func (n *networkImpl) InjectFaults(faults []domain.FaultConfig) error {
+	for i := 0; i < len(faults); i++ {
+		if err := n.validFaultTarget(faults[i]); err != nil {
+			n.logger.Error().Err(err).Int("fault", i).Str("fault_str", faults[i].String()).Msg("invalid fault target")
+			return err
+		}
+	}
+
+	injector, err := components.NewFaultInjector(faults, n.logger)
+	if err != nil {
+		return err
+	}
+
+	n.faults = injector
+	for i := 0; i < len(n.routers); i++ {
+		n.routers[i].InjectFaults(injector)
+	}
+
+	return nil
+}
+
+func (n *networkImpl) validFaultTarget(fault domain.FaultConfig) error {
+	switch fault.Type {
+	case domain.RouterStallFault:
+		if _, exists := n.routerMap[fault.Src]; !exists {
+			return errors.Join(domain.ErrInvalidFault, domain.ErrMissingRouter)
+		}
+	case domain.LinkDownFault, domain.FlitDropFault, domain.FlitCorruptFault:
+		if fault.Src == "" && fault.Dst == "" {
+			return nil
+		}
+		if !n.linkExists(fault.Link()) {
+			return errors.Join(domain.ErrInvalidFault, fmt.Errorf("no link from %s to %s", fault.Src, fault.Dst))
+		}
+	}
+
+	return nil
+}
+
+func (n *networkImpl) linkExists(link domain.Link) bool {
+	if link.Src == domain.LocalPort {
+		return n.netwrkIntfcAttached(link.Dst, link.Dst)
+	}
+	if link.Dst == domain.LocalPort {
+		return n.netwrkIntfcAttached(link.Src, link.Src)
+	}
+	// A router's first network interface is only identified by LocalPort.
+	if link.Src != link.Dst && (n.netwrkIntfcAttached(link.Src, link.Dst) || n.netwrkIntfcAttached(link.Dst, link.Src)) {
+		return true
+	}
+
+	for i := 0; i < len(n.links); i++ {
+		if n.links[i].GetSrcRouter() == link.Src && n.links[i].GetDstRouter() == link.Dst {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether the network interface is attached to the router.
+func (n *networkImpl) netwrkIntfcAttached(netwrkIntfcID, routerID string) bool {
+	router, exists := n.netwrkIntfcRouters[netwrkIntfcID]
+	return exists && router == routerID
+}
+
+func (n *networkImpl) FaultLog() domain.FaultLog {
+	log := domain.FaultLog{
+		Faults:      n.faults.Faults(),
+		Activations: n.faults.Activations(),
+		Affected:    n.faults.AffectedPackets(),
+		Discarded:   make(map[string]string),
+	}
+
+	for i := 0; i < len(n.netwrkIntfcs); i++ {
+		for packetID, err := range n.netwrkIntfcs[i].DiscardedPackets() {
+			log.Discarded[packetID] = err.Error()
+		}
+	}
+
+	return log
+}
+
 func (n *networkImpl) Topology() *topology.Topology {
 	return n.top
 }
@@ -231,7 +474,7 @@ func (n *networkImpl) cycleRouters(cycle int) error {
 	}
 
 	for i := 0; i < len(n.routers); i++ {
-		if err := n.routers[i].UpdateOutputPortsCredit(); err != nil {
+		if err := n.routers[i].UpdateOutputPortsCredit(cycle); err != nil {
 			n.logger.Error().Err(err).Str("id", n.routers[i].NodeID()).Msg("error updating router output ports credit")
 			return err
 		}
@@ -0,0 +1,55 @@
+package network
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/topology"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNetworkBufferSizes(t *testing.T) {
+	conf := domain.SimConfig{
+		MaxPriority:     2,
+		BufferSize:      6,
+		VCDepths:        []int{4, 2},
+		ProcessingDelay: 1,
+	}
+
+	t.Run("MatchingVCDepths", func(t *testing.T) {
+		top := topology.ThreeNodeLine(t)
+		top.SetNodeConfig("n1", domain.NodeConfig{BufferSize: 6, EjectionBufferSize: 6})
+
+		_, err := NewNetwork(top, conf, zerolog.New(io.Discard))
+		assert.NoError(t, err)
+	})
+
+	t.Run("NodeBufferSize", func(t *testing.T) {
+		top := topology.ThreeNodeLine(t)
+		top.SetNodeConfig("n1", domain.NodeConfig{BufferSize: 8})
+
+		_, err := NewNetwork(top, conf, zerolog.New(io.Discard))
+		require.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+
+	t.Run("EjectionBufferSize", func(t *testing.T) {
+		top := topology.ThreeNodeLine(t)
+		top.SetNodeConfig("n2", domain.NodeConfig{EjectionBufferSize: 4})
+
+		_, err := NewNetwork(top, conf, zerolog.New(io.Discard))
+		require.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+
+	t.Run("EdgeBufferSize", func(t *testing.T) {
+		top := topology.ThreeNodeLine(t)
+		edge, _ := top.Edge("e0")
+		edge.SetLinkConfig(domain.LinkConfig{BufferSize: 8})
+
+		_, err := NewNetwork(top, conf, zerolog.New(io.Discard))
+		require.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+}
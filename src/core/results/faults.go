@@ -0,0 +1,79 @@
+package results
+
+import (
+	"strconv"
+	"strings"
+
+	"main/src/domain"
+
+	"github.com/alexeyco/simpletable"
+)
+
+func writeFaultReportCSV(path string, report domain.FaultReport) error {
+	data := [][]string{{"TF_ID", "Packet_ID", "Faults", "Status", "Latency", "Extra_Latency"}}
+
+	for i := 0; i < len(report.Packets); i++ {
+		pkt := report.Packets[i]
+
+		latency, extraLatency := "", ""
+		if pkt.Status == domain.PacketArrived {
+			latency = strconv.Itoa(pkt.Latency)
+			extraLatency = cleanFloat(pkt.ExtraLatency, true)
+		}
+
+		data = append(data, []string{
+			pkt.TrafficFlowID,
+			pkt.PacketID,
+			faultDescriptions(report.Faults, pkt.Faults),
+			string(pkt.Status),
+			latency,
+			extraLatency,
+		})
+	}
+
+	return writeCSV(path, data)
+}
+
+func faultDescriptions(faults []domain.FaultOutcome, indexes []int) string {
+	descriptions := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		if i < len(faults) {
+			descriptions = append(descriptions, faults[i].String())
+		}
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// Summarises each injected fault's activity, empty if no faults were injected.
+func prettifyFaults(report domain.FaultReport) string {
+	if len(report.Faults) == 0 {
+		return ""
+	}
+
+	str := "\n\nFaults\n"
+	str += "======\n"
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{Cells: []*simpletable.Cell{
+		{Align: simpletable.AlignLeft, Text: "Fault"},
+		{Align: simpletable.AlignLeft, Text: "Cycles"},
+		{Align: simpletable.AlignLeft, Text: "Activations"},
+		{Align: simpletable.AlignLeft, Text: "Packets Affected"},
+	}}
+	for i := 0; i < len(report.Faults); i++ {
+		cycles := strconv.Itoa(report.Faults[i].StartCycle) + " - "
+		if report.Faults[i].EndCycle != 0 {
+			cycles += strconv.Itoa(report.Faults[i].EndCycle)
+		}
+
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: report.Faults[i].String()},
+			{Align: simpletable.AlignLeft, Text: cycles},
+			{Align: simpletable.AlignLeft, Text: strconv.Itoa(report.Faults[i].Activations)},
+			{Align: simpletable.AlignLeft, Text: strconv.Itoa(report.Faults[i].PacketsAffected)},
+		})
+	}
+	str += table.String()
+
+	return str
+}
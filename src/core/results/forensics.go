@@ -0,0 +1,114 @@
+package results
+
+import (
+	"encoding/json"
+	"os"
+
+	"main/src/domain"
+)
+
+type deadlineMissRecord struct {
+	TrafficFlowID   string           `json:"traffic_flow"`
+	PacketID        string           `json:"packet"`
+	Deadline        int              `json:"deadline"`
+	Latency         int              `json:"latency"`
+	GenerationCycle int              `json:"generation_cycle"`
+	ReleaseCycle    int              `json:"release_cycle"`
+	InjectionCycle  int              `json:"injection_cycle"`
+	ArrivalCycle    int              `json:"arrival_cycle"`
+	Hops            []hopRecord      `json:"hops"`
+	LinkWaits       []linkWaitRecord `json:"link_waits"`
+}
+
+type hopRecord struct {
+	Router     string `json:"router"`
+	HeaderIn   int    `json:"header_in"`
+	HeaderOut  int    `json:"header_out"`
+	HeaderWait int    `json:"header_wait"`
+	TailIn     int    `json:"tail_in"`
+	TailOut    int    `json:"tail_out"`
+	TailWait   int    `json:"tail_wait"`
+}
+
+type linkWaitRecord struct {
+	Src        string           `json:"src"`
+	Dst        string           `json:"dst"`
+	StartCycle int              `json:"start_cycle"`
+	EndCycle   int              `json:"end_cycle"`
+	Occupants  []occupantRecord `json:"occupants"`
+	FlitsByTF  map[string]int   `json:"flits_by_traffic_flow"`
+}
+
+type occupantRecord struct {
+	Cycle         int    `json:"cycle"`
+	TrafficFlowID string `json:"traffic_flow"`
+	PacketID      string `json:"packet"`
+	FlitType      string `json:"flit_type"`
+}
+
+// Writes a forensic record of every packet which exceeded its deadline to a json file.
+func writeDeadlineMissesJSON(path string, misses []domain.DeadlineMiss) error {
+	records := make([]deadlineMissRecord, len(misses))
+	for i := 0; i < len(misses); i++ {
+		records[i] = newDeadlineMissRecord(misses[i])
+	}
+
+	bytes, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, bytes, 0o644)
+}
+
+func newDeadlineMissRecord(miss domain.DeadlineMiss) deadlineMissRecord {
+	record := deadlineMissRecord{
+		TrafficFlowID:   miss.TrafficFlowID,
+		PacketID:        miss.PacketID,
+		Deadline:        miss.Deadline,
+		Latency:         miss.Latency,
+		GenerationCycle: miss.GenerationCycle,
+		ReleaseCycle:    miss.ReleaseCycle,
+		InjectionCycle:  miss.InjectionCycle,
+		ArrivalCycle:    miss.ArrivalCycle,
+		Hops:            make([]hopRecord, len(miss.Hops)),
+		LinkWaits:       make([]linkWaitRecord, len(miss.LinkWaits)),
+	}
+
+	for i := 0; i < len(miss.Hops); i++ {
+		record.Hops[i] = hopRecord{
+			Router:     miss.Hops[i].Router,
+			HeaderIn:   miss.Hops[i].HeaderIn,
+			HeaderOut:  miss.Hops[i].HeaderOut,
+			HeaderWait: miss.Hops[i].HeaderWait(),
+			TailIn:     miss.Hops[i].TailIn,
+			TailOut:    miss.Hops[i].TailOut,
+			TailWait:   miss.Hops[i].TailWait(),
+		}
+	}
+
+	for i := 0; i < len(miss.LinkWaits); i++ {
+		wait := miss.LinkWaits[i]
+
+		record.LinkWaits[i] = linkWaitRecord{
+			Src:        wait.Src,
+			Dst:        wait.Dst,
+			StartCycle: wait.StartCycle,
+			EndCycle:   wait.EndCycle,
+			Occupants:  make([]occupantRecord, len(wait.Occupants)),
+			FlitsByTF:  make(map[string]int),
+		}
+
+		for j := 0; j < len(wait.Occupants); j++ {
+			record.LinkWaits[i].Occupants[j] = occupantRecord{
+				Cycle:         wait.Occupants[j].Cycle,
+				TrafficFlowID: wait.Occupants[j].TrafficFlowID,
+				PacketID:      wait.Occupants[j].PacketID,
+				FlitType:      wait.Occupants[j].FlitType,
+			}
+			record.LinkWaits[i].FlitsByTF[wait.Occupants[j].TrafficFlowID]++
+		}
+	}
+
+	return record
+}
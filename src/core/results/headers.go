@@ -2,12 +2,27 @@ package results
 
 import "strconv"
 
+type ColumnGroup string
+
+const (
+	// Columns output regardless of the enabled column groups.
+	DefaultColumns       ColumnGroup = ""
+	PercentileColumns    ColumnGroup = "percentiles"
+	DecompositionColumns ColumnGroup = "decomposition"
+	InterferenceColumns  ColumnGroup = "interference"
+	FaultColumns         ColumnGroup = "faults"
+	FairnessColumns      ColumnGroup = "fairness"
+	BlockingColumns      ColumnGroup = "blocking"
+	InjectionColumns     ColumnGroup = "injection"
+)
+
 type resultParameter struct {
 	name                string
 	terminalStr         string
 	csvStr              string
 	terminalAllowedFlag bool
 	reqAnalysisFlag     bool
+	columnGroup         ColumnGroup
 	value               func(tf tfSimAnalysis) string
 }
 
@@ -36,6 +51,53 @@ var parameters = []resultParameter{
 		reqAnalysisFlag:     true,
 		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.IndirectInterferenceCount) },
 	},
+	{
+		name:                "Direct Interference Set",
+		terminalStr:         "S^D_i",
+		csvStr:              "Direct_Interference_Set",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     true,
+		columnGroup:         InterferenceColumns,
+		value:               func(tf tfSimAnalysis) string { return joinIDs(tf.TrafficFlowAnalysisSet.Direct) },
+	},
+	{
+		name:                "Observed Direct Interference Set",
+		terminalStr:         "obs S^D_i",
+		csvStr:              "Observed_Direct_Interference_Set",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         InterferenceColumns,
+		value:               func(tf tfSimAnalysis) string { return joinIDs(tf.ObservedInterference.Direct) },
+	},
+	{
+		name:                "Indirect Interference Set",
+		terminalStr:         "S^I_i",
+		csvStr:              "Indirect_Interference_Set",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     true,
+		columnGroup:         InterferenceColumns,
+		value:               func(tf tfSimAnalysis) string { return joinIDs(tf.TrafficFlowAnalysisSet.Indirect) },
+	},
+	{
+		name:                "Observed Indirect Interference Set",
+		terminalStr:         "obs S^I_i",
+		csvStr:              "Observed_Indirect_Interference_Set",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         InterferenceColumns,
+		value:               func(tf tfSimAnalysis) string { return joinIDs(tf.ObservedInterference.Indirect) },
+	},
+	{
+		name:                "Observed Interference Missing From Analysis",
+		terminalStr:         "unpredicted",
+		csvStr:              "Unpredicted_Interference",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     true,
+		columnGroup:         InterferenceColumns,
+		value: func(tf tfSimAnalysis) string {
+			return joinIDs(unpredictedInterference(tf.ObservedInterference, tf.TrafficFlowAnalysisSet.InterferenceSets))
+		},
+	},
 	{
 		name:                "Number of Packets Routed",
 		terminalStr:         "No. pkts",
@@ -66,7 +128,7 @@ var parameters = []resultParameter{
 		csvStr:              "Mean_Latency",
 		terminalAllowedFlag: true,
 		reqAnalysisFlag:     false,
-		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MeanLatency) },
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MeanLatency, false) },
 	},
 	{
 		name:                "Worst Latency",
@@ -76,6 +138,222 @@ var parameters = []resultParameter{
 		reqAnalysisFlag:     false,
 		value:               func(tf tfSimAnalysis) string { return cleanInt(tf.WorstLatency) },
 	},
+	{
+		name:                "50th Percentile Latency",
+		terminalStr:         "p50",
+		csvStr:              "P50_Latency",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         PercentileColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanInt(tf.P50Latency) },
+	},
+	{
+		name:                "90th Percentile Latency",
+		terminalStr:         "p90",
+		csvStr:              "P90_Latency",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         PercentileColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanInt(tf.P90Latency) },
+	},
+	{
+		name:                "99th Percentile Latency",
+		terminalStr:         "p99",
+		csvStr:              "P99_Latency",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         PercentileColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanInt(tf.P99Latency) },
+	},
+	{
+		name:                "99.9th Percentile Latency",
+		terminalStr:         "p99.9",
+		csvStr:              "P99_9_Latency",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         PercentileColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanInt(tf.P999Latency) },
+	},
+	{
+		name:                "Mean Jitter Delay",
+		terminalStr:         "jitter mean",
+		csvStr:              "Mean_Jitter_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MeanJitterDelay, true) },
+	},
+	{
+		name:                "Max Jitter Delay",
+		terminalStr:         "jitter max",
+		csvStr:              "Max_Jitter_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.MaxJitterDelay) },
+	},
+	{
+		name:                "Mean Source Queueing Delay",
+		terminalStr:         "queue mean",
+		csvStr:              "Mean_Queueing_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MeanQueueingDelay, true) },
+	},
+	{
+		name:                "Max Source Queueing Delay",
+		terminalStr:         "queue max",
+		csvStr:              "Max_Queueing_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.MaxQueueingDelay) },
+	},
+	{
+		name:                "Mean Network Traversal Delay",
+		terminalStr:         "network mean",
+		csvStr:              "Mean_Network_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MeanNetworkDelay, true) },
+	},
+	{
+		name:                "Max Network Traversal Delay",
+		terminalStr:         "network max",
+		csvStr:              "Max_Network_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.MaxNetworkDelay) },
+	},
+	{
+		name:                "Mean Serialisation Delay",
+		terminalStr:         "serial mean",
+		csvStr:              "Mean_Serialisation_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MeanSerialisationDelay, true) },
+	},
+	{
+		name:                "Max Serialisation Delay",
+		terminalStr:         "serial max",
+		csvStr:              "Max_Serialisation_Delay",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         DecompositionColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.MaxSerialisationDelay) },
+	},
+	{
+		name:                "Packets Affected By Faults",
+		terminalStr:         "faulted",
+		csvStr:              "Packets_Affected_By_Faults",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         FaultColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.PacketsAffected) },
+	},
+	{
+		name:                "Packets Not Reconstructed",
+		terminalStr:         "unrecon.",
+		csvStr:              "Packets_Unreconstructed",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         FaultColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.PacketsUnreconstructed) },
+	},
+	{
+		name:                "Mean Fault Extra Latency",
+		terminalStr:         "fault extra mean",
+		csvStr:              "Mean_Fault_Extra_Latency",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         FaultColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MeanExtraLatency, true) },
+	},
+	{
+		name:                "Max Fault Extra Latency",
+		terminalStr:         "fault extra max",
+		csvStr:              "Max_Fault_Extra_Latency",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         FaultColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.MaxExtraLatency, true) },
+	},
+	{
+		name:                "Lower Priority Blocking Bound",
+		terminalStr:         "B_i",
+		csvStr:              "Lower_Priority_Blocking_Bound",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     true,
+		columnGroup:         BlockingColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.Blocking) },
+	},
+	{
+		name:                "Observed Lower Priority Blocking",
+		terminalStr:         "obs blocked",
+		csvStr:              "Observed_Lower_Priority_Blocking",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         BlockingColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.LowerPriorityBlocking) },
+	},
+	{
+		name:                "Injection Queue Overflows",
+		terminalStr:         "iq overflows",
+		csvStr:              "Injection_Queue_Overflows",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         InjectionColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.Overflows) },
+	},
+	{
+		name:                "Injection Queue Drops",
+		terminalStr:         "iq dropped",
+		csvStr:              "Injection_Queue_Dropped",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         InjectionColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.PacketsDropped) },
+	},
+	{
+		name:                "Max Injection Queue Depth",
+		terminalStr:         "iq max",
+		csvStr:              "Max_Injection_Queue_Depth",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         InjectionColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.Itoa(tf.MaxQueueDepth) },
+	},
+	{
+		name:                "Throughput",
+		terminalStr:         "flits/cycle",
+		csvStr:              "Throughput",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         FairnessColumns,
+		value:               func(tf tfSimAnalysis) string { return strconv.FormatFloat(tf.Throughput, 'f', 4, 64) },
+	},
+	{
+		name:                "Delivery Ratio",
+		terminalStr:         "delivered",
+		csvStr:              "Delivery_Ratio",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         FairnessColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.DeliveryRatio, true) },
+	},
+	{
+		name:                "Slowdown",
+		terminalStr:         "slowdown",
+		csvStr:              "Slowdown",
+		terminalAllowedFlag: true,
+		reqAnalysisFlag:     false,
+		columnGroup:         FairnessColumns,
+		value:               func(tf tfSimAnalysis) string { return cleanFloat(tf.Slowdown, false) },
+	},
 	{
 		name:                "Deadline",
 		terminalStr:         "D_i",
@@ -0,0 +1,64 @@
+package results
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"main/src/domain"
+)
+
+type histogramBin struct {
+	Latency int `json:"latency"`
+	Packets int `json:"packets"`
+}
+
+// Writes each traffic flow's latency histogram to a json file if the path has a .json extension, otherwise to a csv file.
+func writeHistograms(path string, tfs []tfSim) error {
+	switch filepath.Ext(path) {
+	case ".json":
+		return writeHistogramsJSON(path, tfs)
+	default:
+		return writeHistogramsCSV(path, tfs)
+	}
+}
+
+func writeHistogramsCSV(path string, tfs []tfSim) error {
+	data := [][]string{{"TF_ID", "Latency", "Packets"}}
+
+	for i := 0; i < len(tfs); i++ {
+		bins := histogramBins(tfs[i].LatencyHistogram)
+		for b := 0; b < len(bins); b++ {
+			data = append(data, []string{tfs[i].ID, strconv.Itoa(bins[b].Latency), strconv.Itoa(bins[b].Packets)})
+		}
+	}
+
+	return writeCSV(path, data)
+}
+
+func writeHistogramsJSON(path string, tfs []tfSim) error {
+	histograms := make(map[string][]histogramBin, len(tfs))
+	for i := 0; i < len(tfs); i++ {
+		histograms[tfs[i].ID] = histogramBins(tfs[i].LatencyHistogram)
+	}
+
+	bytes, err := json.MarshalIndent(histograms, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, bytes, 0o644)
+}
+
+// Returns the histogram's bins sorted by ascending latency.
+func histogramBins(histogram domain.LatencyHistogram) []histogramBin {
+	bins := make([]histogramBin, 0, len(histogram))
+	for latency, packets := range histogram {
+		bins = append(bins, histogramBin{Latency: latency, Packets: packets})
+	}
+
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Latency < bins[j].Latency })
+	return bins
+}
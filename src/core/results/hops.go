@@ -0,0 +1,67 @@
+package results
+
+import (
+	"strconv"
+
+	"main/src/domain"
+)
+
+// Writes each traffic flow's per hop header and tail flit waiting times. The hop count is the number of routers
+// traversed, as assumed by basic latency analysis.
+func writeHopStatsCSV(path string, simRes domain.SimResults, tfIDs []string) error {
+	data := [][]string{{
+		"TF_ID", "Hop_Count", "Hop", "Router",
+		"Mean_Header_Wait", "Max_Header_Wait", "Mean_Tail_Wait", "Max_Tail_Wait",
+	}}
+
+	for i := 0; i < len(tfIDs); i++ {
+		hopStats := simRes.TFHopStats[tfIDs[i]]
+		for h := 0; h < len(hopStats); h++ {
+			data = append(data, []string{
+				tfIDs[i],
+				strconv.Itoa(len(hopStats)),
+				strconv.Itoa(hopStats[h].Hop),
+				hopStats[h].Router,
+				cleanFloat(hopStats[h].MeanHeaderWait, true),
+				strconv.Itoa(hopStats[h].MaxHeaderWait),
+				cleanFloat(hopStats[h].MeanTailWait, true),
+				strconv.Itoa(hopStats[h].MaxTailWait),
+			})
+		}
+	}
+
+	return writeCSV(path, data)
+}
+
+// Writes every arrived packet's timeline, one row per hop.
+func writeTimelinesCSV(path string, simRes domain.SimResults, tfIDs []string) error {
+	data := [][]string{{
+		"TF_ID", "Packet_ID", "Generation_Cycle", "Release_Cycle", "Injection_Cycle", "Arrival_Cycle",
+		"Hop", "Router", "Header_In", "Header_Out", "Tail_In", "Tail_Out",
+	}}
+
+	for i := 0; i < len(tfIDs); i++ {
+		timelines := simRes.TFTimelines[tfIDs[i]]
+		for p := 0; p < len(timelines); p++ {
+			for h := 0; h < len(timelines[p].Hops); h++ {
+				hop := timelines[p].Hops[h]
+				data = append(data, []string{
+					tfIDs[i],
+					timelines[p].PacketID,
+					strconv.Itoa(timelines[p].GenerationCycle),
+					strconv.Itoa(timelines[p].ReleaseCycle),
+					strconv.Itoa(timelines[p].InjectionCycle),
+					strconv.Itoa(timelines[p].ArrivalCycle),
+					strconv.Itoa(h),
+					hop.Router,
+					strconv.Itoa(hop.HeaderIn),
+					strconv.Itoa(hop.HeaderOut),
+					strconv.Itoa(hop.TailIn),
+					strconv.Itoa(hop.TailOut),
+				})
+			}
+		}
+	}
+
+	return writeCSV(path, data)
+}
@@ -0,0 +1,52 @@
+package results
+
+import (
+	"sort"
+	"strings"
+
+	"main/src/domain"
+)
+
+// Returns the observed interferers the analysis did not predict: direct interferers outside the analytical direct set
+// and indirect interferers outside both analytical sets.
+func unpredictedInterference(observed, analytical domain.InterferenceSets) []string {
+	direct := make(map[string]bool, len(analytical.Direct))
+	for i := 0; i < len(analytical.Direct); i++ {
+		direct[analytical.Direct[i]] = true
+	}
+
+	predicted := make(map[string]bool, len(analytical.Direct)+len(analytical.Indirect))
+	for id := range direct {
+		predicted[id] = true
+	}
+	for i := 0; i < len(analytical.Indirect); i++ {
+		predicted[analytical.Indirect[i]] = true
+	}
+
+	unpredicted := make(map[string]bool)
+	for i := 0; i < len(observed.Direct); i++ {
+		if !direct[observed.Direct[i]] {
+			unpredicted[observed.Direct[i]] = true
+		}
+	}
+	for i := 0; i < len(observed.Indirect); i++ {
+		if !predicted[observed.Indirect[i]] {
+			unpredicted[observed.Indirect[i]] = true
+		}
+	}
+
+	ids := make([]string, 0, len(unpredicted))
+	for id := range unpredicted {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+func joinIDs(ids []string) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	return strings.Join(ids, " ")
+}
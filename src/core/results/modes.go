@@ -0,0 +1,135 @@
+package results
+
+import (
+	"fmt"
+	"strconv"
+
+	"main/src/domain"
+
+	"github.com/alexeyco/simpletable"
+)
+
+const (
+	modeStatsType  = "mode"
+	modeChangeType = "change"
+)
+
+func writeModeStatsCSV(path string, modes []domain.ModeStats, changes []domain.ModeChange) error {
+	data := [][]string{{
+		"Type", "Mode", "Start_Cycle", "End_Cycle", "TF_ID",
+		"Num_Packets_Routed", "Num_Packets_Arrived", "Num_Packets_Exceeded_Deadline",
+		"Min_Latency", "Mean_Latency", "Max_Latency", "Max_Latency_Packet_ID",
+	}}
+
+	for i := 0; i < len(modes); i++ {
+		for _, tfStats := range modes[i].TFStats {
+			data = append(data, append([]string{
+				modeStatsType,
+				modes[i].ID,
+				strconv.Itoa(modes[i].StartCycle),
+				strconv.Itoa(modes[i].EndCycle),
+			}, modeTFStatsRecord(tfStats)...))
+		}
+	}
+
+	for i := 0; i < len(changes); i++ {
+		for _, tfStats := range changes[i].TFStats {
+			data = append(data, append([]string{
+				modeChangeType,
+				modeChangeName(changes[i]),
+				strconv.Itoa(changes[i].Cycle),
+				"",
+			}, modeTFStatsRecord(tfStats)...))
+		}
+	}
+
+	return writeCSV(path, data)
+}
+
+func modeTFStatsRecord(stats domain.ModeTFStats) []string {
+	return []string{
+		stats.TrafficFlowID,
+		strconv.Itoa(stats.PacketsRouted),
+		strconv.Itoa(stats.PacketsArrived),
+		strconv.Itoa(stats.PacketsExceededDeadline),
+		strconv.Itoa(stats.BestLatency),
+		cleanFloat(stats.MeanLatency, true),
+		strconv.Itoa(stats.WorstLatency),
+		stats.WorstPacketID,
+	}
+}
+
+func modeChangeName(change domain.ModeChange) string {
+	return fmt.Sprintf("%s -> %s", change.FromID, change.ToID)
+}
+
+// Summarises each mode's traffic flows and the packets straddling each mode change, empty if there are no modes.
+func prettifyModes(modes []domain.ModeStats, changes []domain.ModeChange) string {
+	if len(modes) == 0 {
+		return ""
+	}
+
+	str := "\n\nModes\n"
+	str += "=====\n"
+
+	modeTable := simpletable.New()
+	modeTable.Header = &simpletable.Header{Cells: modeTableHeader("Mode", "Cycles")}
+	for i := 0; i < len(modes); i++ {
+		cycles := fmt.Sprintf("%d - %d", modes[i].StartCycle, modes[i].EndCycle)
+		for _, tfStats := range modes[i].TFStats {
+			modeTable.Body.Cells = append(modeTable.Body.Cells, modeTableRow(modes[i].ID, cycles, tfStats))
+		}
+	}
+	str += modeTable.String()
+
+	if len(changes) == 0 {
+		return str
+	}
+
+	str += "\n\nMode Change Straddling Packets\n"
+	str += "==============================\n"
+
+	changeTable := simpletable.New()
+	changeTable.Header = &simpletable.Header{Cells: modeTableHeader("Change", "Cycle")}
+	for i := 0; i < len(changes); i++ {
+		for _, tfStats := range changes[i].TFStats {
+			changeTable.Body.Cells = append(changeTable.Body.Cells, modeTableRow(modeChangeName(changes[i]), strconv.Itoa(changes[i].Cycle), tfStats))
+		}
+	}
+	if len(changeTable.Body.Cells) == 0 {
+		return str + "No packets in flight at any mode change"
+	}
+	str += changeTable.String()
+
+	return str
+}
+
+func modeTableHeader(name, cycles string) []*simpletable.Cell {
+	return []*simpletable.Cell{
+		{Align: simpletable.AlignLeft, Text: name},
+		{Align: simpletable.AlignLeft, Text: cycles},
+		{Align: simpletable.AlignLeft, Text: "TF"},
+		{Align: simpletable.AlignLeft, Text: "No. Packets"},
+		{Align: simpletable.AlignLeft, Text: "arrived"},
+		{Align: simpletable.AlignLeft, Text: "No. > D_i"},
+		{Align: simpletable.AlignLeft, Text: "min"},
+		{Align: simpletable.AlignLeft, Text: "mean"},
+		{Align: simpletable.AlignLeft, Text: "max"},
+		{Align: simpletable.AlignLeft, Text: "max packet"},
+	}
+}
+
+func modeTableRow(name, cycles string, stats domain.ModeTFStats) []*simpletable.Cell {
+	return []*simpletable.Cell{
+		{Align: simpletable.AlignLeft, Text: name},
+		{Align: simpletable.AlignLeft, Text: cycles},
+		{Align: simpletable.AlignLeft, Text: stats.TrafficFlowID},
+		{Align: simpletable.AlignLeft, Text: strconv.Itoa(stats.PacketsRouted)},
+		{Align: simpletable.AlignLeft, Text: strconv.Itoa(stats.PacketsArrived)},
+		{Align: simpletable.AlignLeft, Text: strconv.Itoa(stats.PacketsExceededDeadline)},
+		{Align: simpletable.AlignLeft, Text: cleanInt(stats.BestLatency)},
+		{Align: simpletable.AlignLeft, Text: cleanFloat(stats.MeanLatency, false)},
+		{Align: simpletable.AlignLeft, Text: cleanInt(stats.WorstLatency)},
+		{Align: simpletable.AlignLeft, Text: stats.WorstPacketID},
+	}
+}
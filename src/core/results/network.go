@@ -0,0 +1,136 @@
+package results
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"main/src/domain"
+
+	"github.com/alexeyco/simpletable"
+)
+
+// Number of links and buffers listed in the terminal hotspot summary.
+const hotspotCount = 5
+
+const (
+	linkStatsType      = "link"
+	bufferStatsType    = "buffer"
+	injectionStatsType = "injection"
+	ejectionStatsType  = "ejection"
+)
+
+func writeNetworkStatsCSV(path string, stats domain.NetworkStats) error {
+	data := [][]string{{
+		"Type", "Router", "Port", "VC",
+		"Flits", "Utilisation", "Mean_Occupancy", "Max_Occupancy", "Credit_Blocked_Cycles", "Retransmissions",
+	}}
+
+	for i := 0; i < len(stats.Links); i++ {
+		data = append(data, []string{
+			linkStatsType,
+			stats.Links[i].Src,
+			stats.Links[i].Dst,
+			"",
+			strconv.Itoa(stats.Links[i].Flits),
+			strconv.FormatFloat(stats.Links[i].Utilisation, 'f', 4, 64),
+			"", "", "",
+			strconv.Itoa(stats.Links[i].Retransmissions),
+		})
+	}
+
+	for i := 0; i < len(stats.NetworkInterfaces); i++ {
+		netIntfc := stats.NetworkInterfaces[i]
+		data = append(data, []string{
+			injectionStatsType,
+			netIntfc.Router,
+			netIntfc.ID,
+			"",
+			strconv.Itoa(netIntfc.InjectedFlits),
+			strconv.FormatFloat(netIntfc.InjectionUtilisation, 'f', 4, 64),
+			"", "", "", "",
+		}, []string{
+			ejectionStatsType,
+			netIntfc.Router,
+			netIntfc.ID,
+			"",
+			strconv.Itoa(netIntfc.EjectedFlits),
+			strconv.FormatFloat(netIntfc.EjectionUtilisation, 'f', 4, 64),
+			strconv.FormatFloat(netIntfc.MeanEjectionOccupancy, 'f', 4, 64),
+			strconv.Itoa(netIntfc.MaxEjectionOccupancy),
+			"", "",
+		})
+	}
+
+	for i := 0; i < len(stats.Buffers); i++ {
+		data = append(data, []string{
+			bufferStatsType,
+			stats.Buffers[i].Router,
+			stats.Buffers[i].InputPort,
+			strconv.Itoa(stats.Buffers[i].VirtualChannel),
+			"", "",
+			strconv.FormatFloat(stats.Buffers[i].MeanOccupancy, 'f', 4, 64),
+			strconv.Itoa(stats.Buffers[i].MaxOccupancy),
+			strconv.Itoa(stats.Buffers[i].CreditBlockedCycles),
+			"",
+		})
+	}
+
+	return writeCSV(path, data)
+}
+
+// Summarises the most utilised links and most occupied router input buffers.
+func prettifyHotspots(stats domain.NetworkStats) string {
+	if len(stats.Links) == 0 && len(stats.Buffers) == 0 {
+		return ""
+	}
+
+	links := make([]domain.LinkStats, len(stats.Links))
+	copy(links, stats.Links)
+	sort.SliceStable(links, func(i, j int) bool { return links[i].Utilisation > links[j].Utilisation })
+
+	buffers := make([]domain.BufferStats, len(stats.Buffers))
+	copy(buffers, stats.Buffers)
+	sort.SliceStable(buffers, func(i, j int) bool { return buffers[i].MeanOccupancy > buffers[j].MeanOccupancy })
+
+	str := "\n\nNetwork Hotspots\n"
+	str += "================\n"
+
+	linkTable := simpletable.New()
+	linkTable.Header = &simpletable.Header{Cells: []*simpletable.Cell{
+		{Align: simpletable.AlignLeft, Text: "Link"},
+		{Align: simpletable.AlignLeft, Text: "Flits"},
+		{Align: simpletable.AlignLeft, Text: "Utilisation"},
+	}}
+	for i := 0; i < len(links) && i < hotspotCount; i++ {
+		linkTable.Body.Cells = append(linkTable.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: fmt.Sprintf("%s -> %s", links[i].Src, links[i].Dst)},
+			{Align: simpletable.AlignLeft, Text: strconv.Itoa(links[i].Flits)},
+			{Align: simpletable.AlignLeft, Text: strconv.FormatFloat(links[i].Utilisation, 'f', 4, 64)},
+		})
+	}
+	str += linkTable.String() + "\n"
+
+	bufferTable := simpletable.New()
+	bufferTable.Header = &simpletable.Header{Cells: []*simpletable.Cell{
+		{Align: simpletable.AlignLeft, Text: "Router"},
+		{Align: simpletable.AlignLeft, Text: "Input Port"},
+		{Align: simpletable.AlignLeft, Text: "VC"},
+		{Align: simpletable.AlignLeft, Text: "mean occ."},
+		{Align: simpletable.AlignLeft, Text: "max occ."},
+		{Align: simpletable.AlignLeft, Text: "credit blocked"},
+	}}
+	for i := 0; i < len(buffers) && i < hotspotCount; i++ {
+		bufferTable.Body.Cells = append(bufferTable.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: buffers[i].Router},
+			{Align: simpletable.AlignLeft, Text: buffers[i].InputPort},
+			{Align: simpletable.AlignLeft, Text: strconv.Itoa(buffers[i].VirtualChannel)},
+			{Align: simpletable.AlignLeft, Text: strconv.FormatFloat(buffers[i].MeanOccupancy, 'f', 2, 64)},
+			{Align: simpletable.AlignLeft, Text: strconv.Itoa(buffers[i].MaxOccupancy)},
+			{Align: simpletable.AlignLeft, Text: strconv.Itoa(buffers[i].CreditBlockedCycles)},
+		})
+	}
+	str += bufferTable.String()
+
+	return str
+}
@@ -7,17 +7,37 @@ import (
 )
 
 type Results interface {
+	EnableColumnGroup(group ColumnGroup)
+	// Reports whether the simulation was interrupted before its cycle limit.
+	Truncated() bool
+	// Reports whether the analysis was requested but interrupted before it finished, the results then exclude it.
+	AnalysisInterrupted() bool
+
 	Prettify() (string, error)
 	OutputCSV(path string) error
+	OutputHistograms(path string) error
+	OutputHopStats(path string) error
+	OutputTimelines(path string) error
+	OutputNetworkStats(path string) error
+	OutputDeadlineMisses(path string) error
+	OutputFaultReport(path string) error
+	OutputModeStats(path string) error
+}
+
+type outputConfig struct {
+	columnGroups map[ColumnGroup]bool
 }
 
 type simResults struct {
 	domain.SimResults
-	trafficFlows []tfSim
+	outputConfig
+	trafficFlows        []tfSim
+	analysisInterrupted bool
 }
 
 type simAnalaysisResults struct {
 	domain.SimResults
+	outputConfig
 	trafficFlows []tfSimAnalysis
 }
 
@@ -54,6 +74,18 @@ func NewResults(simRes domain.SimResults, tfOrder []domain.TrafficFlowConfig) (R
 	return &results, nil
 }
 
+// Constructs results without analysis for a run whose analysis was interrupted, such as by the time budget expiring.
+func NewResultsWithInterruptedAnalysis(simRes domain.SimResults, tfOrder []domain.TrafficFlowConfig) (Results, error) {
+	results, err := NewResults(simRes, tfOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	results.(*simResults).analysisInterrupted = true
+
+	return results, nil
+}
+
 func NewResultsWithAnalysis(simRes domain.SimResults, analyses domain.AnalysisResults, tfOrder []domain.TrafficFlowConfig) (Results, error) {
 	var results simAnalaysisResults
 
@@ -89,8 +121,41 @@ func NewResultsWithAnalysis(simRes domain.SimResults, analyses domain.AnalysisRe
 	return &results, nil
 }
 
+func (r *simResults) Truncated() bool {
+	return r.SimHeadlineResults.Truncated
+}
+
+func (r *simAnalaysisResults) Truncated() bool {
+	return r.SimHeadlineResults.Truncated
+}
+
+func (r *simResults) AnalysisInterrupted() bool {
+	return r.analysisInterrupted
+}
+
+func (r *simAnalaysisResults) AnalysisInterrupted() bool {
+	return false
+}
+
+func (o *outputConfig) EnableColumnGroup(group ColumnGroup) {
+	if o.columnGroups == nil {
+		o.columnGroups = make(map[ColumnGroup]bool)
+	}
+	o.columnGroups[group] = true
+}
+
+func (o *outputConfig) columnEnabled(param resultParameter) bool {
+	return param.columnGroup == DefaultColumns || o.columnGroups[param.columnGroup]
+}
+
 func (r *simResults) Prettify() (string, error) {
 	str := prettifySimHeadlineResults(r.SimHeadlineResults)
+	if r.analysisInterrupted {
+		str += "Analysis: interrupted, results exclude analysis\n\n"
+	}
+	if r.columnGroups[FairnessColumns] {
+		str += prettifyFairness(r.SimHeadlineResults.FairnessIndices)
+	}
 
 	str += "Traffic Flow domain.Results\n"
 	str += "====================\n"
@@ -99,7 +164,7 @@ func (r *simResults) Prettify() (string, error) {
 
 	table.Header = &simpletable.Header{Cells: []*simpletable.Cell{}}
 	for i := 0; i < len(parameters); i++ {
-		if parameters[i].terminalAllowedFlag && !parameters[i].reqAnalysisFlag {
+		if parameters[i].terminalAllowedFlag && !parameters[i].reqAnalysisFlag && r.columnEnabled(parameters[i]) {
 			table.Header.Cells = append(table.Header.Cells, &simpletable.Cell{Align: simpletable.AlignLeft, Text: parameters[i].terminalStr})
 		}
 	}
@@ -107,7 +172,7 @@ func (r *simResults) Prettify() (string, error) {
 	for i := 0; i < len(r.trafficFlows); i++ {
 		row := []*simpletable.Cell{}
 		for p := 0; p < len(parameters); p++ {
-			if parameters[p].terminalAllowedFlag && !parameters[p].reqAnalysisFlag {
+			if parameters[p].terminalAllowedFlag && !parameters[p].reqAnalysisFlag && r.columnEnabled(parameters[p]) {
 				row = append(row, &simpletable.Cell{
 					Align: simpletable.AlignLeft,
 					Text:  parameters[p].value(tfSimAnalysis{tfSim: r.trafficFlows[i]}),
@@ -118,12 +183,18 @@ func (r *simResults) Prettify() (string, error) {
 	}
 
 	str += table.String()
+	str += prettifyHotspots(r.NetworkStats)
+	str += prettifyFaults(r.FaultReport)
+	str += prettifyModes(r.Modes, r.ModeChanges)
 
 	return str, nil
 }
 
 func (r *simAnalaysisResults) Prettify() (string, error) {
 	str := prettifySimHeadlineResults(r.SimHeadlineResults)
+	if r.columnGroups[FairnessColumns] {
+		str += prettifyFairness(r.SimHeadlineResults.FairnessIndices)
+	}
 
 	str += "Traffic Flow domain.Results\n"
 	str += "====================\n"
@@ -132,7 +203,7 @@ func (r *simAnalaysisResults) Prettify() (string, error) {
 
 	table.Header = &simpletable.Header{Cells: []*simpletable.Cell{}}
 	for i := 0; i < len(parameters); i++ {
-		if parameters[i].terminalAllowedFlag {
+		if parameters[i].terminalAllowedFlag && r.columnEnabled(parameters[i]) {
 			table.Header.Cells = append(table.Header.Cells, &simpletable.Cell{Align: simpletable.AlignLeft, Text: parameters[i].terminalStr})
 		}
 	}
@@ -140,7 +211,7 @@ func (r *simAnalaysisResults) Prettify() (string, error) {
 	for i := 0; i < len(r.trafficFlows); i++ {
 		row := []*simpletable.Cell{}
 		for p := 0; p < len(parameters); p++ {
-			if parameters[p].terminalAllowedFlag {
+			if parameters[p].terminalAllowedFlag && r.columnEnabled(parameters[p]) {
 				row = append(row, &simpletable.Cell{
 					Align: simpletable.AlignLeft,
 					Text:  parameters[p].value(r.trafficFlows[i]),
@@ -151,6 +222,9 @@ func (r *simAnalaysisResults) Prettify() (string, error) {
 	}
 
 	str += table.String()
+	str += prettifyHotspots(r.NetworkStats)
+	str += prettifyFaults(r.FaultReport)
+	str += prettifyModes(r.Modes, r.ModeChanges)
 
 	return str, nil
 }
@@ -160,7 +234,7 @@ func (r *simResults) OutputCSV(path string) error {
 
 	header := []string{}
 	for i := 0; i < len(parameters); i++ {
-		if !parameters[i].reqAnalysisFlag {
+		if !parameters[i].reqAnalysisFlag && r.columnEnabled(parameters[i]) {
 			header = append(header, parameters[i].csvStr)
 		}
 	}
@@ -169,7 +243,7 @@ func (r *simResults) OutputCSV(path string) error {
 	for i := 0; i < len(r.trafficFlows); i++ {
 		row := []string{}
 		for p := 0; p < len(parameters); p++ {
-			if !parameters[p].reqAnalysisFlag {
+			if !parameters[p].reqAnalysisFlag && r.columnEnabled(parameters[p]) {
 				row = append(row, parameters[p].value(tfSimAnalysis{tfSim: r.trafficFlows[i]}))
 			}
 		}
@@ -184,17 +258,101 @@ func (r *simAnalaysisResults) OutputCSV(path string) error {
 
 	header := []string{}
 	for i := 0; i < len(parameters); i++ {
-		header = append(header, parameters[i].csvStr)
+		if r.columnEnabled(parameters[i]) {
+			header = append(header, parameters[i].csvStr)
+		}
 	}
 	data = append(data, header)
 
 	for i := 0; i < len(r.trafficFlows); i++ {
 		row := []string{}
 		for p := 0; p < len(parameters); p++ {
-			row = append(row, parameters[p].value(r.trafficFlows[i]))
+			if r.columnEnabled(parameters[p]) {
+				row = append(row, parameters[p].value(r.trafficFlows[i]))
+			}
 		}
 		data = append(data, row)
 	}
 
 	return writeCSV(path, data)
 }
+
+func (r *simResults) OutputHistograms(path string) error {
+	tfs := make([]tfSim, len(r.trafficFlows))
+	copy(tfs, r.trafficFlows)
+
+	return writeHistograms(path, tfs)
+}
+
+func (r *simAnalaysisResults) OutputHistograms(path string) error {
+	tfs := make([]tfSim, len(r.trafficFlows))
+	for i := 0; i < len(r.trafficFlows); i++ {
+		tfs[i] = r.trafficFlows[i].tfSim
+	}
+
+	return writeHistograms(path, tfs)
+}
+
+func (r *simResults) OutputHopStats(path string) error {
+	return writeHopStatsCSV(path, r.SimResults, r.tfIDs())
+}
+
+func (r *simAnalaysisResults) OutputHopStats(path string) error {
+	return writeHopStatsCSV(path, r.SimResults, r.tfIDs())
+}
+
+func (r *simResults) OutputTimelines(path string) error {
+	return writeTimelinesCSV(path, r.SimResults, r.tfIDs())
+}
+
+func (r *simAnalaysisResults) OutputTimelines(path string) error {
+	return writeTimelinesCSV(path, r.SimResults, r.tfIDs())
+}
+
+func (r *simResults) OutputNetworkStats(path string) error {
+	return writeNetworkStatsCSV(path, r.NetworkStats)
+}
+
+func (r *simAnalaysisResults) OutputNetworkStats(path string) error {
+	return writeNetworkStatsCSV(path, r.NetworkStats)
+}
+
+func (r *simResults) OutputDeadlineMisses(path string) error {
+	return writeDeadlineMissesJSON(path, r.DeadlineMisses)
+}
+
+func (r *simAnalaysisResults) OutputDeadlineMisses(path string) error {
+	return writeDeadlineMissesJSON(path, r.DeadlineMisses)
+}
+
+func (r *simResults) OutputFaultReport(path string) error {
+	return writeFaultReportCSV(path, r.FaultReport)
+}
+
+func (r *simAnalaysisResults) OutputFaultReport(path string) error {
+	return writeFaultReportCSV(path, r.FaultReport)
+}
+
+func (r *simResults) OutputModeStats(path string) error {
+	return writeModeStatsCSV(path, r.Modes, r.ModeChanges)
+}
+
+func (r *simAnalaysisResults) OutputModeStats(path string) error {
+	return writeModeStatsCSV(path, r.Modes, r.ModeChanges)
+}
+
+func (r *simResults) tfIDs() []string {
+	ids := make([]string, len(r.trafficFlows))
+	for i := 0; i < len(r.trafficFlows); i++ {
+		ids[i] = r.trafficFlows[i].ID
+	}
+	return ids
+}
+
+func (r *simAnalaysisResults) tfIDs() []string {
+	ids := make([]string, len(r.trafficFlows))
+	for i := 0; i < len(r.trafficFlows); i++ {
+		ids[i] = r.trafficFlows[i].ID
+	}
+	return ids
+}
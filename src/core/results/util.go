@@ -13,7 +13,11 @@ import (
 func prettifySimHeadlineResults(r domain.SimHeadlineResults) string {
 	str := "Simulation domain.Results\n"
 	str += "==================\n"
-	str += fmt.Sprintf("Cycles: %d\n", r.Cycles)
+	if r.Truncated {
+		str += fmt.Sprintf("Cycles: %d of %d (stopped early, partial results)\n", r.Cycles, r.CycleLimit)
+	} else {
+		str += fmt.Sprintf("Cycles: %d\n", r.Cycles)
+	}
 	str += fmt.Sprintf("Duration (ms): %d\n\n", r.Duration.Milliseconds())
 	str += fmt.Sprintf("Packets Routed: %d\n", r.PacketsRouted)
 	str += fmt.Sprintf("Packets Exceeded Deadline: %d\n", r.PacketsExceededDeadline)
@@ -21,6 +25,13 @@ func prettifySimHeadlineResults(r domain.SimHeadlineResults) string {
 	return str
 }
 
+func prettifyFairness(f domain.FairnessIndices) string {
+	str := fmt.Sprintf("Delivery Fairness (Jain): %s\n", cleanFloat(f.DeliveryFairness, false))
+	str += fmt.Sprintf("Slowdown Fairness (Jain): %s\n", cleanFloat(f.SlowdownFairness, false))
+	str += "\n"
+	return str
+}
+
 func cleanInt(val int) string {
 	if val == math.MaxInt || val == 0 || val == math.MinInt {
 		return "-"
@@ -28,8 +39,10 @@ func cleanInt(val int) string {
 	return strconv.Itoa(val)
 }
 
-func cleanFloat(val float64) string {
-	if math.IsNaN(val) || val == 0 {
+// Formats the value to 2 decimal places, or "-" when NaN or zero, keepZero formats zero for values where it is
+// meaningful.
+func cleanFloat(val float64, keepZero bool) string {
+	if math.IsNaN(val) || (val == 0 && !keepZero) {
 		return "-"
 	}
 	return strconv.FormatFloat(val, 'f', 2, 64)
@@ -0,0 +1,65 @@
+package simulation
+
+import (
+	"math"
+
+	"main/src/domain"
+)
+
+// Returns the traffic flow's throughput, in flits per cycle over the cycles simulated, delivery ratio and slowdown.
+func (r *Records) fairnessStatsByTF(tfID string, cycles int) domain.FairnessStats {
+	var stats domain.FairnessStats
+
+	flits := 0
+	for _, pkt := range r.ArrivedByTF[tfID] {
+		flits += pkt.Packet.PacketSize()
+	}
+	if cycles > 0 {
+		stats.Throughput = float64(flits) / float64(cycles)
+	}
+
+	if routed := r.noTransmittedByTF(tfID); routed > 0 {
+		stats.DeliveryRatio = float64(r.noArrivedByTF(tfID)) / float64(routed)
+	}
+
+	if r.noArrivedByTF(tfID) > 0 {
+		stats.Slowdown = r.meanLatencyByTF(tfID) / float64(r.bestLatencyByTF(tfID))
+	}
+
+	return stats
+}
+
+// Returns Jain's fairness indices of the traffic flows' delivery ratios and slowdowns, flows which routed no packets
+// are excluded from the delivery index and flows with no arrived packets from the slowdown index.
+func fairnessIndices(tfStats map[string]domain.StatSet) domain.FairnessIndices {
+	deliveryRatios := make([]float64, 0, len(tfStats))
+	slowdowns := make([]float64, 0, len(tfStats))
+
+	for _, stats := range tfStats {
+		if stats.PacketsRouted > 0 {
+			deliveryRatios = append(deliveryRatios, stats.DeliveryRatio)
+		}
+		if stats.PacketsArrived > 0 {
+			slowdowns = append(slowdowns, stats.Slowdown)
+		}
+	}
+
+	return domain.FairnessIndices{
+		DeliveryFairness: jainIndex(deliveryRatios),
+		SlowdownFairness: jainIndex(slowdowns),
+	}
+}
+
+// Returns Jain's fairness index, (Σx)² / (n·Σx²), of the values, NaN if there are none or all are 0.
+func jainIndex(vals []float64) float64 {
+	var sum, sumSquares float64
+	for _, val := range vals {
+		sum += val
+		sumSquares += val * val
+	}
+
+	if sumSquares == 0 {
+		return math.NaN()
+	}
+	return (sum * sum) / (float64(len(vals)) * sumSquares)
+}
@@ -0,0 +1,56 @@
+package simulation
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJainIndex(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, math.IsNaN(jainIndex(nil)))
+	assert.True(t, math.IsNaN(jainIndex([]float64{0, 0})))
+	assert.InDelta(t, 1, jainIndex([]float64{2, 2, 2}), 1e-9)
+	assert.InDelta(t, 0.25, jainIndex([]float64{1, 0, 0, 0}), 1e-9)
+	assert.InDelta(t, 0.9, jainIndex([]float64{1, 2}), 1e-9)
+}
+
+func TestFairnessStatsByTF(t *testing.T) {
+	t.Parallel()
+
+	pkt := packet.NewPacket("t1", "a", 1, 100, domain.Route{"n1", "n2"}, 4, zerolog.New(io.Discard))
+
+	rcrds := newRecords(zerolog.New(io.Discard))
+	rcrds.TransmittedByTF["t1"] = map[string]transmittedPacket{
+		"b": {Packet: pkt},
+	}
+	rcrds.ArrivedByTF["t1"] = map[string]arrivedPacket{
+		"a": {transmittedPacket: transmittedPacket{Packet: pkt, GenerationCycle: 0}, ReceivedCycle: 9},
+		"c": {transmittedPacket: transmittedPacket{Packet: pkt, GenerationCycle: 10}, ReceivedCycle: 39},
+	}
+
+	stats := rcrds.fairnessStatsByTF("t1", 80)
+	assert.InDelta(t, 0.1, stats.Throughput, 1e-9)
+	assert.InDelta(t, 2.0/3.0, stats.DeliveryRatio, 1e-9)
+	assert.InDelta(t, 2, stats.Slowdown, 1e-9)
+}
+
+func TestFairnessIndices(t *testing.T) {
+	t.Parallel()
+
+	indices := fairnessIndices(map[string]domain.StatSet{
+		"t1": {PacketsRouted: 2, PacketsArrived: 2, FairnessStats: domain.FairnessStats{DeliveryRatio: 1, Slowdown: 1}},
+		"t2": {PacketsRouted: 2, PacketsArrived: 1, FairnessStats: domain.FairnessStats{DeliveryRatio: 0.5, Slowdown: 2}},
+		"t3": {},
+	})
+
+	assert.InDelta(t, 0.9, indices.DeliveryFairness, 1e-9)
+	assert.InDelta(t, 0.9, indices.SlowdownFairness, 1e-9)
+}
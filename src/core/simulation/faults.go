@@ -0,0 +1,132 @@
+package simulation
+
+import (
+	"sort"
+
+	"main/src/domain"
+)
+
+// Builds the report of the packets affected by each fault, and the per traffic flow fault statistics.
+func (r *Records) faultReport(faultLog domain.FaultLog) (domain.FaultReport, map[string]domain.FaultStats) {
+	report := domain.FaultReport{
+		Faults:  make([]domain.FaultOutcome, len(faultLog.Faults)),
+		Packets: make([]domain.FaultAffectedPacket, 0, len(faultLog.Affected)),
+	}
+	stats := make(map[string]domain.FaultStats)
+
+	for i := 0; i < len(faultLog.Faults); i++ {
+		report.Faults[i] = domain.FaultOutcome{FaultConfig: faultLog.Faults[i]}
+		if i < len(faultLog.Activations) {
+			report.Faults[i].Activations = faultLog.Activations[i]
+		}
+	}
+
+	if len(faultLog.Affected) == 0 {
+		return report, stats
+	}
+
+	baselines := r.unaffectedMeanLatencies(faultLog.Affected)
+
+	for tfID := range r.TransmittedByTF {
+		for _, pkt := range r.TransmittedByTF[tfID] {
+			faults, affected := faultLog.Affected[pkt.Packet.ID()]
+			if !affected {
+				continue
+			}
+
+			status := domain.PacketLost
+			if _, discarded := faultLog.Discarded[pkt.Packet.ID()]; discarded {
+				status = domain.PacketDiscarded
+			}
+
+			report.Packets = append(report.Packets, domain.FaultAffectedPacket{
+				TrafficFlowID: tfID,
+				PacketID:      pkt.Packet.ID(),
+				Faults:        faults,
+				Status:        status,
+			})
+		}
+	}
+
+	for tfID := range r.ArrivedByTF {
+		for _, pkt := range r.ArrivedByTF[tfID] {
+			faults, affected := faultLog.Affected[pkt.Packet.ID()]
+			if !affected {
+				continue
+			}
+
+			latency := arrivedPacketLatency(pkt)
+			extraLatency := 0.0
+			if baseline, exists := baselines[tfID]; exists {
+				extraLatency = latency - baseline
+			}
+
+			report.Packets = append(report.Packets, domain.FaultAffectedPacket{
+				TrafficFlowID: tfID,
+				PacketID:      pkt.Packet.ID(),
+				Faults:        faults,
+				Status:        domain.PacketArrived,
+				Latency:       int(latency),
+				ExtraLatency:  extraLatency,
+			})
+		}
+	}
+
+	sort.Slice(report.Packets, func(i, j int) bool {
+		if report.Packets[i].TrafficFlowID == report.Packets[j].TrafficFlowID {
+			return report.Packets[i].PacketID < report.Packets[j].PacketID
+		}
+		return report.Packets[i].TrafficFlowID < report.Packets[j].TrafficFlowID
+	})
+
+	arrivedByTF := make(map[string]int)
+	for i := 0; i < len(report.Packets); i++ {
+		pkt := report.Packets[i]
+		for _, fault := range pkt.Faults {
+			if fault < len(report.Faults) {
+				report.Faults[fault].PacketsAffected++
+			}
+		}
+
+		tfStats := stats[pkt.TrafficFlowID]
+		tfStats.PacketsAffected++
+		if pkt.Status == domain.PacketArrived {
+			tfStats.MeanExtraLatency += pkt.ExtraLatency
+			tfStats.MaxExtraLatency = max(tfStats.MaxExtraLatency, pkt.ExtraLatency)
+			arrivedByTF[pkt.TrafficFlowID]++
+		} else {
+			tfStats.PacketsUnreconstructed++
+		}
+		stats[pkt.TrafficFlowID] = tfStats
+	}
+
+	for tfID, tfStats := range stats {
+		if arrivedByTF[tfID] > 0 {
+			tfStats.MeanExtraLatency /= float64(arrivedByTF[tfID])
+			stats[tfID] = tfStats
+		}
+	}
+
+	return report, stats
+}
+
+// Returns the mean latency of each traffic flow's arrived packets which were not affected by faults.
+func (r *Records) unaffectedMeanLatencies(affected map[string][]int) map[string]float64 {
+	means := make(map[string]float64)
+
+	for tfID := range r.ArrivedByTF {
+		total, count := 0.0, 0
+		for _, pkt := range r.ArrivedByTF[tfID] {
+			if _, exists := affected[pkt.Packet.ID()]; !exists {
+				total += arrivedPacketLatency(pkt)
+				count++
+			}
+		}
+
+		if count > 0 {
+			means[tfID] = total / float64(count)
+		}
+	}
+
+	return means
+}
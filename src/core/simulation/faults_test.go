@@ -0,0 +1,70 @@
+package simulation
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultReport(t *testing.T) {
+	t.Parallel()
+
+	route := domain.Route{"n0", "n1"}
+	newPkt := func(index string) packet.Packet {
+		return packet.NewPacket("t1", index, 1, 100, route, 3, zerolog.New(io.Discard))
+	}
+	arrived := func(index string, generation, received float64) arrivedPacket {
+		return arrivedPacket{
+			transmittedPacket: transmittedPacket{GenerationCycle: generation, Packet: newPkt(index)},
+			ReceivedCycle:     received,
+		}
+	}
+
+	rcrds := newRecords(zerolog.New(io.Discard))
+	rcrds.ArrivedByTF["t1"] = map[string]arrivedPacket{
+		"0": arrived("0", 0, 9),
+		"1": arrived("1", 100, 111),
+		"2": arrived("2", 200, 229),
+	}
+	rcrds.TransmittedByTF["t1"] = map[string]transmittedPacket{
+		"3": {GenerationCycle: 300, Packet: newPkt("3")},
+		"4": {GenerationCycle: 400, Packet: newPkt("4")},
+	}
+
+	faultLog := domain.FaultLog{
+		Faults: []domain.FaultConfig{
+			{Type: domain.LinkDownFault, Src: "n0", Dst: "n1"},
+			{Type: domain.FlitCorruptFault, FlitID: "t1-3-1"},
+		},
+		Activations: []int{10, 1},
+		Affected:    map[string][]int{"t1-2": {0}, "t1-3": {1}},
+		Discarded:   map[string]string{"t1-3": domain.ErrCorruptPacket.Error()},
+	}
+
+	report, stats := rcrds.faultReport(faultLog)
+
+	require.Len(t, report.Packets, 2)
+	assert.Equal(t, domain.FaultAffectedPacket{
+		TrafficFlowID: "t1", PacketID: "t1-2", Faults: []int{0}, Status: domain.PacketArrived, Latency: 30, ExtraLatency: 19,
+	}, report.Packets[0])
+	assert.Equal(t, domain.FaultAffectedPacket{
+		TrafficFlowID: "t1", PacketID: "t1-3", Faults: []int{1}, Status: domain.PacketDiscarded,
+	}, report.Packets[1])
+
+	assert.Equal(t, 10, report.Faults[0].Activations)
+	assert.Equal(t, 1, report.Faults[0].PacketsAffected)
+	assert.Equal(t, 1, report.Faults[1].PacketsAffected)
+
+	assert.Equal(t, domain.FaultStats{
+		PacketsAffected:        2,
+		PacketsUnreconstructed: 1,
+		MeanExtraLatency:       19,
+		MaxExtraLatency:        19,
+	}, stats["t1"])
+}
@@ -0,0 +1,88 @@
+package simulation
+
+import (
+	"sort"
+
+	"main/src/domain"
+)
+
+// Returns a forensic record of every arrived packet which exceeded its deadline, ordered by traffic flow then
+// generation cycle. Link occupants are only reported for links with a traversal log.
+func (r *Records) deadlineMisses(traversals map[domain.Link][]domain.LinkTraversal) []domain.DeadlineMiss {
+	misses := make([]domain.DeadlineMiss, 0)
+
+	for tfID := range r.ArrivedByTF {
+		for _, pkt := range r.ArrivedByTF[tfID] {
+			if arrivedPacketInDeadline(pkt) {
+				continue
+			}
+
+			misses = append(misses, domain.DeadlineMiss{
+				PacketTimeline: arrivedPacketTimeline(pkt),
+				TrafficFlowID:  tfID,
+				Deadline:       pkt.Packet.Deadline(),
+				Latency:        int(arrivedPacketLatency(pkt)),
+				LinkWaits:      linkWaits(pkt, traversals),
+			})
+		}
+	}
+
+	sort.Slice(misses, func(i, j int) bool {
+		if misses[i].TrafficFlowID != misses[j].TrafficFlowID {
+			return misses[i].TrafficFlowID < misses[j].TrafficFlowID
+		}
+		if misses[i].GenerationCycle != misses[j].GenerationCycle {
+			return misses[i].GenerationCycle < misses[j].GenerationCycle
+		}
+		return misses[i].PacketID < misses[j].PacketID
+	})
+	return misses
+}
+
+// Returns the packet's wait on each link along its route, from the source network interface's injection link to the
+// destination router's ejection link. Returns nil if the packet's hop timestamps are incomplete.
+func linkWaits(pkt arrivedPacket, traversals map[domain.Link][]domain.LinkTraversal) []domain.LinkWait {
+	if len(pkt.Hops) == 0 {
+		return nil
+	}
+
+	waits := make([]domain.LinkWait, 0, len(pkt.Hops)+1)
+
+	injection := domain.Link{Src: domain.NetworkInterfacePort(pkt.Packet.Source(), pkt.Hops[0].Router), Dst: pkt.Hops[0].Router}
+	waits = append(waits, linkWait(injection, int(pkt.TransmissionCycle), pkt.Hops[0].TailIn, pkt.Packet.TrafficFlowID(), traversals))
+
+	for i := 0; i < len(pkt.Hops); i++ {
+		link := domain.Link{Src: pkt.Hops[i].Router, Dst: domain.NetworkInterfacePort(pkt.Packet.Destination(), pkt.Hops[i].Router)}
+		if i+1 < len(pkt.Hops) {
+			link.Dst = pkt.Hops[i+1].Router
+		}
+
+		waits = append(waits, linkWait(link, pkt.Hops[i].HeaderIn, pkt.Hops[i].TailOut, pkt.Packet.TrafficFlowID(), traversals))
+	}
+
+	return waits
+}
+
+func linkWait(link domain.Link, start, end int, tfID string, traversals map[domain.Link][]domain.LinkTraversal) domain.LinkWait {
+	return domain.LinkWait{
+		Link:       link,
+		StartCycle: start,
+		EndCycle:   end,
+		Occupants:  linkOccupants(traversals[link], tfID, start, end),
+	}
+}
+
+// Returns the other traffic flows' flits sent over the link between the start and end cycles inclusive, assumes the
+// traversals are ordered by cycle.
+func linkOccupants(traversals []domain.LinkTraversal, tfID string, start, end int) []domain.LinkTraversal {
+	occupants := make([]domain.LinkTraversal, 0)
+
+	first := sort.Search(len(traversals), func(i int) bool { return traversals[i].Cycle >= start })
+	for i := first; i < len(traversals) && traversals[i].Cycle <= end; i++ {
+		if traversals[i].TrafficFlowID != tfID {
+			occupants = append(occupants, traversals[i])
+		}
+	}
+
+	return occupants
+}
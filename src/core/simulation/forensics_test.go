@@ -0,0 +1,74 @@
+package simulation
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkOccupants(t *testing.T) {
+	t.Parallel()
+
+	traversals := []domain.LinkTraversal{
+		{Cycle: 1, TrafficFlowID: "t1"},
+		{Cycle: 2, TrafficFlowID: "t2"},
+		{Cycle: 3, TrafficFlowID: "t1"},
+		{Cycle: 5, TrafficFlowID: "t3"},
+		{Cycle: 8, TrafficFlowID: "t1"},
+	}
+
+	assert.Equal(t, []domain.LinkTraversal{{Cycle: 3, TrafficFlowID: "t1"}, {Cycle: 5, TrafficFlowID: "t3"}}, linkOccupants(traversals, "t2", 2, 5))
+	assert.Empty(t, linkOccupants(traversals, "t1", 6, 7))
+	assert.Empty(t, linkOccupants(nil, "t1", 0, 10))
+}
+
+func TestDeadlineMisses(t *testing.T) {
+	t.Parallel()
+
+	route := domain.Route{"n0", "n1"}
+	hops := []domain.HopTimestamps{
+		{Router: "n0", HeaderIn: 1, HeaderOut: 2, TailIn: 4, TailOut: 5},
+		{Router: "n1", HeaderIn: 2, HeaderOut: 3, TailIn: 5, TailOut: 6},
+	}
+
+	rcrds := newRecords(zerolog.New(io.Discard))
+	rcrds.ArrivedByTF["t2"] = map[string]arrivedPacket{
+		"0": {
+			transmittedPacket: transmittedPacket{
+				GenerationCycle: 0,
+				Packet:          packet.NewPacket("t2", "0", 2, 5, route, 3, zerolog.New(io.Discard)),
+			},
+			ReceivedCycle: 6,
+			Hops:          hops,
+		},
+		"1": {
+			transmittedPacket: transmittedPacket{
+				GenerationCycle:   100,
+				TransmissionCycle: 100,
+				Packet:            packet.NewPacket("t2", "1", 2, 50, route, 3, zerolog.New(io.Discard)),
+			},
+			ReceivedCycle: 106,
+		},
+	}
+
+	traversals := map[domain.Link][]domain.LinkTraversal{
+		{Src: "n0", Dst: "n1"}: {{Cycle: 2, TrafficFlowID: "t1"}, {Cycle: 3, TrafficFlowID: "t2"}, {Cycle: 9, TrafficFlowID: "t1"}},
+	}
+
+	misses := rcrds.deadlineMisses(traversals)
+	require.Len(t, misses, 1)
+	assert.Equal(t, "t2", misses[0].TrafficFlowID)
+	assert.Equal(t, 7, misses[0].Latency)
+	assert.Equal(t, 5, misses[0].Deadline)
+
+	require.Len(t, misses[0].LinkWaits, 3)
+	assert.Equal(t, domain.LinkWait{Link: domain.Link{Src: domain.LocalPort, Dst: "n0"}, StartCycle: 0, EndCycle: 4, Occupants: []domain.LinkTraversal{}}, misses[0].LinkWaits[0])
+	assert.Equal(t, domain.LinkWait{Link: domain.Link{Src: "n0", Dst: "n1"}, StartCycle: 1, EndCycle: 5, Occupants: []domain.LinkTraversal{{Cycle: 2, TrafficFlowID: "t1"}}}, misses[0].LinkWaits[1])
+	assert.Equal(t, domain.Link{Src: "n1", Dst: domain.LocalPort}, misses[0].LinkWaits[2].Link)
+}
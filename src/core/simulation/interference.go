@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"sort"
+
+	"main/src/domain"
+)
+
+// Derives a traffic flow's observed interference sets from the blocking recorded during simulation. The direct set
+// holds the flows whose flits blocked the traffic flow's flits, the indirect set holds the flows which, transitively,
+// blocked a direct interferer without blocking the traffic flow itself.
+func observedInterference(interference map[string]map[string]int, tfID string) domain.InterferenceSets {
+	direct := make(map[string]bool, len(interference[tfID]))
+	for interferingTF := range interference[tfID] {
+		direct[interferingTF] = true
+	}
+
+	indirect := make(map[string]bool)
+	visited := map[string]bool{tfID: true}
+	pending := make([]string, 0, len(direct))
+	for interferingTF := range direct {
+		pending = append(pending, interferingTF)
+	}
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		for interferingTF := range interference[current] {
+			if interferingTF != tfID && !direct[interferingTF] {
+				indirect[interferingTF] = true
+			}
+			pending = append(pending, interferingTF)
+		}
+	}
+
+	return domain.InterferenceSets{
+		Direct:   sortedIDs(direct),
+		Indirect: sortedIDs(indirect),
+	}
+}
+
+// Returns the cycles lower priority traffic flows' flits held an output port, or injection link, the traffic flow's
+// flits were waiting to be sent through.
+func lowerPriorityBlocking(interference map[string]map[string]int, tfID string, priorities map[string]int) int {
+	blocking := 0
+	for interferingTF, cycles := range interference[tfID] {
+		if priorities[interferingTF] > priorities[tfID] {
+			blocking += cycles
+		}
+	}
+	return blocking
+}
+
+func sortedIDs(set map[string]bool) []string {
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,52 @@
+package simulation
+
+import (
+	"testing"
+
+	"main/src/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObservedInterference(t *testing.T) {
+	t.Parallel()
+
+	interference := map[string]map[string]int{
+		"t4": {"t3": 2},
+		"t3": {"t2": 5, "t1": 1},
+		"t2": {"t1": 3},
+	}
+
+	testCases := map[string]struct {
+		tfID     string
+		expected domain.InterferenceSets
+	}{
+		"NoInterference": {tfID: "t1", expected: domain.InterferenceSets{Direct: []string{}, Indirect: []string{}}},
+		"DirectOnly":     {tfID: "t2", expected: domain.InterferenceSets{Direct: []string{"t1"}, Indirect: []string{}}},
+		"DirectShared":   {tfID: "t3", expected: domain.InterferenceSets{Direct: []string{"t1", "t2"}, Indirect: []string{}}},
+		"Transitive":     {tfID: "t4", expected: domain.InterferenceSets{Direct: []string{"t3"}, Indirect: []string{"t1", "t2"}}},
+		"UnknownFlow":    {tfID: "t5", expected: domain.InterferenceSets{Direct: []string{}, Indirect: []string{}}},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, observedInterference(interference, tc.tfID))
+		})
+	}
+}
+
+func TestLowerPriorityBlocking(t *testing.T) {
+	t.Parallel()
+
+	interference := map[string]map[string]int{
+		"t2": {"t1": 3, "t3": 4, "t4": 1},
+		"t3": {"t1": 2},
+	}
+	priorities := map[string]int{"t1": 1, "t2": 2, "t3": 3, "t4": 4}
+
+	assert.Equal(t, 5, lowerPriorityBlocking(interference, "t2", priorities))
+	assert.Equal(t, 0, lowerPriorityBlocking(interference, "t3", priorities))
+	assert.Equal(t, 0, lowerPriorityBlocking(interference, "t1", priorities))
+}
@@ -0,0 +1,92 @@
+package simulation
+
+import (
+	"main/src/domain"
+)
+
+// Returns per mode statistics of the packets generated while each mode was active, and per mode change statistics of
+// the packets straddling each change, ordered by the traffic flow IDs. Returns nil if there are no modes.
+func (r *Records) modeStats(modes []domain.ModeConfig, tfIDs []string, cycleLimit int) ([]domain.ModeStats, []domain.ModeChange) {
+	if len(modes) == 0 {
+		return nil, nil
+	}
+
+	stats := make([]domain.ModeStats, len(modes))
+	for i := 0; i < len(modes); i++ {
+		endCycle := cycleLimit
+		if i+1 < len(modes) {
+			endCycle = modes[i+1].StartCycle
+		}
+
+		stats[i] = domain.ModeStats{ID: modes[i].ID, StartCycle: modes[i].StartCycle, EndCycle: endCycle}
+
+		// Modes are validated before simulation, the traffic flows have already been read successfully.
+		modeTFIDs, _ := modes[i].TrafficFlowIDs()
+		for _, tfID := range modeTFIDs {
+			stats[i].TFStats = append(stats[i].TFStats, r.modeTFStats(tfID, func(generation float64, _ float64, _ bool) bool {
+				return generation >= float64(stats[i].StartCycle) && generation < float64(stats[i].EndCycle)
+			}))
+		}
+	}
+
+	changes := make([]domain.ModeChange, 0, len(modes)-1)
+	for i := 1; i < len(modes); i++ {
+		change := domain.ModeChange{FromID: modes[i-1].ID, ToID: modes[i].ID, Cycle: modes[i].StartCycle}
+
+		for _, tfID := range tfIDs {
+			tfStats := r.modeTFStats(tfID, func(generation float64, received float64, arrived bool) bool {
+				return generation < float64(change.Cycle) && (!arrived || received >= float64(change.Cycle))
+			})
+			if tfStats.PacketsRouted > 0 {
+				change.TFStats = append(change.TFStats, tfStats)
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return stats, changes
+}
+
+// Returns the traffic flow's statistics over its packets matching the filter, which is passed each packet's generation
+// and received cycles and whether it arrived.
+func (r *Records) modeTFStats(tfID string, include func(generation, received float64, arrived bool) bool) domain.ModeTFStats {
+	stats := domain.ModeTFStats{TrafficFlowID: tfID}
+
+	for _, pkt := range r.TransmittedByTF[tfID] {
+		if include(pkt.GenerationCycle, 0, false) {
+			stats.PacketsRouted++
+		}
+	}
+
+	var totalLatency, worstGeneration float64
+	for _, pkt := range r.ArrivedByTF[tfID] {
+		if !include(pkt.GenerationCycle, pkt.ReceivedCycle, true) {
+			continue
+		}
+
+		latency := int(arrivedPacketLatency(pkt))
+		stats.PacketsRouted++
+		stats.PacketsArrived++
+		totalLatency += float64(latency)
+
+		if !arrivedPacketInDeadline(pkt) {
+			stats.PacketsExceededDeadline++
+		}
+		if stats.PacketsArrived == 1 || latency < stats.BestLatency {
+			stats.BestLatency = latency
+		}
+		if stats.PacketsArrived == 1 || latency > stats.WorstLatency ||
+			(latency == stats.WorstLatency && pkt.GenerationCycle < worstGeneration) {
+			stats.WorstLatency = latency
+			stats.WorstPacketID = pkt.Packet.ID()
+			worstGeneration = pkt.GenerationCycle
+		}
+	}
+
+	if stats.PacketsArrived > 0 {
+		stats.MeanLatency = totalLatency / float64(stats.PacketsArrived)
+	}
+
+	return stats
+}
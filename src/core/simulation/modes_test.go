@@ -0,0 +1,109 @@
+package simulation
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeMode(t *testing.T) {
+	t.Parallel()
+
+	conf := domain.SimConfig{MaxPriority: 3}
+	simulator := &simulator{mode: -1, logger: zerolog.New(io.Discard)}
+	for _, id := range []string{"t1", "t2", "t3"} {
+		tf, err := traffic.NewTrafficFlow(domain.TrafficFlowConfig{
+			ID: id, Priority: 1, Period: 10, Deadline: 10, PacketSize: 2, Route: "[n1,n2]",
+		}, conf)
+		require.NoError(t, err)
+		simulator.trafficFlows = append(simulator.trafficFlows, trafficFlowRoute{TrafficFlow: tf})
+	}
+	simulator.modes = []simMode{
+		{ModeConfig: domain.ModeConfig{ID: "m1", StartCycle: 0}, trafficFlows: map[string]bool{"t1": true, "t2": true}},
+		{ModeConfig: domain.ModeConfig{ID: "m2", StartCycle: 25}, trafficFlows: map[string]bool{"t2": true, "t3": true}},
+	}
+
+	active := func() []bool {
+		flags := make([]bool, len(simulator.trafficFlows))
+		for i := 0; i < len(simulator.trafficFlows); i++ {
+			flags[i] = simulator.trafficFlows[i].active
+		}
+		return flags
+	}
+
+	simulator.changeMode(0)
+	assert.Equal(t, 0, simulator.mode)
+	assert.Equal(t, []bool{true, true, false}, active())
+
+	simulator.changeMode(24)
+	assert.Equal(t, 0, simulator.mode)
+
+	simulator.changeMode(25)
+	assert.Equal(t, 1, simulator.mode)
+	assert.Equal(t, []bool{false, true, true}, active())
+	assert.Equal(t, 0, simulator.trafficFlows[1].phase, "continuing traffic flow keeps its release periods")
+	assert.Equal(t, 25, simulator.trafficFlows[2].phase, "joining traffic flow starts a release period on the mode change")
+}
+
+func TestModeStats(t *testing.T) {
+	t.Parallel()
+
+	route := domain.Route{"n0", "n1"}
+	newPkt := func(tfID, index string, deadline int) packet.Packet {
+		return packet.NewPacket(tfID, index, 1, deadline, route, 2, zerolog.New(io.Discard))
+	}
+	arrived := func(pkt packet.Packet, generation, received float64) arrivedPacket {
+		return arrivedPacket{
+			transmittedPacket: transmittedPacket{GenerationCycle: generation, Packet: pkt},
+			ReceivedCycle:     received,
+		}
+	}
+
+	rcrds := newRecords(zerolog.New(io.Discard))
+	rcrds.ArrivedByTF["t1"] = map[string]arrivedPacket{
+		"0": arrived(newPkt("t1", "0", 20), 0, 9),
+		"1": arrived(newPkt("t1", "1", 20), 90, 119),
+	}
+	rcrds.ArrivedByTF["t2"] = map[string]arrivedPacket{
+		"0": arrived(newPkt("t2", "0", 50), 100, 109),
+	}
+	rcrds.TransmittedByTF["t2"] = map[string]transmittedPacket{
+		"1": {GenerationCycle: 190, Packet: newPkt("t2", "1", 50)},
+	}
+
+	modes := []domain.ModeConfig{
+		{ID: "m1", StartCycle: 0, TrafficFlows: "[t1]"},
+		{ID: "m2", StartCycle: 100, TrafficFlows: "[t2]"},
+	}
+
+	stats, changes := rcrds.modeStats(modes, []string{"t1", "t2"}, 200)
+
+	assert.Equal(t, []domain.ModeStats{
+		{ID: "m1", StartCycle: 0, EndCycle: 100, TFStats: []domain.ModeTFStats{{
+			TrafficFlowID: "t1", PacketsRouted: 2, PacketsArrived: 2, PacketsExceededDeadline: 1,
+			BestLatency: 10, MeanLatency: 20, WorstLatency: 30, WorstPacketID: "t1-1",
+		}}},
+		{ID: "m2", StartCycle: 100, EndCycle: 200, TFStats: []domain.ModeTFStats{{
+			TrafficFlowID: "t2", PacketsRouted: 2, PacketsArrived: 1,
+			BestLatency: 10, MeanLatency: 10, WorstLatency: 10, WorstPacketID: "t2-0",
+		}}},
+	}, stats)
+
+	assert.Equal(t, []domain.ModeChange{{FromID: "m1", ToID: "m2", Cycle: 100, TFStats: []domain.ModeTFStats{{
+		TrafficFlowID: "t1", PacketsRouted: 1, PacketsArrived: 1, PacketsExceededDeadline: 1,
+		BestLatency: 30, MeanLatency: 30, WorstLatency: 30, WorstPacketID: "t1-1",
+	}}}}, changes)
+
+	t.Run("NoModes", func(t *testing.T) {
+		stats, changes := rcrds.modeStats(nil, []string{"t1", "t2"}, 200)
+		assert.Nil(t, stats)
+		assert.Nil(t, changes)
+	})
+}
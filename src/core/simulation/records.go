@@ -2,7 +2,9 @@ package simulation
 
 import (
 	"math"
+	"sort"
 
+	"main/src/domain"
 	"main/src/traffic/packet"
 
 	"github.com/rs/zerolog"
@@ -24,6 +26,14 @@ type transmittedPacket struct {
 type arrivedPacket struct {
 	transmittedPacket
 	ReceivedCycle float64
+
+	// Cycles at which the packet's header flit was injected into the network, and its header & tail flits arrived
+	// at the destination network interface.
+	InjectionCycle     float64
+	HeaderArrivalCycle float64
+	TailArrivalCycle   float64
+
+	Hops []domain.HopTimestamps
 }
 
 func newRecords(logger zerolog.Logger) *Records {
@@ -48,6 +58,13 @@ func (r *Records) recordTransmittedPacket(generationCycle, transmissionCycle int
 	r.logger.Trace().Str("packet", pkt.PacketIndex()).Msg("recording transmitted packet")
 }
 
+// Forgets a transmitted packet dropped by its source network interface's injection queue, which is neither routed nor
+// lost.
+func (r *Records) recordDroppedPacket(header packet.Flit) {
+	delete(r.TransmittedByTF[header.TrafficFlowID()], header.PacketIndex())
+	r.logger.Trace().Str("packet", header.PacketIndex()).Msg("recording dropped packet")
+}
+
 func (r *Records) recordArrivedPacket(cycle int, pkt packet.Packet) {
 	if _, exists := r.ArrivedByTF[pkt.TrafficFlowID()]; !exists {
 		r.ArrivedByTF[pkt.TrafficFlowID()] = make(map[string]arrivedPacket)
@@ -58,9 +75,15 @@ func (r *Records) recordArrivedPacket(cycle int, pkt packet.Packet) {
 			r.logger.Error().Err(err).Str("packet", pkt.PacketIndex()).Msg("packet did not match outstanding packet")
 		}
 
+		injection, headerArrival, tailArrival := packetTimestamps(cycle, pkt)
+
 		r.ArrivedByTF[pkt.TrafficFlowID()][pkt.PacketIndex()] = arrivedPacket{
-			transmittedPacket: outstandingPkt,
-			ReceivedCycle:     float64(cycle),
+			transmittedPacket:  outstandingPkt,
+			ReceivedCycle:      float64(cycle),
+			InjectionCycle:     float64(injection),
+			HeaderArrivalCycle: float64(headerArrival),
+			TailArrivalCycle:   float64(tailArrival),
+			Hops:               packetHops(pkt),
 		}
 
 		delete(r.TransmittedByTF[pkt.TrafficFlowID()], pkt.PacketIndex())
@@ -71,6 +94,63 @@ func (r *Records) recordArrivedPacket(cycle int, pkt packet.Packet) {
 	}
 }
 
+// Returns the cycles the packet's header flit was transmitted and header & tail flits arrived, from the flits' recorded
+// events. Missing events default to the arrival cycle.
+func packetTimestamps(cycle int, pkt packet.Packet) (injection, headerArrival, tailArrival int) {
+	injection, headerArrival, tailArrival = cycle, cycle, cycle
+
+	flits := pkt.Flits()
+	if len(flits) == 0 {
+		return injection, headerArrival, tailArrival
+	}
+
+	headerEvents := flits[0].Events()
+	if event, exists := headerEvents.First(packet.FlitTransmitted, ""); exists {
+		injection = event.Cycle
+	}
+	if event, exists := headerEvents.First(packet.FlitArrived, ""); exists {
+		headerArrival = event.Cycle
+	}
+	if event, exists := flits[len(flits)-1].Events().First(packet.FlitArrived, ""); exists {
+		tailArrival = event.Cycle
+	}
+
+	return injection, headerArrival, tailArrival
+}
+
+// Returns the cycles the packet's header and tail flits entered and left each router's input buffer along its route.
+// Returns nil if the flits' events are incomplete.
+func packetHops(pkt packet.Packet) []domain.HopTimestamps {
+	flits := pkt.Flits()
+	if len(flits) < 2 {
+		return nil
+	}
+
+	headerEvents := flits[0].Events()
+	tailEvents := flits[len(flits)-1].Events()
+
+	hops := make([]domain.HopTimestamps, len(pkt.Route()))
+	for i, router := range pkt.Route() {
+		headerIn, inExists := headerEvents.First(packet.FlitBuffered, router)
+		headerOut, outExists := headerEvents.First(packet.FlitForwarded, router)
+		tailIn, tailInExists := tailEvents.First(packet.FlitBuffered, router)
+		tailOut, tailOutExists := tailEvents.First(packet.FlitForwarded, router)
+		if !inExists || !outExists || !tailInExists || !tailOutExists {
+			return nil
+		}
+
+		hops[i] = domain.HopTimestamps{
+			Router:    router,
+			HeaderIn:  headerIn.Cycle,
+			HeaderOut: headerOut.Cycle,
+			TailIn:    tailIn.Cycle,
+			TailOut:   tailOut.Cycle,
+		}
+	}
+
+	return hops
+}
+
 func (r *Records) noTransmitted() int {
 	count := 0
 	for tfID := range r.TransmittedByTF {
@@ -199,6 +279,167 @@ func (r *Records) worstLatencyByTF(tfID string) int {
 	return worstLatency
 }
 
+func (r *Records) latencies() []int {
+	latencies := make([]int, 0, r.noArrived())
+	for tfID := range r.ArrivedByTF {
+		latencies = append(latencies, r.latenciesByTF(tfID)...)
+	}
+
+	sort.Ints(latencies)
+	return latencies
+}
+
+// Returns the latencies of all arrived packets of the traffic flow, sorted in ascending order.
+func (r *Records) latenciesByTF(tfID string) []int {
+	latencies := make([]int, 0, r.noArrivedByTF(tfID))
+	for id := range r.ArrivedByTF[tfID] {
+		latencies = append(latencies, int(arrivedPacketLatency(r.ArrivedByTF[tfID][id])))
+	}
+
+	sort.Ints(latencies)
+	return latencies
+}
+
+func latencyPercentiles(sortedLatencies []int) domain.LatencyPercentiles {
+	return domain.LatencyPercentiles{
+		P50Latency:  latencyPercentile(sortedLatencies, 50),
+		P90Latency:  latencyPercentile(sortedLatencies, 90),
+		P99Latency:  latencyPercentile(sortedLatencies, 99),
+		P999Latency: latencyPercentile(sortedLatencies, 99.9),
+	}
+}
+
+// Nearest-rank percentile of the sorted latencies, returns 0 if there are no latencies.
+func latencyPercentile(sortedLatencies []int, percentile float64) int {
+	if len(sortedLatencies) == 0 {
+		return 0
+	}
+
+	// The epsilon guards against floating point error in fractional percentiles, e.g. 99.9.
+	rank := int(math.Ceil(percentile/100*float64(len(sortedLatencies)) - 1e-9))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sortedLatencies[rank-1]
+}
+
+func latencyHistogram(latencies []int) domain.LatencyHistogram {
+	histogram := make(domain.LatencyHistogram)
+	for i := 0; i < len(latencies); i++ {
+		histogram[latencies[i]]++
+	}
+	return histogram
+}
+
+func (r *Records) latencyDecomposition() domain.LatencyDecomposition {
+	pkts := make([]arrivedPacket, 0, r.noArrived())
+	for tfID := range r.ArrivedByTF {
+		for id := range r.ArrivedByTF[tfID] {
+			pkts = append(pkts, r.ArrivedByTF[tfID][id])
+		}
+	}
+	return latencyDecomposition(pkts)
+}
+
+func (r *Records) latencyDecompositionByTF(tfID string) domain.LatencyDecomposition {
+	pkts := make([]arrivedPacket, 0, r.noArrivedByTF(tfID))
+	for id := range r.ArrivedByTF[tfID] {
+		pkts = append(pkts, r.ArrivedByTF[tfID][id])
+	}
+	return latencyDecomposition(pkts)
+}
+
+func latencyDecomposition(pkts []arrivedPacket) domain.LatencyDecomposition {
+	var decomp domain.LatencyDecomposition
+	if len(pkts) == 0 {
+		return decomp
+	}
+
+	for i := 0; i < len(pkts); i++ {
+		jitter := int(pkts[i].TransmissionCycle - pkts[i].GenerationCycle)
+		queueing := int(pkts[i].InjectionCycle - pkts[i].TransmissionCycle)
+		network := int(pkts[i].HeaderArrivalCycle - pkts[i].InjectionCycle)
+		serialisation := int(pkts[i].TailArrivalCycle - pkts[i].HeaderArrivalCycle)
+
+		decomp.MeanJitterDelay += float64(jitter)
+		decomp.MeanQueueingDelay += float64(queueing)
+		decomp.MeanNetworkDelay += float64(network)
+		decomp.MeanSerialisationDelay += float64(serialisation)
+
+		decomp.MaxJitterDelay = max(decomp.MaxJitterDelay, jitter)
+		decomp.MaxQueueingDelay = max(decomp.MaxQueueingDelay, queueing)
+		decomp.MaxNetworkDelay = max(decomp.MaxNetworkDelay, network)
+		decomp.MaxSerialisationDelay = max(decomp.MaxSerialisationDelay, serialisation)
+	}
+
+	decomp.MeanJitterDelay /= float64(len(pkts))
+	decomp.MeanQueueingDelay /= float64(len(pkts))
+	decomp.MeanNetworkDelay /= float64(len(pkts))
+	decomp.MeanSerialisationDelay /= float64(len(pkts))
+
+	return decomp
+}
+
+// Returns the traffic flow's arrived packets' timelines, ordered by generation cycle.
+func (r *Records) timelinesByTF(tfID string) []domain.PacketTimeline {
+	timelines := make([]domain.PacketTimeline, 0, r.noArrivedByTF(tfID))
+	for _, pkt := range r.ArrivedByTF[tfID] {
+		timelines = append(timelines, arrivedPacketTimeline(pkt))
+	}
+
+	sort.Slice(timelines, func(i, j int) bool {
+		if timelines[i].GenerationCycle == timelines[j].GenerationCycle {
+			return timelines[i].PacketID < timelines[j].PacketID
+		}
+		return timelines[i].GenerationCycle < timelines[j].GenerationCycle
+	})
+	return timelines
+}
+
+func arrivedPacketTimeline(pkt arrivedPacket) domain.PacketTimeline {
+	return domain.PacketTimeline{
+		PacketID:        pkt.Packet.ID(),
+		GenerationCycle: int(pkt.GenerationCycle),
+		ReleaseCycle:    int(pkt.TransmissionCycle),
+		InjectionCycle:  int(pkt.InjectionCycle),
+		ArrivalCycle:    int(pkt.ReceivedCycle),
+		Hops:            pkt.Hops,
+	}
+}
+
+func (r *Records) hopStatsByTF(tfID string) []domain.HopStats {
+	var stats []domain.HopStats
+	var samples []int
+
+	for _, pkt := range r.ArrivedByTF[tfID] {
+		if stats == nil && len(pkt.Hops) > 0 {
+			stats = make([]domain.HopStats, len(pkt.Hops))
+			samples = make([]int, len(pkt.Hops))
+			for i := 0; i < len(pkt.Hops); i++ {
+				stats[i] = domain.HopStats{Hop: i, Router: pkt.Hops[i].Router}
+			}
+		}
+
+		for i := 0; i < len(pkt.Hops) && i < len(stats); i++ {
+			stats[i].MeanHeaderWait += float64(pkt.Hops[i].HeaderWait())
+			stats[i].MeanTailWait += float64(pkt.Hops[i].TailWait())
+			stats[i].MaxHeaderWait = max(stats[i].MaxHeaderWait, pkt.Hops[i].HeaderWait())
+			stats[i].MaxTailWait = max(stats[i].MaxTailWait, pkt.Hops[i].TailWait())
+			samples[i]++
+		}
+	}
+
+	for i := 0; i < len(stats); i++ {
+		if samples[i] > 0 {
+			stats[i].MeanHeaderWait /= float64(samples[i])
+			stats[i].MeanTailWait /= float64(samples[i])
+		}
+	}
+
+	return stats
+}
+
 func arrivedPacketLatency(pkt arrivedPacket) float64 {
 	return math.Round(pkt.ReceivedCycle - pkt.GenerationCycle + 1)
 }
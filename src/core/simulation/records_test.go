@@ -0,0 +1,140 @@
+package simulation
+
+import (
+	"io"
+	"testing"
+
+	"main/src/domain"
+	"main/src/traffic/packet"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyPercentile(t *testing.T) {
+	t.Parallel()
+
+	latencies := make([]int, 1000)
+	for i := 0; i < len(latencies); i++ {
+		latencies[i] = i + 1
+	}
+
+	testCases := map[string]struct {
+		latencies  []int
+		percentile float64
+		expected   int
+	}{
+		"Empty":     {latencies: []int{}, percentile: 50, expected: 0},
+		"Single":    {latencies: []int{7}, percentile: 99.9, expected: 7},
+		"Median":    {latencies: []int{1, 2, 3, 4}, percentile: 50, expected: 2},
+		"Maximum":   {latencies: []int{1, 2, 3, 4}, percentile: 100, expected: 4},
+		"Zeroth":    {latencies: []int{1, 2, 3, 4}, percentile: 0, expected: 1},
+		"P90":       {latencies: latencies, percentile: 90, expected: 900},
+		"P99":       {latencies: latencies, percentile: 99, expected: 990},
+		"P999":      {latencies: latencies, percentile: 99.9, expected: 999},
+		"NearestUp": {latencies: []int{10, 20, 30}, percentile: 50, expected: 20},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, latencyPercentile(tc.latencies, tc.percentile))
+		})
+	}
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	t.Parallel()
+
+	latencies := make([]int, 1000)
+	for i := 0; i < len(latencies); i++ {
+		latencies[i] = i + 1
+	}
+
+	assert.Equal(t, domain.LatencyPercentiles{
+		P50Latency:  500,
+		P90Latency:  900,
+		P99Latency:  990,
+		P999Latency: 999,
+	}, latencyPercentiles(latencies))
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, domain.LatencyHistogram{3: 2, 5: 1, 9: 3}, latencyHistogram([]int{3, 3, 5, 9, 9, 9}))
+	assert.Empty(t, latencyHistogram([]int{}))
+}
+
+func TestLatencyDecomposition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Equal(t, domain.LatencyDecomposition{}, latencyDecomposition([]arrivedPacket{}))
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		pkts := []arrivedPacket{
+			{
+				transmittedPacket:  transmittedPacket{GenerationCycle: 0, TransmissionCycle: 2},
+				InjectionCycle:     3,
+				HeaderArrivalCycle: 7,
+				TailArrivalCycle:   10,
+			},
+			{
+				transmittedPacket:  transmittedPacket{GenerationCycle: 100, TransmissionCycle: 100},
+				InjectionCycle:     105,
+				HeaderArrivalCycle: 111,
+				TailArrivalCycle:   114,
+			},
+		}
+
+		assert.Equal(t, domain.LatencyDecomposition{
+			MeanJitterDelay:        1,
+			MaxJitterDelay:         2,
+			MeanQueueingDelay:      3,
+			MaxQueueingDelay:       5,
+			MeanNetworkDelay:       5,
+			MaxNetworkDelay:        6,
+			MeanSerialisationDelay: 3,
+			MaxSerialisationDelay:  3,
+		}, latencyDecomposition(pkts))
+	})
+}
+
+func TestPacketHops(t *testing.T) {
+	t.Parallel()
+
+	route := domain.Route{"n0", "n1"}
+
+	t.Run("Valid", func(t *testing.T) {
+		pkt := packet.NewPacket("t", "AA", 1, 100, route, 3, zerolog.New(io.Discard))
+		flits := pkt.Flits()
+		header, tail := flits[0], flits[len(flits)-1]
+
+		header.RecordEvent(1, packet.FlitBuffered, "n0")
+		header.RecordEvent(3, packet.FlitForwarded, "n0")
+		header.RecordEvent(3, packet.FlitBuffered, "n1")
+		header.RecordEvent(4, packet.FlitForwarded, "n1")
+		tail.RecordEvent(3, packet.FlitBuffered, "n0")
+		tail.RecordEvent(5, packet.FlitForwarded, "n0")
+		tail.RecordEvent(5, packet.FlitBuffered, "n1")
+		tail.RecordEvent(9, packet.FlitForwarded, "n1")
+
+		hops := packetHops(pkt)
+		require.Len(t, hops, 2)
+		assert.Equal(t, domain.HopTimestamps{Router: "n0", HeaderIn: 1, HeaderOut: 3, TailIn: 3, TailOut: 5}, hops[0])
+		assert.Equal(t, domain.HopTimestamps{Router: "n1", HeaderIn: 3, HeaderOut: 4, TailIn: 5, TailOut: 9}, hops[1])
+		assert.Equal(t, 2, hops[0].HeaderWait())
+		assert.Equal(t, 4, hops[1].TailWait())
+	})
+
+	t.Run("MissingEvents", func(t *testing.T) {
+		pkt := packet.NewPacket("t", "AA", 1, 100, route, 3, zerolog.New(io.Discard))
+		pkt.Flits()[0].RecordEvent(1, packet.FlitBuffered, "n0")
+
+		assert.Nil(t, packetHops(pkt))
+	})
+}
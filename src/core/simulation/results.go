@@ -7,7 +7,21 @@ import (
 	"main/src/traffic"
 )
 
-func simResults(cycles int, dur time.Duration, rcrds *Records, trafficFlows []traffic.TrafficFlow) domain.SimResults {
+func simResults(cycles int, dur time.Duration, rcrds *Records, netStats domain.NetworkStats, traversals map[domain.Link][]domain.LinkTraversal, faultLog domain.FaultLog, modes []domain.ModeConfig, trafficFlows []traffic.TrafficFlow) domain.SimResults {
+	latencies := rcrds.latencies()
+	faultReport, faultStats := rcrds.faultReport(faultLog)
+
+	tfIDs := make([]string, len(trafficFlows))
+	for i := 0; i < len(trafficFlows); i++ {
+		tfIDs[i] = trafficFlows[i].ID()
+	}
+	modeStats, modeChanges := rcrds.modeStats(modes, tfIDs, cycles)
+
+	priorities := make(map[string]int, len(trafficFlows))
+	for i := 0; i < len(trafficFlows); i++ {
+		priorities[trafficFlows[i].ID()] = trafficFlows[i].Priority()
+	}
+
 	results := domain.SimResults{
 		SimHeadlineResults: domain.SimHeadlineResults{
 			Cycles:   cycles,
@@ -20,12 +34,24 @@ func simResults(cycles int, dur time.Duration, rcrds *Records, trafficFlows []tr
 				BestLatency:             rcrds.bestLatency(),
 				MeanLatency:             rcrds.meanLatency(),
 				WorstLatency:            rcrds.worstLatency(),
+				LatencyPercentiles:      latencyPercentiles(latencies),
+				LatencyDecomposition:    rcrds.latencyDecomposition(),
+				LatencyHistogram:        latencyHistogram(latencies),
 			},
 		},
-		TFStats: make(map[string]domain.StatSet, len(trafficFlows)),
+		TFStats:        make(map[string]domain.StatSet, len(trafficFlows)),
+		TFHopStats:     make(map[string][]domain.HopStats, len(trafficFlows)),
+		TFTimelines:    make(map[string][]domain.PacketTimeline, len(trafficFlows)),
+		NetworkStats:   netStats,
+		DeadlineMisses: rcrds.deadlineMisses(traversals),
+		FaultReport:    faultReport,
+		Modes:          modeStats,
+		ModeChanges:    modeChanges,
 	}
 
 	for i := 0; i < len(trafficFlows); i++ {
+		tfLatencies := rcrds.latenciesByTF(trafficFlows[i].ID())
+
 		results.TFStats[trafficFlows[i].ID()] = domain.StatSet{
 			PacketsRouted:           rcrds.noTransmittedByTF(trafficFlows[i].ID()),
 			PacketsArrived:          rcrds.noArrivedByTF(trafficFlows[i].ID()),
@@ -34,8 +60,21 @@ func simResults(cycles int, dur time.Duration, rcrds *Records, trafficFlows []tr
 			BestLatency:             rcrds.bestLatencyByTF(trafficFlows[i].ID()),
 			MeanLatency:             rcrds.meanLatencyByTF(trafficFlows[i].ID()),
 			WorstLatency:            rcrds.worstLatencyByTF(trafficFlows[i].ID()),
+			LatencyPercentiles:      latencyPercentiles(tfLatencies),
+			LatencyDecomposition:    rcrds.latencyDecompositionByTF(trafficFlows[i].ID()),
+			FaultStats:              faultStats[trafficFlows[i].ID()],
+			FairnessStats:           rcrds.fairnessStatsByTF(trafficFlows[i].ID(), cycles),
+			InjectionQueueStats:     netStats.InjectionQueues[trafficFlows[i].ID()],
+			LatencyHistogram:        latencyHistogram(tfLatencies),
+			ObservedInterference:    observedInterference(netStats.Interference, trafficFlows[i].ID()),
+			LowerPriorityBlocking:   lowerPriorityBlocking(netStats.Interference, trafficFlows[i].ID(), priorities),
 		}
+
+		results.TFHopStats[trafficFlows[i].ID()] = rcrds.hopStatsByTF(trafficFlows[i].ID())
+		results.TFTimelines[trafficFlows[i].ID()] = rcrds.timelinesByTF(trafficFlows[i].ID())
 	}
 
+	results.SimHeadlineResults.FairnessIndices = fairnessIndices(results.TFStats)
+
 	return results
 }
@@ -15,49 +15,78 @@ import (
 const (
 	simProgressMultiple = 0.05
 	maxProgressInterval = 100000
+
+	// Minimum wall-clock interval between progress reports.
+	progressReportInterval = 250 * time.Millisecond
 )
 
+// Receives periodic snapshots of the simulation's progress.
+type ProgressFunc func(domain.SimProgress)
+
 type simulator struct {
 	network      network.Network
 	trafficFlows []trafficFlowRoute
 	cycleLimit   int
 
+	// Modes ordered by start cycle and the index of the active mode, -1 before the first mode starts. All traffic flows
+	// are active if there are no modes.
+	modes []simMode
+	mode  int
+
 	rcrds *Records
 
+	progress ProgressFunc
+
 	logger zerolog.Logger
 }
 
 type trafficFlowRoute struct {
 	traffic.TrafficFlow
 	route domain.Route
+
+	active bool
+	// Cycle the traffic flow's release periods are aligned to, the cycle its mode started when it was activated, shifted
+	// by the cycles its releases were blocked.
+	phase int
 }
 
-func Simulate(ctx context.Context, network network.Network, trafficFlows []traffic.TrafficFlow, cycleLimit int, logger zerolog.Logger) (domain.SimResults, error) {
+type simMode struct {
+	domain.ModeConfig
+	trafficFlows map[string]bool
+}
+
+func Simulate(ctx context.Context, network network.Network, trafficFlows []traffic.TrafficFlow, modes []domain.ModeConfig, cycleLimit int, progress ProgressFunc, logger zerolog.Logger) (domain.SimResults, error) {
 	select {
 	case <-ctx.Done():
 		return domain.SimResults{}, ctx.Err()
 	default:
-		simulator, err := newSimulator(network, trafficFlows, cycleLimit, logger)
+		simulator, err := newSimulator(network, trafficFlows, modes, cycleLimit, logger)
 		if err != nil {
 			logger.Error().Err(nil).Msg("error creating simulator")
 			return domain.SimResults{}, err
 		}
+		simulator.progress = progress
 
-		simDuration, rcrds, err := simulator.runSimulation(ctx)
+		cycles, simDuration, rcrds, err := simulator.runSimulation(ctx)
 		if err != nil {
 			logger.Error().Err(err).Msg("error running simulation")
 			return domain.SimResults{}, err
 		}
 
-		return simResults(cycleLimit, simDuration, rcrds, trafficFlows), nil
+		results := simResults(cycles, simDuration, rcrds, network.Stats(cycles), network.LinkTraversals(), network.FaultLog(), modes, trafficFlows)
+		results.SimHeadlineResults.CycleLimit = cycleLimit
+		results.SimHeadlineResults.Truncated = cycles < cycleLimit
+
+		return results, nil
 	}
 }
 
-func newSimulator(network network.Network, trafficFlows []traffic.TrafficFlow, cycleLimit int, logger zerolog.Logger) (*simulator, error) {
+func newSimulator(network network.Network, trafficFlows []traffic.TrafficFlow, modes []domain.ModeConfig, cycleLimit int, logger zerolog.Logger) (*simulator, error) {
 	simulator := &simulator{
 		network:      network,
 		cycleLimit:   cycleLimit,
 		trafficFlows: make([]trafficFlowRoute, len(trafficFlows)),
+		mode:         -1,
 
 		rcrds: newRecords(logger),
 
@@ -74,40 +103,74 @@ func newSimulator(network network.Network, trafficFlows []traffic.TrafficFlow, c
 			return nil, err
 		}
 
+		for _, netwrkIntfcID := range []string{trafficFlows[i].Source(), trafficFlows[i].Destination()} {
+			if _, exists := network.NetworkInterfaceMap()[netwrkIntfcID]; !exists {
+				logger.Error().Err(domain.ErrMissingNetworkInterface).Str("traffic_flow", trafficFlows[i].ID()).Str("network_interface", netwrkIntfcID).Msg("traffic flow endpoint network interface not found")
+				return nil, domain.ErrMissingNetworkInterface
+			}
+		}
+
 		simulator.trafficFlows[i] = trafficFlowRoute{
 			TrafficFlow: trafficFlows[i],
 			route:       route,
+			active:      len(modes) == 0,
+		}
+	}
+
+	for i := 0; i < len(modes); i++ {
+		ids, err := modes[i].TrafficFlowIDs()
+		if err != nil {
+			logger.Error().Err(err).Str("mode", modes[i].ID).Msg("error reading mode traffic flows")
+			return nil, err
+		}
+
+		mode := simMode{ModeConfig: modes[i], trafficFlows: make(map[string]bool, len(ids))}
+		for _, id := range ids {
+			mode.trafficFlows[id] = true
 		}
+		simulator.modes = append(simulator.modes, mode)
 	}
 
 	return simulator, nil
 }
 
-func (s *simulator) runSimulation(ctx context.Context) (time.Duration, *Records, error) {
+// Simulates up to the cycle limit, returning the number of cycles simulated. If the context is cancelled the cycle in
+// progress completes and the records of the cycles simulated so far are returned.
+func (s *simulator) runSimulation(ctx context.Context) (int, time.Duration, *Records, error) {
 	logProgressInterval := int(math.Round(float64(s.cycleLimit) * simProgressMultiple))
 	if logProgressInterval > maxProgressInterval {
 		logProgressInterval = maxProgressInterval
 	}
+	if logProgressInterval < 1 {
+		logProgressInterval = 1
+	}
 
 	s.logger.Info().Msg("starting simulation")
 
 	start := time.Now()
+	lastReport := start
+	cycles := s.cycleLimit
 
+simLoop:
 	for c := 0; c < s.cycleLimit; c++ {
 		select {
 		case <-ctx.Done():
-			return 0, nil, ctx.Err()
+			cycles = c
+			s.logger.Warn().Err(ctx.Err()).Int("cycle", c).Int("limit", s.cycleLimit).Msg("simulation interrupted, returning partial results")
+			break simLoop
 		default:
 			s.logger.Trace().Int("cycle", c).Msg("starting cycle")
 
+			s.changeMode(c)
+
 			if err := s.releasePackets(c); err != nil {
 				s.logger.Error().Err(err).Msg("error releasing packets")
-				return 0, nil, err
+				return 0, 0, nil, err
 			}
 
 			if err := s.network.Cycle(c); err != nil {
 				s.logger.Error().Err(err).Msg("error cycling network")
-				return 0, nil, err
+				return 0, 0, nil, err
 			}
 
 			for i := 0; i < len(s.network.NetworkInterfaces()); i++ {
@@ -121,30 +184,87 @@ func (s *simulator) runSimulation(ctx context.Context) (time.Duration, *Records,
 				s.logger.Info().Int("cycle", c).Int("limit", s.cycleLimit).Msg("simulation progress")
 			}
 
+			if s.progress != nil {
+				if now := time.Now(); now.Sub(lastReport) >= progressReportInterval {
+					lastReport = now
+					s.reportProgress(c+1, now.Sub(start), false)
+				}
+			}
+
 			s.logger.Debug().Int("cycle", c).Msg("cycle completed")
 		}
 	}
 
 	simDuration := time.Since(start)
 
-	s.logger.Info().Dur("duration_ms", simDuration).Msg("simulation complete")
-	return simDuration, s.rcrds, nil
+	if s.progress != nil {
+		s.reportProgress(cycles, simDuration, true)
+	}
+
+	s.logger.Info().Dur("duration_ms", simDuration).Int("cycles", cycles).Msg("simulation complete")
+	return cycles, simDuration, s.rcrds, nil
+}
+
+func (s *simulator) reportProgress(cycle int, elapsed time.Duration, done bool) {
+	s.progress(domain.SimProgress{
+		Cycle:          cycle,
+		CycleLimit:     s.cycleLimit,
+		Elapsed:        elapsed,
+		PacketsArrived: s.rcrds.noArrived(),
+		Done:           done,
+	})
+}
+
+// Switches to the next mode if it starts on the cycle. Traffic flows leaving the mode stop releasing packets, their
+// in-flight packets still complete, and traffic flows joining the mode start a new release period on the cycle.
+func (s *simulator) changeMode(cycle int) {
+	next := s.mode + 1
+	if next >= len(s.modes) || s.modes[next].StartCycle != cycle {
+		return
+	}
+	s.mode = next
+
+	for i := 0; i < len(s.trafficFlows); i++ {
+		active := s.modes[next].trafficFlows[s.trafficFlows[i].ID()]
+		if active && !s.trafficFlows[i].active {
+			s.trafficFlows[i].phase = cycle
+		}
+		s.trafficFlows[i].active = active
+	}
+
+	s.logger.Info().Int("cycle", cycle).Str("mode", s.modes[next].ID).Msg("mode change")
 }
 
 func (s *simulator) releasePackets(cycle int) error {
 	for i := 0; i < len(s.trafficFlows); i++ {
-		released, pkt, periodStartCycle := s.trafficFlows[i].ReleasePacket(cycle, s.trafficFlows[i].TrafficFlow, s.trafficFlows[i].route, s.logger)
+		if !s.trafficFlows[i].active {
+			continue
+		}
+
+		// A traffic flow with a packet blocked by its source's injection queue releases nothing until the packet is queued,
+		// shifting its later releases by the cycles it waited.
+		if netwrkIntfc, exists := s.network.NetworkInterfaceMap()[s.trafficFlows[i].Source()]; exists && netwrkIntfc.TrafficFlowBlocked(s.trafficFlows[i].ID()) {
+			s.trafficFlows[i].phase++
+			continue
+		}
+
+		released, pkt, periodStartCycle := s.trafficFlows[i].ReleasePacket(cycle-s.trafficFlows[i].phase, s.trafficFlows[i].TrafficFlow, s.trafficFlows[i].route, s.logger)
+		periodStartCycle += s.trafficFlows[i].phase
 
 		if released {
-			if netwrkIntfc, exists := s.network.NetworkInterfaceMap()[pkt.Route()[0]]; exists {
+			pkt.SetGenerationCycle(periodStartCycle)
+			if netwrkIntfc, exists := s.network.NetworkInterfaceMap()[pkt.Source()]; exists {
 				if err := netwrkIntfc.RoutePacket(cycle, pkt); err != nil {
 					s.logger.Error().Err(err).Msg("failed to route packet")
 					return err
 				}
 
 				s.rcrds.recordTransmittedPacket(periodStartCycle, cycle, pkt)
+				for _, header := range netwrkIntfc.PopDroppedPackets(cycle) {
+					s.rcrds.recordDroppedPacket(header)
+				}
 			} else {
-				s.logger.Error().Err(domain.ErrMissingNetworkInterface).Str("network_interface", pkt.Route()[0]).Msg("network interface not found")
+				s.logger.Error().Err(domain.ErrMissingNetworkInterface).Str("network_interface", pkt.Source()).Msg("network interface not found")
 				return domain.ErrMissingNetworkInterface
 			}
 		}
@@ -456,7 +456,7 @@ func BenchmarkNewSimulator(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				_, err := newSimulator(network, trafficFlows, testCase.cycles, zerolog.New(io.Discard))
+				_, err := newSimulator(network, trafficFlows, nil, testCase.cycles, zerolog.New(io.Discard))
 				require.NoError(b, err)
 			}
 		})
@@ -531,10 +531,10 @@ func TestRunSimulation(t *testing.T) {
 				trafficFlows[i] = tf
 			}
 
-			simulator, err := newSimulator(network, trafficFlows, testCase.cycles, zerolog.New(io.Discard))
+			simulator, err := newSimulator(network, trafficFlows, nil, testCase.cycles, zerolog.New(io.Discard))
 			require.NoError(t, err)
 
-			_, records, err := simulator.runSimulation(context.Background())
+			_, _, records, err := simulator.runSimulation(context.Background())
 			require.NoError(t, err)
 
 			// Check Expected Packets
@@ -597,16 +597,247 @@ func BenchmarkRunSimulation(b *testing.B) {
 				trafficFlows[i] = tf
 			}
 
-			simulator, err := newSimulator(network, trafficFlows, testCase.cycles, zerolog.New(io.Discard))
+			simulator, err := newSimulator(network, trafficFlows, nil, testCase.cycles, zerolog.New(io.Discard))
 			require.NoError(b, err)
 
 			b.ReportAllocs()
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				_, _, err := simulator.runSimulation(context.Background())
+				_, _, _, err := simulator.runSimulation(context.Background())
 				require.NoError(b, err)
 			}
 		})
 	}
 }
+
+// Context which is done once Done has been called more than limit times, interrupting the simulation at a
+// deterministic cycle.
+type countdownContext struct {
+	context.Context
+	calls int
+	limit int
+	done  chan struct{}
+}
+
+func (c *countdownContext) Done() <-chan struct{} {
+	c.calls++
+	if c.calls > c.limit {
+		return c.done
+	}
+	return nil
+}
+
+func TestSimulateInterrupted(t *testing.T) {
+	t.Parallel()
+
+	testCase := templateTestCases["3hLineOnePkt"]
+
+	network, err := network.NewNetwork(testCase.topologyFunc(t), testCase.networkConf, zerolog.New(io.Discard))
+	require.NoError(t, err)
+
+	tf, err := traffic.NewTrafficFlow(testCase.traffic[0], testCase.networkConf)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	close(done)
+	// Simulate checks the context once before starting, then once per cycle.
+	ctx := &countdownContext{Context: context.Background(), limit: 21, done: done}
+
+	results, err := Simulate(ctx, network, []traffic.TrafficFlow{tf}, nil, testCase.cycles, nil, zerolog.New(io.Discard))
+	require.NoError(t, err)
+
+	assert.True(t, results.SimHeadlineResults.Truncated)
+	assert.Equal(t, 20, results.SimHeadlineResults.Cycles)
+	assert.Equal(t, testCase.cycles, results.SimHeadlineResults.CycleLimit)
+	assert.Equal(t, 1, results.TFStats["t1"].PacketsArrived)
+}
+
+func TestSimulateProgress(t *testing.T) {
+	t.Parallel()
+
+	testCase := templateTestCases["3hLineOnePkt"]
+
+	network, err := network.NewNetwork(testCase.topologyFunc(t), testCase.networkConf, zerolog.New(io.Discard))
+	require.NoError(t, err)
+
+	tf, err := traffic.NewTrafficFlow(testCase.traffic[0], testCase.networkConf)
+	require.NoError(t, err)
+
+	var snapshots []domain.SimProgress
+	progress := func(p domain.SimProgress) { snapshots = append(snapshots, p) }
+
+	_, err = Simulate(context.Background(), network, []traffic.TrafficFlow{tf}, nil, testCase.cycles, progress, zerolog.New(io.Discard))
+	require.NoError(t, err)
+
+	require.NotEmpty(t, snapshots)
+	final := snapshots[len(snapshots)-1]
+	assert.True(t, final.Done)
+	assert.Equal(t, testCase.cycles, final.Cycle)
+	assert.Equal(t, testCase.cycles, final.CycleLimit)
+	assert.Equal(t, testCase.cycles/testCase.traffic[0].Period, final.PacketsArrived)
+}
+
+func TestSimulateSharedVirtualChannels(t *testing.T) {
+	t.Parallel()
+
+	trafficConfs := []domain.TrafficFlowConfig{
+		{ID: "t1", Priority: 1, Period: 40, Deadline: 40, PacketSize: 4, Route: "[n0,n1,n2]"},
+		{ID: "t2", Priority: 2, Period: 50, Deadline: 50, PacketSize: 8, Route: "[n0,n1,n2,n5,n8]"},
+		{ID: "t3", Priority: 3, Period: 45, Deadline: 45, PacketSize: 6, Route: "[n3,n4,n5]"},
+		{ID: "t4", Priority: 4, Period: 60, Deadline: 60, PacketSize: 10, Route: "[n1,n4,n7]"},
+		{ID: "t5", Priority: 5, Period: 55, Deadline: 55, PacketSize: 8, Route: "[n6,n7,n8,n5,n2]"},
+		{ID: "t6", Priority: 6, Period: 70, Deadline: 70, PacketSize: 10, Route: "[n2,n1,n0,n3,n6]"},
+		{ID: "t7", Priority: 7, Period: 65, Deadline: 65, PacketSize: 6, Route: "[n3,n4,n5,n8]"},
+		{ID: "t8", Priority: 8, Period: 80, Deadline: 80, PacketSize: 12, Route: "[n0,n1,n2,n5]"},
+	}
+
+	testCases := map[string]domain.SimConfig{
+		"Bands": {
+			MaxPriority:     8,
+			NumVCs:          2,
+			BufferSize:      8,
+			ProcessingDelay: 2,
+		},
+		"Map": {
+			MaxPriority:     8,
+			NumVCs:          3,
+			VCMap:           []int{1, 2, 3, 1, 2, 3, 1, 2},
+			BufferSize:      9,
+			ProcessingDelay: 2,
+		},
+		"SingleVirtualChannel": {
+			MaxPriority:     8,
+			NumVCs:          1,
+			BufferSize:      4,
+			ProcessingDelay: 2,
+		},
+	}
+
+	const cycles = 5600
+
+	for name, conf := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			network, err := network.NewNetwork(topology.ThreeByThreeMesh(t), conf, zerolog.New(io.Discard))
+			require.NoError(t, err)
+
+			trafficFlows := make([]traffic.TrafficFlow, len(trafficConfs))
+			for i := range trafficConfs {
+				trafficFlows[i], err = traffic.NewTrafficFlow(trafficConfs[i], conf)
+				require.NoError(t, err)
+			}
+
+			results, err := Simulate(context.Background(), network, trafficFlows, nil, cycles, nil, zerolog.New(io.Discard))
+			require.NoError(t, err)
+
+			// Every packet released, but for those still in flight when the simulation stops, arrives intact.
+			for _, tfConf := range trafficConfs {
+				stats := results.TFStats[tfConf.ID]
+				assert.GreaterOrEqual(t, stats.PacketsArrived, cycles/tfConf.Period-1, tfConf.ID)
+				assert.LessOrEqual(t, stats.PacketsRouted-stats.PacketsArrived, 1, tfConf.ID)
+			}
+		})
+	}
+}
+
+func TestSimulateOnOffFlowControlWideLinks(t *testing.T) {
+	t.Parallel()
+
+	// Traffic flows converging on the same links and virtual channels, so wide links carry several flits into a
+	// virtual channel in the cycle it signals off.
+	trafficConfs := []domain.TrafficFlowConfig{
+		{ID: "t1", Priority: 1, Period: 12, Deadline: 12, PacketSize: 4, Route: "[n0,n1,n2,n5]"},
+		{ID: "t2", Priority: 2, Period: 14, Deadline: 14, PacketSize: 6, Route: "[n4,n1,n2,n5]"},
+		{ID: "t3", Priority: 3, Period: 16, Deadline: 16, PacketSize: 4, Route: "[n8,n5,n2,n1]"},
+		{ID: "t4", Priority: 4, Period: 18, Deadline: 18, PacketSize: 6, Route: "[n3,n4,n5,n2]"},
+		{ID: "t5", Priority: 5, Period: 20, Deadline: 20, PacketSize: 8, Route: "[n6,n3,n4,n5]"},
+		{ID: "t6", Priority: 6, Period: 22, Deadline: 22, PacketSize: 4, Route: "[n7,n4,n5,n2]"},
+	}
+
+	conf := domain.SimConfig{
+		MaxPriority:     6,
+		NumVCs:          2,
+		BufferSize:      4,
+		ProcessingDelay: 1,
+		LinkBandwidth:   2,
+		FlowControl:     domain.OnOffFlowControl,
+	}
+
+	network, err := network.NewNetwork(topology.ThreeByThreeMesh(t), conf, zerolog.New(io.Discard))
+	require.NoError(t, err)
+
+	trafficFlows := make([]traffic.TrafficFlow, len(trafficConfs))
+	for i := range trafficConfs {
+		trafficFlows[i], err = traffic.NewTrafficFlow(trafficConfs[i], conf)
+		require.NoError(t, err)
+	}
+
+	results, err := Simulate(context.Background(), network, trafficFlows, nil, 2000, nil, zerolog.New(io.Discard))
+	require.NoError(t, err)
+
+	for _, tfConf := range trafficConfs {
+		assert.Positive(t, results.TFStats[tfConf.ID].PacketsArrived, tfConf.ID)
+	}
+}
+
+func TestSimulateInjectionQueueDrops(t *testing.T) {
+	t.Parallel()
+
+	// Traffic flows sharing a source network interface release more flits than its injection queue holds.
+	trafficConfs := []domain.TrafficFlowConfig{
+		{ID: "t1", Priority: 1, Period: 6, Deadline: 6, PacketSize: 4, Route: "[n0,n1,n2]"},
+		{ID: "t2", Priority: 2, Period: 6, Deadline: 6, PacketSize: 4, Route: "[n0,n1,n2]"},
+	}
+
+	for _, policy := range domain.InjectionQueuePolicies() {
+		t.Run(string(policy), func(t *testing.T) {
+			t.Parallel()
+
+			conf := domain.SimConfig{
+				MaxPriority:          2,
+				BufferSize:           8,
+				ProcessingDelay:      1,
+				InjectionQueueDepth:  4,
+				InjectionQueuePolicy: policy,
+			}
+
+			network, err := network.NewNetwork(topology.ThreeNodeLine(t), conf, zerolog.New(io.Discard))
+			require.NoError(t, err)
+
+			trafficFlows := make([]traffic.TrafficFlow, len(trafficConfs))
+			for i := range trafficConfs {
+				trafficFlows[i], err = traffic.NewTrafficFlow(trafficConfs[i], conf)
+				require.NoError(t, err)
+			}
+
+			const cycles = 600
+			results, err := Simulate(context.Background(), network, trafficFlows, nil, cycles, nil, zerolog.New(io.Discard))
+			require.NoError(t, err)
+
+			// Packets dropped by the injection queue are neither routed nor lost, at most a packet per traffic flow is
+			// still in flight, or queued, when the simulation stops. Blocking drops nothing, instead shifting the
+			// traffic flows' later releases so they release fewer packets.
+			dropped := 0
+			for _, tfConf := range trafficConfs {
+				stats := results.TFStats[tfConf.ID]
+				dropped += stats.InjectionQueueStats.PacketsDropped
+				assert.LessOrEqual(t, stats.PacketsLost, 2, tfConf.ID)
+
+				if policy == domain.BlockInjectionQueuePolicy {
+					assert.Positive(t, stats.PacketsRouted, tfConf.ID)
+					assert.Less(t, stats.PacketsRouted, cycles/tfConf.Period, tfConf.ID)
+					continue
+				}
+				assert.GreaterOrEqual(t, stats.PacketsRouted+stats.InjectionQueueStats.PacketsDropped, cycles/tfConf.Period, tfConf.ID)
+			}
+
+			if policy == domain.BlockInjectionQueuePolicy {
+				assert.Zero(t, dropped)
+				return
+			}
+			assert.Positive(t, dropped)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+type ArbitrationPolicy string
+
+const (
+	// Highest priority head flit first, ties in input port then virtual channel order.
+	FixedPriorityArbitration ArbitrationPolicy = "fixed_priority"
+	// Input port virtual channels take turns, starting after the last virtual channel granted.
+	RoundRobinArbitration ArbitrationPolicy = "round_robin"
+	// Oldest packet, by the cycle it was released at its source network interface, first.
+	AgeArbitration ArbitrationPolicy = "age"
+	// Earliest absolute deadline, the cycle the packet's release period started plus its header flit's deadline, first.
+	EDFArbitration ArbitrationPolicy = "edf"
+	// Round robin in which each priority is granted up to its weight in flits before lower weighted priorities are
+	// served again.
+	WeightedRoundRobinArbitration ArbitrationPolicy = "weighted_round_robin"
+)
+
+// Returns an array of all valid router arbitration policies.
+func ArbitrationPolicies() []ArbitrationPolicy {
+	return []ArbitrationPolicy{
+		FixedPriorityArbitration,
+		RoundRobinArbitration,
+		AgeArbitration,
+		EDFArbitration,
+		WeightedRoundRobinArbitration,
+	}
+}
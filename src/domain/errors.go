@@ -31,7 +31,16 @@ var (
 	ErrFlitAlreadySet = errors.New("header or tail flit already set")
 	ErrFlitUnset      = errors.New("header or tail flit not set")
 
+	ErrIncompletePacket = errors.New("packet is missing flits")
+	ErrCorruptPacket    = errors.New("packet contains corrupted flits")
+
 	ErrMissingTrafficFlow = errors.New("missing traffic flow")
 
 	ErrInvalidRoute = errors.New("invalid route")
+
+	ErrInvalidFault = errors.New("invalid fault")
+
+	ErrInvalidMode = errors.New("invalid mode")
+
+	ErrUnsupportedAnalysis = errors.New("analysis does not support the configuration")
 )
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+type FaultType string
+
+const (
+	// Takes the link from src to dst down, no flits are sent over it while the fault is active.
+	LinkDownFault FaultType = "link_down"
+	// Stalls the src router, no buffered flits are routed while the fault is active.
+	RouterStallFault FaultType = "router_stall"
+	// Drops the flit when it is received over a matching link, a dropped header flit drops its packet's remaining
+	// flits on the same link.
+	FlitDropFault FaultType = "flit_drop"
+	// Corrupts the flit when it is received over a matching link, the destination network interface discards the
+	// corrupted flit's packet.
+	FlitCorruptFault FaultType = "flit_corrupt"
+)
+
+// A fault schedule entry. Faults are active from the start to the end cycle inclusive, an end cycle of 0 leaves the
+// fault active until the end of the simulation. Links are identified by their src and dst components, LocalPort
+// identifying a router's first network interface and a network interface's ID identifying any other. Flit faults match
+// any link if src and dst are empty, and are applied at most once.
+type FaultConfig struct {
+	Type       FaultType `csv:"type"`
+	StartCycle int       `csv:"start_cycle"`
+	EndCycle   int       `csv:"end_cycle"`
+	Src        string    `csv:"src"`
+	Dst        string    `csv:"dst"`
+	FlitID     string    `csv:"flit_id"`
+}
+
+func (f FaultConfig) Validate() error {
+	if f.StartCycle < 0 || (f.EndCycle != 0 && f.EndCycle < f.StartCycle) {
+		return errors.Join(ErrInvalidFault, fmt.Errorf("invalid cycle range %d to %d", f.StartCycle, f.EndCycle))
+	}
+
+	switch f.Type {
+	case LinkDownFault:
+		if f.Src == "" || f.Dst == "" {
+			return errors.Join(ErrInvalidFault, errors.New("link fault requires src and dst"))
+		}
+	case RouterStallFault:
+		if f.Src == "" {
+			return errors.Join(ErrInvalidFault, errors.New("router fault requires src"))
+		}
+	case FlitDropFault, FlitCorruptFault:
+		if f.FlitID == "" {
+			return errors.Join(ErrInvalidFault, errors.New("flit fault requires flit_id"))
+		}
+		if (f.Src == "") != (f.Dst == "") {
+			return errors.Join(ErrInvalidFault, errors.New("flit fault requires both or neither of src and dst"))
+		}
+	default:
+		return errors.Join(ErrInvalidFault, fmt.Errorf("unknown fault type %q", f.Type))
+	}
+
+	return nil
+}
+
+func (f FaultConfig) Active(cycle int) bool {
+	return cycle >= f.StartCycle && (f.EndCycle == 0 || cycle <= f.EndCycle)
+}
+
+func (f FaultConfig) Link() Link {
+	return Link{Src: f.Src, Dst: f.Dst}
+}
+
+func (f FaultConfig) String() string {
+	switch f.Type {
+	case LinkDownFault:
+		return fmt.Sprintf("%s %s->%s", f.Type, f.Src, f.Dst)
+	case RouterStallFault:
+		return fmt.Sprintf("%s %s", f.Type, f.Src)
+	default:
+		return fmt.Sprintf("%s %s", f.Type, f.FlitID)
+	}
+}
+
+// Faults injected during simulation, the packets they affected, and the packets destination network interfaces could
+// not reconstruct.
+type FaultLog struct {
+	Faults []FaultConfig
+	// Number of cycles each link and router fault was active, and number of flits each flit fault was applied to.
+	Activations []int
+	// Indexes of the faults which affected each packet, by packet ID.
+	Affected map[string][]int
+	// Reason each discarded packet could not be reconstructed, by packet ID.
+	Discarded map[string]string
+}
+
+type PacketStatus string
+
+const (
+	PacketArrived   PacketStatus = "arrived"
+	PacketDiscarded PacketStatus = "discarded"
+	PacketLost      PacketStatus = "lost"
+)
+
+type FaultReport struct {
+	Faults  []FaultOutcome
+	Packets []FaultAffectedPacket
+}
+
+type FaultOutcome struct {
+	FaultConfig
+	Activations     int
+	PacketsAffected int
+}
+
+// A packet affected by one or more faults. Extra latency is the packet's latency above the mean latency of its traffic
+// flow's arrived packets unaffected by faults, and is only set for arrived packets.
+type FaultAffectedPacket struct {
+	TrafficFlowID string
+	PacketID      string
+	Faults        []int
+	Status        PacketStatus
+	Latency       int
+	ExtraLatency  float64
+}
+
+// Per traffic flow fault statistics, unreconstructed packets are affected packets which were discarded by, or never
+// completed at, their destination network interface.
+type FaultStats struct {
+	PacketsAffected        int     `csv:"PacketsAffected"`
+	PacketsUnreconstructed int     `csv:"PacketsUnreconstructed"`
+	MeanExtraLatency       float64 `csv:"MeanExtraLatency"`
+	MaxExtraLatency        float64 `csv:"MaxExtraLatency"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+type FlowControl string
+
+const (
+	// Credit based flow control in which an upstream component sends a flit only while it holds a credit for a free
+	// flit of the downstream virtual channel.
+	CreditFlowControl FlowControl = "credit"
+	// Threshold based flow control in which a downstream virtual channel signals its upstream component off once its
+	// free space falls to the off threshold, and back on once its free space rises to the on threshold.
+	OnOffFlowControl FlowControl = "on_off"
+	// Flow control in which an upstream component sends flits without knowledge of the downstream space, the downstream
+	// component acknowledging each flit it buffers and rejecting the first flit it has no space for, which the upstream
+	// component retransmits along with every flit sent after it.
+	AckNackFlowControl FlowControl = "ack_nack"
+)
+
+// Returns an array of all valid link flow control schemes.
+func FlowControls() []FlowControl {
+	return []FlowControl{
+		CreditFlowControl,
+		OnOffFlowControl,
+		AckNackFlowControl,
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+type InjectionQueuePolicy string
+
+const (
+	// A released packet which does not fit in the source network interface's injection queue waits for space, along
+	// with every later packet of its traffic flow.
+	BlockInjectionQueuePolicy InjectionQueuePolicy = "block"
+	// A released packet which does not fit in the injection queue is dropped.
+	DropNewInjectionQueuePolicy InjectionQueuePolicy = "drop_new"
+	// The oldest queued packets whose header flits have not been injected are dropped until a released packet fits in
+	// the injection queue, the released packet is dropped if it cannot be made to fit.
+	DropOldestInjectionQueuePolicy InjectionQueuePolicy = "drop_oldest"
+)
+
+// Returns an array of all valid injection queue overflow policies.
+func InjectionQueuePolicies() []InjectionQueuePolicy {
+	return []InjectionQueuePolicy{
+		BlockInjectionQueuePolicy,
+		DropNewInjectionQueuePolicy,
+		DropOldestInjectionQueuePolicy,
+	}
+}
+
+type InjectionQueueScope string
+
+const (
+	// The injection queue depth limits the flits queued at a network interface across every priority.
+	TotalInjectionQueueScope InjectionQueueScope = "total"
+	// The injection queue depth limits the flits queued at a network interface for each priority separately.
+	PriorityInjectionQueueScope InjectionQueueScope = "priority"
+)
+
+// Returns an array of all valid injection queue scopes.
+func InjectionQueueScopes() []InjectionQueueScope {
+	return []InjectionQueueScope{
+		TotalInjectionQueueScope,
+		PriorityInjectionQueueScope,
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// An operating mode, from its start cycle until the next mode's start cycle only the mode's traffic flows release
+// packets. Traffic flows are listed as "[t1,t2]".
+type ModeConfig struct {
+	ID           string `csv:"id"`
+	StartCycle   int    `csv:"start_cycle"`
+	TrafficFlows string `csv:"traffic_flows"`
+}
+
+func (m *ModeConfig) TrafficFlowIDs() ([]string, error) {
+	str := strings.Replace(m.TrafficFlows, "[", "", -1)
+	str = strings.Replace(str, "]", "", -1)
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return nil, errors.Join(ErrInvalidMode, errors.New("mode has no traffic flows"))
+	}
+
+	ids := strings.Split(str, ",")
+	for i := 0; i < len(ids); i++ {
+		ids[i] = strings.TrimSpace(ids[i])
+	}
+	return ids, nil
+}
+
+// Statistics of the packets generated while a mode was active, EndCycle is exclusive.
+type ModeStats struct {
+	ID         string
+	StartCycle int
+	EndCycle   int
+	TFStats    []ModeTFStats
+}
+
+// Statistics of the packets generated before a mode change which had not arrived by the change's cycle, i.e. the
+// packets straddling the change.
+type ModeChange struct {
+	FromID  string
+	ToID    string
+	Cycle   int
+	TFStats []ModeTFStats
+}
+
+// A traffic flow's statistics over a subset of its packets. The worst packet is the earliest generated arrived packet
+// with the worst latency.
+type ModeTFStats struct {
+	TrafficFlowID           string
+	PacketsRouted           int
+	PacketsArrived          int
+	PacketsExceededDeadline int
+	BestLatency             int
+	MeanLatency             float64
+	WorstLatency            int
+	WorstPacketID           string
+}
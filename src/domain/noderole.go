@@ -0,0 +1,21 @@
+package domain
+
+type NodeRole string
+
+const (
+	// A router without network interfaces of its own, e.g. an internal switch of an indirect network.
+	RouterNodeRole NodeRole = "router"
+	// A network interface attached to the single router it shares an edge with.
+	EndpointNodeRole NodeRole = "endpoint"
+	// A router with its own network interfaces.
+	RouterEndpointNodeRole NodeRole = "both"
+)
+
+// Returns an array of all valid topology node roles.
+func NodeRoles() []NodeRole {
+	return []NodeRole{
+		RouterNodeRole,
+		EndpointNodeRole,
+		RouterEndpointNodeRole,
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+// A router's pipeline stage latencies in cycles, routers apply a single processing delay stage to header flits when
+// every stage is unset.
+type RouterPipeline struct {
+	RoutingComputation int `yaml:"rc" json:"rc"`
+	VCAllocation       int `yaml:"va" json:"va"`
+	SwitchAllocation   int `yaml:"sa" json:"sa"`
+	SwitchTraversal    int `yaml:"st" json:"st"`
+	// Routing computation is performed for the next router while the header flit is allocated, removing the routing
+	// computation stage.
+	Lookahead bool `yaml:"lookahead" json:"lookahead"`
+	// Switch allocation is performed speculatively alongside virtual channel allocation, the two stages taking the
+	// latency of the longer.
+	Speculative bool `yaml:"speculative" json:"speculative"`
+}
+
+// Reports whether any pipeline stage latency is set.
+func (p RouterPipeline) Enabled() bool {
+	return p.RoutingComputation != 0 || p.VCAllocation != 0 || p.SwitchAllocation != 0 || p.SwitchTraversal != 0
+}
+
+// Returns the cycles a header flit spends in the routing computation, virtual channel allocation and switch allocation
+// stages, which it performs at the head of its virtual channel.
+func (p RouterPipeline) headerAllocation() int {
+	routing := p.RoutingComputation
+	if p.Lookahead {
+		routing = 0
+	}
+
+	if p.Speculative {
+		return routing + max(p.VCAllocation, p.SwitchAllocation)
+	}
+	return routing + p.VCAllocation + p.SwitchAllocation
+}
+
+// Returns a router's latency in cycles for header flits, or for body & tail flits, from the cycle the flit is buffered to
+// the cycle it may be sent, and the number of those cycles the flit must spend at the head of its virtual channel. Switch
+// traversal is pipelined, so body & tail flits following each other through the router are sent one switch allocation
+// apart, every cycle if switch allocation takes a cycle or less.
+func (c SimConfig) PipelineDelay(header bool) (latency, atHead int) {
+	if !c.Pipeline.Enabled() {
+		if header {
+			return c.ProcessingDelay, c.ProcessingDelay
+		}
+		return 1, 1
+	}
+
+	if header {
+		allocation := c.Pipeline.headerAllocation()
+		return allocation + c.Pipeline.SwitchTraversal, allocation
+	}
+	return max(c.Pipeline.SwitchAllocation+c.Pipeline.SwitchTraversal, 1), c.Pipeline.SwitchAllocation
+}
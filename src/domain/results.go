@@ -5,12 +5,30 @@ import "time"
 type SimResults struct {
 	SimHeadlineResults SimHeadlineResults
 	TFStats            map[string]StatSet
+	TFHopStats         map[string][]HopStats
+	TFTimelines        map[string][]PacketTimeline
+	NetworkStats       NetworkStats
+	DeadlineMisses     []DeadlineMiss
+	FaultReport        FaultReport
+	Modes              []ModeStats
+	ModeChanges        []ModeChange
 }
 
 type SimHeadlineResults struct {
-	Cycles   int
-	Duration time.Duration
+	// Cycles simulated, fewer than the cycle limit if the simulation was interrupted and its results truncated.
+	Cycles     int
+	CycleLimit int
+	Truncated  bool
+	Duration   time.Duration
 	StatSet
+	FairnessIndices
+}
+
+// Jain's fairness indices, from 1/n for n traffic flows when one flow receives all the service to 1 when every flow
+// receives an equal share, of the traffic flows' delivery ratios & slowdowns.
+type FairnessIndices struct {
+	DeliveryFairness float64
+	SlowdownFairness float64
 }
 
 type StatSet struct {
@@ -21,12 +39,190 @@ type StatSet struct {
 	BestLatency             int     `csv:"BestLatency"`
 	MeanLatency             float64 `csv:"MeanLatency"`
 	WorstLatency            int     `csv:"WorstLatency"`
+	LatencyPercentiles
+	LatencyDecomposition
+	FaultStats
+	FairnessStats
+	InjectionQueueStats
+	// Cycles lower priority traffic flows held an output the traffic flow was waiting for.
+	LowerPriorityBlocking int              `csv:"LowerPriorityBlocking"`
+	LatencyHistogram      LatencyHistogram `csv:"-"`
+	ObservedInterference  InterferenceSets `csv:"-"`
+}
+
+type LatencyPercentiles struct {
+	P50Latency  int `csv:"P50Latency"`
+	P90Latency  int `csv:"P90Latency"`
+	P99Latency  int `csv:"P99Latency"`
+	P999Latency int `csv:"P999Latency"`
+}
+
+// Breaks packet latency down into its constituent delays, in cycles:
+//   - Jitter: generation to release.
+//   - Queueing: release to the header flit's injection into the network from the source network interface.
+//   - Network: header flit injection to header flit arrival at the destination network interface.
+//   - Serialisation: header flit arrival to tail flit arrival at the destination network interface.
+type LatencyDecomposition struct {
+	MeanJitterDelay        float64 `csv:"MeanJitterDelay"`
+	MaxJitterDelay         int     `csv:"MaxJitterDelay"`
+	MeanQueueingDelay      float64 `csv:"MeanQueueingDelay"`
+	MaxQueueingDelay       int     `csv:"MaxQueueingDelay"`
+	MeanNetworkDelay       float64 `csv:"MeanNetworkDelay"`
+	MaxNetworkDelay        int     `csv:"MaxNetworkDelay"`
+	MeanSerialisationDelay float64 `csv:"MeanSerialisationDelay"`
+	MaxSerialisationDelay  int     `csv:"MaxSerialisationDelay"`
+}
+
+// The share of the network a traffic flow received:
+//   - Throughput: flits delivered to the destination network interface per cycle.
+//   - DeliveryRatio: fraction of the packets routed which arrived.
+//   - Slowdown: mean latency relative to the best latency.
+type FairnessStats struct {
+	Throughput    float64 `csv:"Throughput"`
+	DeliveryRatio float64 `csv:"DeliveryRatio"`
+	Slowdown      float64 `csv:"Slowdown"`
+}
+
+// A traffic flow's use of its source network interface's injection queue:
+//   - Overflows: released packets which did not fit in the injection queue.
+//   - PacketsDropped: packets dropped from, or instead of entering, the injection queue.
+//   - MaxQueueDepth: the most flits of the traffic flow's packets queued at once.
+type InjectionQueueStats struct {
+	Overflows      int `csv:"InjectionQueueOverflows"`
+	PacketsDropped int `csv:"InjectionQueueDropped"`
+	MaxQueueDepth  int `csv:"MaxInjectionQueueDepth"`
+}
+
+// Maps a packet latency, in cycles, to the number of packets which experienced that latency.
+type LatencyHistogram map[int]int
+
+// Traffic flow IDs, sorted, of the flows interfering with a traffic flow.
+type InterferenceSets struct {
+	Direct   []string
+	Indirect []string
 }
 
 func (s *StatSet) Schedulable() bool {
 	return s.PacketsExceededDeadline == 0
 }
 
+// Cycles at which a packet's header and tail flits entered and left a router's input buffer.
+type HopTimestamps struct {
+	Router    string
+	HeaderIn  int
+	HeaderOut int
+	TailIn    int
+	TailOut   int
+}
+
+func (h HopTimestamps) HeaderWait() int {
+	return h.HeaderOut - h.HeaderIn
+}
+
+func (h HopTimestamps) TailWait() int {
+	return h.TailOut - h.TailIn
+}
+
+type PacketTimeline struct {
+	PacketID        string
+	GenerationCycle int
+	ReleaseCycle    int
+	InjectionCycle  int
+	ArrivalCycle    int
+	Hops            []HopTimestamps
+}
+
+// Header and tail flit waiting times at a hop, i.e. the duration the flits spent in the hop router's input buffer.
+type HopStats struct {
+	Hop            int
+	Router         string
+	MeanHeaderWait float64
+	MaxHeaderWait  int
+	MeanTailWait   float64
+	MaxTailWait    int
+}
+
+type NetworkStats struct {
+	Links             []LinkStats
+	Buffers           []BufferStats
+	NetworkInterfaces []NetworkInterfaceStats
+	// Maps a victim traffic flow to the number of cycles each interfering traffic flow's flits held an output port the
+	// victim's flits were waiting to be sent through.
+	Interference map[string]map[string]int
+	// Maps a traffic flow to its use of its source network interface's injection queue.
+	InjectionQueues map[string]InjectionQueueStats
+}
+
+// Statistics for a directed router to router connection.
+type LinkStats struct {
+	Src         string
+	Dst         string
+	Flits       int
+	Utilisation float64
+	// Flits resent under ack/nack flow control, included in Flits.
+	Retransmissions int
+}
+
+// Statistics for a network interface's injection and ejection links, and the flits waiting in its ejection buffer to be
+// consumed.
+type NetworkInterfaceStats struct {
+	ID                    string
+	Router                string
+	InjectedFlits         int
+	InjectionUtilisation  float64
+	EjectedFlits          int
+	EjectionUtilisation   float64
+	MeanEjectionOccupancy float64
+	MaxEjectionOccupancy  int
+}
+
+// Input port identifier for the port connected to the router's first network interface, further network interfaces
+// are identified by their IDs.
+const LocalPort = "local"
+
+// Statistics for a router input port's virtual channel, the input port is identified by its upstream component.
+type BufferStats struct {
+	Router              string
+	InputPort           string
+	VirtualChannel      int
+	MeanOccupancy       float64
+	MaxOccupancy        int
+	CreditBlockedCycles int
+}
+
+// A directed link, the network interface end of an injection or ejection link is identified by LocalPort for a router's
+// first network interface and by the network interface's ID otherwise.
+type Link struct {
+	Src string
+	Dst string
+}
+
+// A flit sent over a link.
+type LinkTraversal struct {
+	Cycle         int
+	TrafficFlowID string
+	PacketID      string
+	FlitType      string
+}
+
+// Forensic record of a packet which arrived after its deadline.
+type DeadlineMiss struct {
+	PacketTimeline
+	TrafficFlowID string
+	Deadline      int
+	Latency       int
+	LinkWaits     []LinkWait
+}
+
+// The window, from the packet's header flit reaching the link's upstream component to its tail flit crossing the link,
+// during which the packet waited for and used the link, and the other traffic flows' flits sent over it meanwhile.
+type LinkWait struct {
+	Link
+	StartCycle int
+	EndCycle   int
+	Occupants  []LinkTraversal
+}
+
 type AnalysisResults map[string]TrafficFlowAnalysisSet
 
 func (r AnalysisResults) AnalysesSchedulable() (bool, []string) {
@@ -51,6 +247,9 @@ type TrafficFlowAnalysisSet struct {
 	ShiAndBurns               int
 	DirectInterferenceCount   int
 	IndirectInterferenceCount int
+	InterferenceSets
+	// Worst case blocking by lower priority packets holding output ports, non-preemptive switching only.
+	Blocking int
 }
 
 func (a TrafficFlowAnalysisSet) AnalysisSchedulable() bool {
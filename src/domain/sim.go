@@ -1,8 +1,343 @@
 package domain
 
+import (
+	"math"
+	"time"
+)
+
 type SimConfig struct {
 	CycleLimit      int `yaml:"cycle_limit" json:"cycle_limit"`
 	MaxPriority     int `yaml:"max_priority" json:"max_priority"`
 	BufferSize      int `yaml:"buffer_size" json:"buffer_size"`
 	ProcessingDelay int `yaml:"processing_delay" json:"processing_delay"`
+	// Default router to router link flit and credit latencies in cycles, 1 if unset.
+	LinkLatency   int `yaml:"link_latency" json:"link_latency"`
+	CreditLatency int `yaml:"credit_latency" json:"credit_latency"`
+	// Default router to router link bandwidth in flits per cycle, 1 if unset.
+	LinkBandwidth float64 `yaml:"link_bandwidth" json:"link_bandwidth"`
+	// Number of virtual channels per buffer, one per priority if unset.
+	NumVCs int `yaml:"num_vcs" json:"num_vcs"`
+	// Virtual channel of each priority, highest priority first, priorities are split into equal bands of consecutive
+	// priorities per virtual channel if unset.
+	VCMap []int `yaml:"vc_map" json:"vc_map"`
+	// Depth of each virtual channel in flits, buffers are split evenly across virtual channels if unset.
+	VCDepths []int `yaml:"vc_depths" json:"vc_depths"`
+	// Router arbitration policy, fixed priority if unset.
+	Arbiter ArbitrationPolicy `yaml:"arbiter" json:"arbiter"`
+	// Weighted round robin weight of each priority, highest priority first, MaxPriority - p + 1 for priority p if
+	// unset.
+	ArbiterWeights []int `yaml:"arbiter_weights" json:"arbiter_weights"`
+	// Router switching mode, priority pre-emptive wormhole if unset.
+	Switching SwitchingMode `yaml:"switching" json:"switching"`
+	// Router pipeline stage latencies, replacing ProcessingDelay if any stage is set.
+	Pipeline RouterPipeline `yaml:"pipeline" json:"pipeline"`
+	// Flits each router output port accepts from the crossbar per cycle, 1 if unset.
+	CrossbarSpeedup int `yaml:"crossbar_speedup" json:"crossbar_speedup"`
+	// Depth in flits of each router output port's virtual channel buffers, output ports are unbuffered if unset.
+	OutputBufferDepth int `yaml:"output_buffer_depth" json:"output_buffer_depth"`
+	// Link flow control scheme, credit based if unset.
+	FlowControl FlowControl `yaml:"flow_control" json:"flow_control"`
+	// On/off flow control only, the free flits of a virtual channel at or below which it signals off and at or above
+	// which it signals on, by default off leaves space for every flit which may arrive before the signal does and on is
+	// a flit above off.
+	OffThreshold int `yaml:"off_threshold" json:"off_threshold"`
+	OnThreshold  int `yaml:"on_threshold" json:"on_threshold"`
+	// Ack/nack flow control only, the flits per virtual channel an upstream component may have sent and not had
+	// acknowledged, the downstream virtual channel's depth if unset.
+	RetransmissionWindow int `yaml:"retransmission_window" json:"retransmission_window"`
+	// Flits each network interface's injection queue holds, in total or per priority, unbounded if unset.
+	InjectionQueueDepth int `yaml:"injection_queue_depth" json:"injection_queue_depth"`
+	// Whether the injection queue depth applies in total or per priority, in total if unset.
+	InjectionQueueScope InjectionQueueScope `yaml:"injection_queue_scope" json:"injection_queue_scope"`
+	// Handling of released packets which do not fit in the injection queue, blocking the traffic flow if unset.
+	InjectionQueuePolicy InjectionQueuePolicy `yaml:"injection_queue_policy" json:"injection_queue_policy"`
+	// Flits each network interface consumes per cycle, a whole number or a single flit every whole number of cycles,
+	// unlimited if unset.
+	EjectionRate float64 `yaml:"ejection_rate" json:"ejection_rate"`
+	// Cycles a network interface spends servicing each packet once it has consumed the packet's tail flit, consuming no
+	// flits meanwhile.
+	EjectionServiceTime int `yaml:"ejection_service_time" json:"ejection_service_time"`
+	// Size of each network interface's ejection buffer in flits, BufferSize if unset.
+	EjectionBufferSize int `yaml:"ejection_buffer_size" json:"ejection_buffer_size"`
+}
+
+// Returns the router switching mode.
+func (c SimConfig) SwitchingMode() SwitchingMode {
+	if c.Switching == "" {
+		return WormholeSwitching
+	}
+	return c.Switching
+}
+
+// Reports whether a packet may only be sent once the downstream virtual channel can hold the whole packet.
+func (c SimConfig) WholePacketSwitching() bool {
+	return c.SwitchingMode() == VirtualCutThroughSwitching || c.SwitchingMode() == StoreAndForwardSwitching
+}
+
+// Reports whether a packet's flits may be pre-empted at an output port by another packet's flits.
+func (c SimConfig) Preemptive() bool {
+	return c.SwitchingMode() != NonPreemptiveWormholeSwitching
+}
+
+// Returns the link flow control scheme.
+func (c SimConfig) FlowControlScheme() FlowControl {
+	if c.FlowControl == "" {
+		return CreditFlowControl
+	}
+	return c.FlowControl
+}
+
+// Returns whether the injection queue depth applies in total or per priority.
+func (c SimConfig) InjectionQueueLimitScope() InjectionQueueScope {
+	if c.InjectionQueueScope == "" {
+		return TotalInjectionQueueScope
+	}
+	return c.InjectionQueueScope
+}
+
+// Returns the handling of released packets which do not fit in the injection queue.
+func (c SimConfig) InjectionQueueOverflowPolicy() InjectionQueuePolicy {
+	if c.InjectionQueuePolicy == "" {
+		return BlockInjectionQueuePolicy
+	}
+	return c.InjectionQueuePolicy
+}
+
+// Returns the size of each network interface's ejection buffer in flits.
+func (c SimConfig) EjectionBuffer() int {
+	if c.EjectionBufferSize > 0 {
+		return c.EjectionBufferSize
+	}
+	return c.BufferSize
+}
+
+// Returns the number of flits each router output port accepts from the crossbar per cycle.
+func (c SimConfig) Speedup() int {
+	return max(c.CrossbarSpeedup, 1)
+}
+
+// Returns the router arbitration policy.
+func (c SimConfig) ArbitrationPolicy() ArbitrationPolicy {
+	if c.Arbiter == "" {
+		return FixedPriorityArbitration
+	}
+	return c.Arbiter
+}
+
+// Returns the weighted round robin weight of each priority, priority p at index p - 1.
+func (c SimConfig) ArbitrationWeights() []int {
+	if len(c.ArbiterWeights) > 0 {
+		return append([]int(nil), c.ArbiterWeights...)
+	}
+
+	weights := make([]int, max(c.MaxPriority, 0))
+	for p := 1; p <= c.MaxPriority; p++ {
+		weights[p-1] = c.MaxPriority - p + 1
+	}
+	return weights
+}
+
+// Returns the number of virtual channels per buffer.
+func (c SimConfig) VCCount() int {
+	if c.NumVCs > 0 {
+		return c.NumVCs
+	}
+	return c.MaxPriority
+}
+
+// Returns the virtual channel of each priority, priority p at index p - 1, or nil if every priority has its own virtual
+// channel.
+func (c SimConfig) VChanMap() []int {
+	if len(c.VCMap) > 0 {
+		return append([]int(nil), c.VCMap...)
+	}
+	if c.VCCount() == c.MaxPriority || c.MaxPriority < 1 {
+		return nil
+	}
+
+	// Static bands, e.g. 16 priorities over 4 virtual channels map priorities 1-4 to virtual channel 1.
+	vChans := make([]int, c.MaxPriority)
+	for p := 1; p <= c.MaxPriority; p++ {
+		vChans[p-1] = (p-1)*c.VCCount()/c.MaxPriority + 1
+	}
+	return vChans
+}
+
+// Returns the depth in flits of each virtual channel in a buffer of bufferSize flits. The configured virtual channel
+// depths apply when set, nil if they do not total bufferSize, otherwise the buffer is split evenly across virtual
+// channels, nil if it cannot be.
+func (c SimConfig) VChanDepths(bufferSize int) []int {
+	numVCs := c.VCCount()
+	if len(c.VCDepths) > 0 {
+		total := 0
+		for _, depth := range c.VCDepths {
+			total += depth
+		}
+		if len(c.VCDepths) != numVCs || total != bufferSize {
+			return nil
+		}
+		return append([]int(nil), c.VCDepths...)
+	}
+
+	if numVCs < 1 || bufferSize < 1 || bufferSize%numVCs != 0 {
+		return nil
+	}
+
+	depths := make([]int, numVCs)
+	for i := range depths {
+		depths[i] = bufferSize / numVCs
+	}
+	return depths
+}
+
+// Returns the depth in flits of the virtual channel the priority's flits use in a buffer of bufferSize flits, 0 if the
+// buffer cannot be split into its virtual channels.
+func (c SimConfig) VChanDepth(bufferSize, priority int) int {
+	depths := c.VChanDepths(bufferSize)
+
+	vChan := priority
+	if vChans := c.VChanMap(); priority >= 1 && priority <= len(vChans) {
+		vChan = vChans[priority-1]
+	}
+
+	if vChan < 1 || vChan > len(depths) {
+		return 0
+	}
+	return depths[vChan-1]
+}
+
+// A link's flit and credit latencies in cycles, bandwidth in flits per cycle and the size of the buffer it feeds at the
+// downstream router in flits, zero values are unset.
+type LinkConfig struct {
+	Latency       int
+	CreditLatency int
+	Bandwidth     float64
+	BufferSize    int
+}
+
+// A node's role, router's buffer size in flits, header flit processing delay in cycles, number of attached network
+// interfaces, each with its own local input and output port, and its network interfaces' ejection rate, service time
+// and buffer size, zero values are unset.
+type NodeConfig struct {
+	Role                NodeRole
+	BufferSize          int
+	ProcessingDelay     int
+	LocalPorts          int
+	EjectionRate        float64
+	EjectionServiceTime int
+	EjectionBufferSize  int
+}
+
+// Returns the node's role, a router with its own network interfaces if unset.
+func (n NodeConfig) NodeRole() NodeRole {
+	if n.Role == "" {
+		return RouterEndpointNodeRole
+	}
+	return n.Role
+}
+
+// Reports whether the node is a router.
+func (n NodeConfig) HasRouter() bool {
+	return n.NodeRole() != EndpointNodeRole
+}
+
+// Reports whether the node is, or has, a network interface traffic flows may start and end at.
+func (n NodeConfig) HasNetworkInterface() bool {
+	return n.NodeRole() != RouterNodeRole
+}
+
+// Returns the number of network interfaces the router creates for itself, none for a router without network
+// interfaces of its own.
+func (n NodeConfig) NetworkInterfaceCount() int {
+	if n.NodeRole() != RouterEndpointNodeRole {
+		return 0
+	}
+	return max(n.LocalPorts, 1)
+}
+
+// Tolerance when checking a bandwidth is a whole number of flits per cycle or of cycles per flit.
+const bandwidthTolerance = 1e-9
+
+// Reports whether the bandwidth is a whole number of flits per cycle, or a single flit every whole number of cycles.
+func ValidBandwidth(bandwidth float64) bool {
+	if bandwidth <= 0 || math.IsInf(bandwidth, 0) || math.IsNaN(bandwidth) {
+		return false
+	}
+	if bandwidth >= 1 {
+		return math.Abs(bandwidth-math.Round(bandwidth)) < bandwidthTolerance
+	}
+	return math.Abs(1/bandwidth-math.Round(1/bandwidth)) < bandwidthTolerance
+}
+
+// Returns the number of flits the link carries per cycle, at least 1.
+func (l LinkConfig) FlitsPerCycle() int {
+	return FlitsPerCycle(l.Bandwidth)
+}
+
+// Returns the number of cycles between flits sent over the link, at least 1.
+func (l LinkConfig) CyclesPerFlit() int {
+	return CyclesPerFlit(l.Bandwidth)
+}
+
+// Returns the number of whole flits per cycle of the bandwidth, at least 1.
+func FlitsPerCycle(bandwidth float64) int {
+	return max(int(math.Round(bandwidth)), 1)
+}
+
+// Returns the number of cycles between flits at the bandwidth, at least 1.
+func CyclesPerFlit(bandwidth float64) int {
+	if bandwidth <= 0 || bandwidth >= 1 {
+		return 1
+	}
+	return int(math.Round(1 / bandwidth))
+}
+
+// Returns the simulation configuration of a node's router and network interfaces, with the node's set values replacing
+// the global values.
+func (c SimConfig) ForNode(node NodeConfig) SimConfig {
+	if node.BufferSize != 0 {
+		c.BufferSize = node.BufferSize
+	}
+	if node.ProcessingDelay != 0 {
+		// A router's own processing delay replaces the configured pipeline.
+		c.ProcessingDelay = node.ProcessingDelay
+		c.Pipeline = RouterPipeline{}
+	}
+	if node.EjectionRate != 0 {
+		c.EjectionRate = node.EjectionRate
+	}
+	if node.EjectionServiceTime != 0 {
+		c.EjectionServiceTime = node.EjectionServiceTime
+	}
+	if node.EjectionBufferSize != 0 {
+		c.EjectionBufferSize = node.EjectionBufferSize
+	}
+	return c
+}
+
+// Resolves a router to router link's configuration, unset values fall back to the simulation configuration's defaults,
+// and to a single cycle if those are also unset.
+func (c SimConfig) ResolveLink(link LinkConfig) LinkConfig {
+	if link.Latency == 0 {
+		link.Latency = max(c.LinkLatency, 1)
+	}
+	if link.CreditLatency == 0 {
+		link.CreditLatency = max(c.CreditLatency, 1)
+	}
+	if link.Bandwidth == 0 {
+		link.Bandwidth = c.LinkBandwidth
+		if link.Bandwidth <= 0 {
+			link.Bandwidth = 1
+		}
+	}
+	return link
+}
+
+// Snapshot of a running simulation's progress, Done is set on the final snapshot once the simulation stops.
+type SimProgress struct {
+	Cycle          int
+	CycleLimit     int
+	Elapsed        time.Duration
+	PacketsArrived int
+	Done           bool
 }
@@ -0,0 +1,28 @@
+package domain
+
+type SwitchingMode string
+
+const (
+	// Wormhole switching in which a higher priority virtual channel pre-empts a lower priority packet's use of an
+	// output port between any two of its flits.
+	WormholeSwitching SwitchingMode = "wormhole"
+	// Classic wormhole switching in which an output port, once allocated to a packet's header flit, is held by the
+	// packet until its tail flit has been sent.
+	NonPreemptiveWormholeSwitching SwitchingMode = "non_preemptive_wormhole"
+	// Virtual cut-through switching in which a packet's header flit is only sent once the downstream virtual channel
+	// can hold the whole packet.
+	VirtualCutThroughSwitching SwitchingMode = "vct"
+	// Store-and-forward switching in which, as in virtual cut-through, the downstream virtual channel must hold the
+	// whole packet and a packet's header flit is only processed once the packet's tail flit has been buffered.
+	StoreAndForwardSwitching SwitchingMode = "saf"
+)
+
+// Returns an array of all valid router switching modes.
+func SwitchingModes() []SwitchingMode {
+	return []SwitchingMode{
+		WormholeSwitching,
+		NonPreemptiveWormholeSwitching,
+		VirtualCutThroughSwitching,
+		StoreAndForwardSwitching,
+	}
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -15,7 +17,47 @@ type TrafficFlowConfig struct {
 	Route      string `csv:"route"`
 }
 
+// Separates a router's node ID from the index of one of its network interfaces in a traffic flow endpoint, e.g. n1:2.
+const NetworkInterfaceSeparator = ":"
+
+// Returns the route's routers, without the network interface indices of its source and destination endpoints.
 func (t *TrafficFlowConfig) RouteArray() ([]string, error) {
+	route, err := t.endpointRoute()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range []int{0, len(route) - 1} {
+		router, _, err := ParseEndpoint(route[i])
+		if err != nil {
+			return nil, err
+		}
+		route[i] = router
+	}
+
+	return route, nil
+}
+
+// Returns the IDs of the network interfaces the traffic flow's packets are injected at and ejected to.
+func (t *TrafficFlowConfig) Endpoints() (string, string, error) {
+	route, err := t.endpointRoute()
+	if err != nil {
+		return "", "", err
+	}
+
+	endpoints := make([]string, 2)
+	for i, endpoint := range []string{route[0], route[len(route)-1]} {
+		router, index, err := ParseEndpoint(endpoint)
+		if err != nil {
+			return "", "", err
+		}
+		endpoints[i] = NetworkInterfaceID(router, index)
+	}
+
+	return endpoints[0], endpoints[1], nil
+}
+
+func (t *TrafficFlowConfig) endpointRoute() ([]string, error) {
 	if len(t.Route) < 2 {
 		return nil, errors.Join(ErrInvalidConfig, ErrInvalidRoute)
 	}
@@ -24,3 +66,36 @@ func (t *TrafficFlowConfig) RouteArray() ([]string, error) {
 	str = strings.Replace(str, "]", "", -1)
 	return strings.Split(str, ","), nil
 }
+
+// Splits a traffic flow endpoint into its router's node ID and the index of the router's network interface, 0 if the
+// endpoint is a bare node ID.
+func ParseEndpoint(endpoint string) (string, int, error) {
+	router, indexStr, hasIndex := strings.Cut(endpoint, NetworkInterfaceSeparator)
+	if !hasIndex {
+		return router, 0, nil
+	}
+
+	index, err := strconv.Atoi(indexStr)
+	if err != nil || index < 0 {
+		return "", 0, errors.Join(ErrInvalidConfig, ErrInvalidRoute, fmt.Errorf("invalid network interface index in endpoint %q", endpoint))
+	}
+	return router, index, nil
+}
+
+// Returns the ID of the router's network interface with the index, a router's first network interface shares its
+// node ID.
+func NetworkInterfaceID(router string, index int) string {
+	if index == 0 {
+		return router
+	}
+	return router + NetworkInterfaceSeparator + strconv.Itoa(index)
+}
+
+// Returns the port identifier of the router's network interface in link and buffer statistics, LocalPort for the
+// router's first network interface.
+func NetworkInterfacePort(netIntfcID, router string) string {
+	if netIntfcID == router {
+		return LocalPort
+	}
+	return netIntfcID
+}
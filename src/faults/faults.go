@@ -0,0 +1,41 @@
+package faults
+
+import (
+	"path/filepath"
+
+	"main/log"
+	"main/src/domain"
+
+	csvtag "github.com/artonge/go-csv-tag/v2"
+)
+
+func LoadFaultSchedule(fPath string) ([]domain.FaultConfig, error) {
+	var faults []domain.FaultConfig
+	var err error
+
+	log.Log.Debug().Msg("reading fault schedule file")
+
+	switch filepath.Ext(fPath) {
+	case ".csv":
+		err = csvtag.LoadFromPath(fPath, &faults)
+		log.Log.Debug().Msg("read .csv fault schedule file")
+
+	default:
+		log.Log.Error().Err(domain.ErrInvalidFilepath).Str("ext", filepath.Ext(fPath)).Msg("invalid fault schedule file extension")
+		return nil, domain.ErrInvalidFilepath
+	}
+
+	if err != nil {
+		log.Log.Error().Err(err).Str("path", fPath).Msg("error loading fault schedule from file")
+		return nil, err
+	}
+
+	for i := 0; i < len(faults); i++ {
+		if err := faults[i].Validate(); err != nil {
+			log.Log.Error().Err(err).Int("row", i+1).Msg("invalid fault in fault schedule")
+			return nil, err
+		}
+	}
+
+	return faults, nil
+}
@@ -0,0 +1,51 @@
+package faults
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"main/src/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFaultSchedule(t *testing.T) {
+	t.Parallel()
+
+	writeSchedule := func(t *testing.T, name, content string) string {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		path := writeSchedule(t, "faults.csv", "type,start_cycle,end_cycle,src,dst,flit_id\n"+
+			"link_down,100,200,n1,n2,\n"+
+			"router_stall,50,60,n5,,\n"+
+			"flit_drop,0,0,,,t1-30-2\n"+
+			"flit_corrupt,0,0,n2,local,t2-31-0\n")
+
+		faults, err := LoadFaultSchedule(path)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.FaultConfig{
+			{Type: domain.LinkDownFault, StartCycle: 100, EndCycle: 200, Src: "n1", Dst: "n2"},
+			{Type: domain.RouterStallFault, StartCycle: 50, EndCycle: 60, Src: "n5"},
+			{Type: domain.FlitDropFault, FlitID: "t1-30-2"},
+			{Type: domain.FlitCorruptFault, Src: "n2", Dst: "local", FlitID: "t2-31-0"},
+		}, faults)
+	})
+
+	t.Run("InvalidFault", func(t *testing.T) {
+		path := writeSchedule(t, "faults.csv", "type,start_cycle,end_cycle,src,dst,flit_id\nlink_down,100,200,n1,,\n")
+
+		_, err := LoadFaultSchedule(path)
+		assert.ErrorIs(t, err, domain.ErrInvalidFault)
+	})
+
+	t.Run("InvalidExtension", func(t *testing.T) {
+		_, err := LoadFaultSchedule("faults.json")
+		assert.ErrorIs(t, err, domain.ErrInvalidFilepath)
+	})
+}
@@ -1,7 +1,12 @@
 package topology
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"main/log"
 	"main/src/domain"
@@ -9,6 +14,35 @@ import (
 	"github.com/yaricom/goGraphML/graphml"
 )
 
+// GraphML edge attributes configuring the links in both directions of the edge, latencies in cycles, bandwidth in
+// flits per cycle and buffer size in flits.
+const (
+	latencyAttr       = "latency"
+	creditLatencyAttr = "credit_latency"
+	bandwidthAttr     = "bandwidth"
+)
+
+// GraphML node attributes configuring the node's router, and edge attribute configuring the buffers fed by the edge.
+const (
+	bufferSizeAttr      = "buffer_size"
+	processingDelayAttr = "processing_delay"
+)
+
+// GraphML node attributes setting the number of network interfaces attached to the node's router, and whether the
+// node is a router, a network interface or both.
+const (
+	localPortsAttr = "local_ports"
+	roleAttr       = "role"
+)
+
+// GraphML node attributes configuring the node's network interfaces' consumption of arriving flits, the ejection rate in
+// flits per cycle, service time in cycles and ejection buffer size in flits.
+const (
+	ejectionRateAttr        = "ejection_rate"
+	ejectionServiceTimeAttr = "ejection_service_time"
+	ejectionBufferSizeAttr  = "ejection_buffer_size"
+)
+
 func graphML(filepath string) (*Topology, error) {
 	log.Log.Debug().Msg("reading GraphML topology file")
 
@@ -34,13 +68,13 @@ func graphML(filepath string) (*Topology, error) {
 
 	log.Log.Debug().Msg("parsing GraphML topology file")
 
-	nodes, err := graphMLNodes(graph.Nodes)
+	nodes, nodeConfigs, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), graph.Nodes)
 	if err != nil {
 		log.Log.Error().Err(err).Str("path", filepath).Msg("error parsing GraphML nodes")
 		return nil, err
 	}
 
-	edges, err := graphMLEdges(nodes, graph.Edges)
+	edges, err := graphMLEdges(nodes, graphMLKeys(gml.Keys, graphml.KeyForEdge), graph.Edges)
 	if err != nil {
 		log.Log.Error().Err(err).Str("path", filepath).Msg("error parsing GraphML edges")
 		return nil, err
@@ -49,26 +83,127 @@ func graphML(filepath string) (*Topology, error) {
 	log.Log.Debug().Msg("parsed GraphML topology file")
 
 	top := NewTopology(nodes, edges)
+	for id, conf := range nodeConfigs {
+		top.SetNodeConfig(id, conf)
+	}
+
+	if err := top.validateRoles(); err != nil {
+		log.Log.Error().Err(err).Str("path", filepath).Msg("invalid GraphML node roles")
+		return nil, err
+	}
 
 	log.Log.Debug().Msg("loaded topology from GraphML file")
 	return top, nil
 }
 
-func graphMLNodes(gmlNodes []*graphml.Node) (map[string]*Node, error) {
+// Returns the nodes by ID and the router configuration of each node with router attributes.
+func graphMLNodes(keys map[string]*graphml.Key, gmlNodes []*graphml.Node) (map[string]*Node, map[string]domain.NodeConfig, error) {
 	var nodes map[string]*Node = make(map[string]*Node, len(gmlNodes))
+	nodeConfigs := make(map[string]domain.NodeConfig)
 	for i := 0; i < len(gmlNodes); i++ {
+		if strings.Contains(gmlNodes[i].ID, domain.NetworkInterfaceSeparator) {
+			log.Log.Error().Err(domain.ErrInvalidTopology).Str("id", gmlNodes[i].ID).Str("separator", domain.NetworkInterfaceSeparator).Msg("GraphML node ID contains the network interface separator")
+			return nil, nil, domain.ErrInvalidTopology
+		}
+
 		node := NewNode(gmlNodes[i].ID)
 		nodes[node.NodeID()] = node
+
+		conf, err := parseGraphMLNodeConfig(keys, gmlNodes[i].Data)
+		if err != nil {
+			log.Log.Error().Err(err).Str("id", gmlNodes[i].ID).Msg("invalid GraphML node attribute")
+			return nil, nil, err
+		}
+		if conf != (domain.NodeConfig{}) {
+			nodeConfigs[node.NodeID()] = conf
+		}
 	}
 
 	log.Log.Debug().Msg("parsed GraphML nodes")
-	return nodes, nil
+	return nodes, nodeConfigs, nil
+}
+
+// Reads the node's router attributes from its data, falling back to the attribute keys' default values.
+func parseGraphMLNodeConfig(keys map[string]*graphml.Key, data []*graphml.Data) (domain.NodeConfig, error) {
+	attrs := graphMLAttributes(keys, data)
+
+	var conf domain.NodeConfig
+	if str, exists := attrs[roleAttr]; exists {
+		conf.Role = domain.NodeRole(strings.TrimSpace(str))
+		if !slices.Contains(domain.NodeRoles(), conf.Role) {
+			return domain.NodeConfig{}, errors.Join(domain.ErrInvalidTopology, fmt.Errorf("%s must be one of %v, got %q", roleAttr, domain.NodeRoles(), str))
+		}
+	}
+
+	for attr, dst := range map[string]*int{
+		bufferSizeAttr:          &conf.BufferSize,
+		processingDelayAttr:     &conf.ProcessingDelay,
+		localPortsAttr:          &conf.LocalPorts,
+		ejectionServiceTimeAttr: &conf.EjectionServiceTime,
+		ejectionBufferSizeAttr:  &conf.EjectionBufferSize,
+	} {
+		if err := parseGraphMLPositiveInt(attrs, attr, dst); err != nil {
+			return domain.NodeConfig{}, err
+		}
+	}
+
+	if str, exists := attrs[ejectionRateAttr]; exists {
+		val, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		if err != nil || !domain.ValidBandwidth(val) {
+			return domain.NodeConfig{}, errors.Join(domain.ErrInvalidTopology, fmt.Errorf("%s must be a whole number of flits per cycle or of cycles per flit, got %q", ejectionRateAttr, str))
+		}
+		conf.EjectionRate = val
+	}
+
+	return conf, nil
 }
 
-func graphMLEdges(nodes map[string]*Node, gmlEdges []*graphml.Edge) (map[string]*Edge, error) {
+// Maps the IDs of the keys applicable to the target element to their keys.
+func graphMLKeys(gmlKeys []*graphml.Key, target graphml.KeyForElement) map[string]*graphml.Key {
+	keys := make(map[string]*graphml.Key, len(gmlKeys))
+	for i := 0; i < len(gmlKeys); i++ {
+		if gmlKeys[i].Target == target || gmlKeys[i].Target == graphml.KeyForAll {
+			keys[gmlKeys[i].ID] = gmlKeys[i]
+		}
+	}
+	return keys
+}
+
+// Maps attribute names to the element's values, falling back to the attribute keys' default values.
+func graphMLAttributes(keys map[string]*graphml.Key, data []*graphml.Data) map[string]string {
+	attrs := make(map[string]string)
+	for _, key := range keys {
+		if key.DefaultValue != "" {
+			attrs[key.Name] = key.DefaultValue
+		}
+	}
+	for i := 0; i < len(data); i++ {
+		if key, exists := keys[data[i].Key]; exists {
+			attrs[key.Name] = data[i].Value
+		}
+	}
+	return attrs
+}
+
+// Parses the attribute into dst if present, it must be an integer greater than 0.
+func parseGraphMLPositiveInt(attrs map[string]string, attr string, dst *int) error {
+	str, exists := attrs[attr]
+	if !exists {
+		return nil
+	}
+
+	val, err := strconv.Atoi(strings.TrimSpace(str))
+	if err != nil || val < 1 {
+		return errors.Join(domain.ErrInvalidTopology, fmt.Errorf("%s must be an integer greater than 0, got %q", attr, str))
+	}
+	*dst = val
+	return nil
+}
+
+func graphMLEdges(nodes map[string]*Node, keys map[string]*graphml.Key, gmlEdges []*graphml.Edge) (map[string]*Edge, error) {
 	var edges map[string]*Edge = make(map[string]*Edge, len(gmlEdges))
 	for i := 0; i < len(gmlEdges); i++ {
-		edge, err := parseGraphMLEdge(nodes, gmlEdges[i])
+		edge, err := parseGraphMLEdge(nodes, keys, gmlEdges[i])
 		if err != nil {
 			log.Log.Error().Err(err).Str("id", gmlEdges[i].ID).Msg("error parsing GraphML edge")
 			return nil, err
@@ -81,7 +216,7 @@ func graphMLEdges(nodes map[string]*Node, gmlEdges []*graphml.Edge) (map[string]
 	return edges, nil
 }
 
-func parseGraphMLEdge(nodes map[string]*Node, gmlEdge *graphml.Edge) (*Edge, error) {
+func parseGraphMLEdge(nodes map[string]*Node, keys map[string]*graphml.Key, gmlEdge *graphml.Edge) (*Edge, error) {
 	aNode, exists := nodes[gmlEdge.Source]
 	if !exists {
 		log.Log.Error().Err(domain.ErrInvalidTopology).Str("id", gmlEdge.ID).Msg("GraphML edge missing source node")
@@ -94,6 +229,37 @@ func parseGraphMLEdge(nodes map[string]*Node, gmlEdge *graphml.Edge) (*Edge, err
 		return nil, domain.ErrInvalidTopology
 	}
 
+	link, err := parseGraphMLLinkConfig(keys, gmlEdge.Data)
+	if err != nil {
+		log.Log.Error().Err(err).Str("id", gmlEdge.ID).Msg("invalid GraphML edge attribute")
+		return nil, err
+	}
+
+	edge := NewEdge(gmlEdge.ID, aNode.NodeID(), bNode.NodeID())
+	edge.SetLinkConfig(link)
+
 	log.Log.Trace().Str("id", gmlEdge.ID).Msg("parsed GraphML edge")
-	return NewEdge(gmlEdge.ID, aNode.NodeID(), bNode.NodeID()), nil
+	return edge, nil
+}
+
+// Reads the edge's link attributes from its data, falling back to the attribute keys' default values.
+func parseGraphMLLinkConfig(keys map[string]*graphml.Key, data []*graphml.Data) (domain.LinkConfig, error) {
+	attrs := graphMLAttributes(keys, data)
+
+	var link domain.LinkConfig
+	for attr, dst := range map[string]*int{latencyAttr: &link.Latency, creditLatencyAttr: &link.CreditLatency, bufferSizeAttr: &link.BufferSize} {
+		if err := parseGraphMLPositiveInt(attrs, attr, dst); err != nil {
+			return domain.LinkConfig{}, err
+		}
+	}
+
+	if str, exists := attrs[bandwidthAttr]; exists {
+		val, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		if err != nil || !domain.ValidBandwidth(val) {
+			return domain.LinkConfig{}, errors.Join(domain.ErrInvalidTopology, fmt.Errorf("%s must be a whole number of flits per cycle or of cycles per flit, got %q", bandwidthAttr, str))
+		}
+		link.Bandwidth = val
+	}
+
+	return link, nil
 }
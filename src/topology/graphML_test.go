@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"testing"
 
+	"main/src/domain"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goGraphML/graphml"
@@ -46,7 +48,7 @@ func TestGraphMLNodes(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
 		graph, nodes, _ := testGraphmlGraph(t)
 
-		gotNodes, err := graphMLNodes(graph.Nodes)
+		gotNodes, _, err := graphMLNodes(nil, graph.Nodes)
 		require.NoError(t, err)
 
 		assert.Len(t, gotNodes, len(nodes))
@@ -64,7 +66,7 @@ func TestGraphMLEdges(t *testing.T) {
 			nodeMap[nodes[i].NodeID()] = nodes[i]
 		}
 
-		gotEdges, err := graphMLEdges(nodeMap, graph.Edges)
+		gotEdges, err := graphMLEdges(nodeMap, nil, graph.Edges)
 		require.NoError(t, err)
 
 		assert.Len(t, gotEdges, len(edges))
@@ -82,8 +84,132 @@ func TestGraphMLToEdge(t *testing.T) {
 			nodeMap[nodes[i].NodeID()] = nodes[i]
 		}
 
-		edge, err := parseGraphMLEdge(nodeMap, graph.Edges[0])
+		edge, err := parseGraphMLEdge(nodeMap, nil, graph.Edges[0])
 		require.NoError(t, err)
 		assert.Equal(t, edges[0], edge)
 	})
 }
+
+func TestGraphMLLinkConfig(t *testing.T) {
+	t.Parallel()
+
+	decode := func(t *testing.T, edgeData string) *graphml.GraphML {
+		graphmlStr := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+	<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="d0" for="edge" attr.name="latency" attr.type="int"/>
+	<key id="d1" for="edge" attr.name="credit_latency" attr.type="int"><default>2</default></key>
+	<key id="d2" for="edge" attr.name="bandwidth" attr.type="double"/>
+	<graph id="G" edgedefault="undirected">
+		<node id="nA"/>
+		<node id="nB"/>
+		<edge id="e" source="nA" target="nB">%s</edge>
+	</graph>
+	</graphml>`, edgeData)
+
+		gml := graphml.NewGraphML("topology")
+		require.NoError(t, gml.Decode(bytes.NewReader([]byte(graphmlStr))))
+		return gml
+	}
+
+	nodeMap := map[string]*Node{"nA": NewNode("nA"), "nB": NewNode("nB")}
+
+	t.Run("Valid", func(t *testing.T) {
+		gml := decode(t, `<data key="d0">4</data><data key="d2">0.5</data>`)
+
+		edge, err := parseGraphMLEdge(nodeMap, graphMLKeys(gml.Keys, graphml.KeyForEdge), gml.Graphs[0].Edges[0])
+		require.NoError(t, err)
+		assert.Equal(t, domain.LinkConfig{Latency: 4, CreditLatency: 2, Bandwidth: 0.5}, edge.LinkConfig())
+	})
+
+	t.Run("InvalidLatency", func(t *testing.T) {
+		gml := decode(t, `<data key="d0">0</data>`)
+
+		_, err := parseGraphMLEdge(nodeMap, graphMLKeys(gml.Keys, graphml.KeyForEdge), gml.Graphs[0].Edges[0])
+		assert.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+
+	t.Run("InvalidBandwidth", func(t *testing.T) {
+		gml := decode(t, `<data key="d2">1.5</data>`)
+
+		_, err := parseGraphMLEdge(nodeMap, graphMLKeys(gml.Keys, graphml.KeyForEdge), gml.Graphs[0].Edges[0])
+		assert.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+}
+
+func TestGraphMLNodeConfig(t *testing.T) {
+	t.Parallel()
+
+	decode := func(t *testing.T, nodeData string) *graphml.GraphML {
+		graphmlStr := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+	<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="d0" for="node" attr.name="buffer_size" attr.type="int"/>
+	<key id="d1" for="node" attr.name="processing_delay" attr.type="int"/>
+	<key id="d2" for="node" attr.name="local_ports" attr.type="int"/>
+	<key id="d3" for="node" attr.name="role" attr.type="string"/>
+	<key id="d4" for="node" attr.name="ejection_rate" attr.type="double"/>
+	<key id="d5" for="node" attr.name="ejection_service_time" attr.type="int"/>
+	<key id="d6" for="node" attr.name="ejection_buffer_size" attr.type="int"/>
+	<graph id="G" edgedefault="undirected">
+		<node id="nA">%s</node>
+		<node id="nB"/>
+	</graph>
+	</graphml>`, nodeData)
+
+		gml := graphml.NewGraphML("topology")
+		require.NoError(t, gml.Decode(bytes.NewReader([]byte(graphmlStr))))
+		return gml
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		gml := decode(t, `<data key="d0">8</data><data key="d1">3</data><data key="d2">4</data>`)
+
+		nodes, nodeConfigs, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), gml.Graphs[0].Nodes)
+		require.NoError(t, err)
+		assert.Len(t, nodes, 2)
+		assert.Equal(t, map[string]domain.NodeConfig{"nA": {BufferSize: 8, ProcessingDelay: 3, LocalPorts: 4}}, nodeConfigs)
+	})
+
+	t.Run("Role", func(t *testing.T) {
+		gml := decode(t, `<data key="d3">router</data>`)
+
+		_, nodeConfigs, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), gml.Graphs[0].Nodes)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]domain.NodeConfig{"nA": {Role: domain.RouterNodeRole}}, nodeConfigs)
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		gml := decode(t, `<data key="d3">switch</data>`)
+
+		_, _, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), gml.Graphs[0].Nodes)
+		assert.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+
+	t.Run("Ejection", func(t *testing.T) {
+		gml := decode(t, `<data key="d4">0.25</data><data key="d5">20</data><data key="d6">4</data>`)
+
+		_, nodeConfigs, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), gml.Graphs[0].Nodes)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]domain.NodeConfig{"nA": {EjectionRate: 0.25, EjectionServiceTime: 20, EjectionBufferSize: 4}}, nodeConfigs)
+	})
+
+	t.Run("InvalidEjectionRate", func(t *testing.T) {
+		gml := decode(t, `<data key="d4">0.3</data>`)
+
+		_, _, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), gml.Graphs[0].Nodes)
+		assert.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+
+	t.Run("InvalidLocalPorts", func(t *testing.T) {
+		gml := decode(t, `<data key="d2">0</data>`)
+
+		_, _, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), gml.Graphs[0].Nodes)
+		assert.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		gml := decode(t, `<data key="d1">-1</data>`)
+
+		_, _, err := graphMLNodes(graphMLKeys(gml.Keys, graphml.KeyForNode), gml.Graphs[0].Nodes)
+		assert.ErrorIs(t, err, domain.ErrInvalidTopology)
+	})
+}
@@ -1,6 +1,8 @@
 package topology
 
 import (
+	"errors"
+	"fmt"
 	"path/filepath"
 
 	"main/log"
@@ -10,6 +12,9 @@ import (
 type Topology struct {
 	nodes map[string]*Node
 	edges map[string]*Edge
+
+	// Per router configuration by node ID, nodes without an entry use the global configuration.
+	nodeConfigs map[string]domain.NodeConfig
 }
 
 type Node string
@@ -18,6 +23,9 @@ type Edge struct {
 	id string
 	a  string
 	b  string
+
+	// Configuration of the links in both directions between a and b, zero values are unset.
+	link domain.LinkConfig
 }
 
 func ReadTopology(fPath string) (*Topology, error) {
@@ -45,8 +53,9 @@ func ReadTopology(fPath string) (*Topology, error) {
 
 func NewTopology(nodes map[string]*Node, edges map[string]*Edge) *Topology {
 	top := &Topology{
-		nodes: nodes,
-		edges: edges,
+		nodes:       nodes,
+		edges:       edges,
+		nodeConfigs: make(map[string]domain.NodeConfig),
 	}
 
 	return top
@@ -70,24 +79,109 @@ func (t *Topology) Edge(id string) (*Edge, bool) {
 	return edge, ok
 }
 
+// Returns the routers along the route between its first and last nodes, which must have network interfaces. Endpoint
+// nodes may only start or end the route and are replaced by the router they are attached to, unless the route already
+// passes through it.
 func (t *Topology) Route(nodes []string) (domain.Route, error) {
 	if len(nodes) < 2 {
 		return nil, domain.ErrInvalidRoute
 	}
 
+	for _, i := range []int{0, len(nodes) - 1} {
+		if !t.NodeConfig(nodes[i]).HasNetworkInterface() {
+			return nil, errors.Join(domain.ErrInvalidRoute, fmt.Errorf("route endpoint %s has no network interface", nodes[i]))
+		}
+	}
+
 	route := make(domain.Route, len(nodes))
 	for i := 0; i < len(nodes); i++ {
 		nodeID, exists := t.Node(nodes[i])
 		if !exists {
 			return nil, domain.ErrInvalidRoute
 		}
-
 		route[i] = nodeID.NodeID()
+
+		if t.NodeConfig(route[i]).HasRouter() {
+			continue
+		}
+		if i != 0 && i != len(nodes)-1 {
+			return nil, errors.Join(domain.ErrInvalidRoute, fmt.Errorf("endpoint %s within route", route[i]))
+		}
+
+		router, exists := t.AttachedRouter(route[i])
+		if !exists {
+			return nil, errors.Join(domain.ErrInvalidRoute, fmt.Errorf("endpoint %s not attached to a router", route[i]))
+		}
+		route[i] = router
+	}
+
+	// A route between network interfaces of the same router keeps both of its entries for the router.
+	if !t.NodeConfig(nodes[0]).HasRouter() && len(route) > 2 && route[0] == route[1] {
+		route = route[1:]
+	}
+	if !t.NodeConfig(nodes[len(nodes)-1]).HasRouter() && len(route) > 2 && route[len(route)-1] == route[len(route)-2] {
+		route = route[:len(route)-1]
 	}
 
 	return route, nil
 }
 
+// Returns the router the endpoint node is attached to, the other node of the endpoint's only edge.
+func (t *Topology) AttachedRouter(endpoint string) (string, bool) {
+	routers := t.neighbours(endpoint)
+	if len(routers) != 1 || !t.NodeConfig(routers[0]).HasRouter() {
+		return "", false
+	}
+	return routers[0], true
+}
+
+// Returns the nodes sharing an edge with the node.
+func (t *Topology) neighbours(id string) []string {
+	neighbours := make([]string, 0)
+	for _, edge := range t.edges {
+		if edge.a == id {
+			neighbours = append(neighbours, edge.b)
+		} else if edge.b == id {
+			neighbours = append(neighbours, edge.a)
+		}
+	}
+	return neighbours
+}
+
+// Checks every endpoint node shares a single edge, with a router.
+func (t *Topology) validateRoles() error {
+	for id := range t.nodes {
+		if t.NodeConfig(id).HasRouter() {
+			continue
+		}
+
+		if _, exists := t.AttachedRouter(id); !exists {
+			return errors.Join(domain.ErrInvalidTopology, fmt.Errorf("endpoint %s must share a single edge with a router, shares edges with %v", id, t.neighbours(id)))
+		}
+	}
+
+	return nil
+}
+
+// Returns the node's router configuration, unset if the node has none.
+func (t *Topology) NodeConfig(id string) domain.NodeConfig {
+	return t.nodeConfigs[id]
+}
+
+func (t *Topology) SetNodeConfig(id string, conf domain.NodeConfig) {
+	t.nodeConfigs[id] = conf
+}
+
+// Returns the configuration of the links between the two nodes, unset if no edge connects them.
+func (t *Topology) LinkConfig(a, b string) domain.LinkConfig {
+	for _, edge := range t.edges {
+		if (edge.a == a && edge.b == b) || (edge.a == b && edge.b == a) {
+			return edge.link
+		}
+	}
+	return domain.LinkConfig{}
+}
+
 func NewNode(id string) *Node {
 	log.Log.Trace().Str("id", id).Msg("new node")
 
@@ -120,3 +214,11 @@ func (e *Edge) A() string {
 func (e *Edge) B() string {
 	return e.b
 }
+
+func (e *Edge) LinkConfig() domain.LinkConfig {
+	return e.link
+}
+
+func (e *Edge) SetLinkConfig(link domain.LinkConfig) {
+	e.link = link
+}
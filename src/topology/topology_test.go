@@ -3,7 +3,10 @@ package topology
 import (
 	"testing"
 
+	"main/src/domain"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTop(t *testing.T) {
@@ -110,3 +113,70 @@ func TestEdgeTarget(t *testing.T) {
 	edge := NewEdge("", "n1", target)
 	assert.Equal(t, target, edge.B())
 }
+
+func TestTopologyRoute(t *testing.T) {
+	t.Parallel()
+
+	nodes := map[string]*Node{
+		"r1": NewNode("r1"),
+		"r2": NewNode("r2"),
+		"e1": NewNode("e1"),
+		"e2": NewNode("e2"),
+		"e3": NewNode("e3"),
+	}
+	edges := map[string]*Edge{
+		"1": NewEdge("1", "r1", "r2"),
+		"2": NewEdge("2", "e1", "r1"),
+		"3": NewEdge("3", "r1", "e2"),
+		"4": NewEdge("4", "e3", "r2"),
+	}
+
+	top := NewTopology(nodes, edges)
+	top.SetNodeConfig("r1", domain.NodeConfig{Role: domain.RouterNodeRole})
+	for _, id := range []string{"e1", "e2", "e3"} {
+		top.SetNodeConfig(id, domain.NodeConfig{Role: domain.EndpointNodeRole})
+	}
+	require.NoError(t, top.validateRoles())
+
+	for _, tc := range []struct {
+		nodes    []string
+		expected domain.Route
+	}{
+		{nodes: []string{"e1", "r1", "r2", "e3"}, expected: domain.Route{"r1", "r2"}},
+		{nodes: []string{"e1", "r2"}, expected: domain.Route{"r1", "r2"}},
+		{nodes: []string{"e1", "e2"}, expected: domain.Route{"r1", "r1"}},
+		{nodes: []string{"r2", "r2"}, expected: domain.Route{"r2", "r2"}},
+	} {
+		route, err := top.Route(tc.nodes)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, route)
+	}
+
+	for _, nodes := range [][]string{
+		{"r1", "r2"},
+		{"e1", "r1"},
+		{"r2", "e1", "r2"},
+		{"e1", "n1"},
+	} {
+		_, err := top.Route(nodes)
+		assert.ErrorIs(t, err, domain.ErrInvalidRoute, nodes)
+	}
+}
+
+func TestTopologyValidateRoles(t *testing.T) {
+	t.Parallel()
+
+	nodes := map[string]*Node{
+		"r1": NewNode("r1"),
+		"r2": NewNode("r2"),
+		"e1": NewNode("e1"),
+	}
+	edges := map[string]*Edge{
+		"1": NewEdge("1", "e1", "r1"),
+		"2": NewEdge("2", "e1", "r2"),
+	}
+
+	top := NewTopology(nodes, edges)
+	top.SetNodeConfig("e1", domain.NodeConfig{Role: domain.EndpointNodeRole})
+	assert.ErrorIs(t, top.validateRoles(), domain.ErrInvalidTopology)
+}
@@ -0,0 +1,81 @@
+package traffic
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"main/log"
+	"main/src/domain"
+
+	csvtag "github.com/artonge/go-csv-tag/v2"
+)
+
+func LoadModeConfig(fPath string, tfConfs []domain.TrafficFlowConfig) ([]domain.ModeConfig, error) {
+	var modes []domain.ModeConfig
+	var err error
+
+	log.Log.Debug().Msg("reading modes file")
+
+	switch filepath.Ext(fPath) {
+	case ".csv":
+		err = csvtag.LoadFromPath(fPath, &modes)
+		log.Log.Debug().Msg("read .csv modes file")
+
+	default:
+		log.Log.Error().Err(domain.ErrInvalidFilepath).Str("ext", filepath.Ext(fPath)).Msg("invalid modes file extension")
+		return nil, domain.ErrInvalidFilepath
+	}
+
+	if err != nil {
+		log.Log.Error().Err(err).Str("path", fPath).Msg("error loading modes from file")
+		return nil, err
+	}
+
+	if err := ValidateModes(modes, tfConfs); err != nil {
+		log.Log.Error().Err(err).Str("path", fPath).Msg("invalid modes file")
+		return nil, err
+	}
+
+	return modes, nil
+}
+
+// Checks the modes are ordered by strictly increasing start cycle, beginning at cycle 0, have unique IDs and only list
+// configured traffic flows.
+func ValidateModes(modes []domain.ModeConfig, tfConfs []domain.TrafficFlowConfig) error {
+	if len(modes) == 0 {
+		return errors.Join(domain.ErrInvalidMode, errors.New("no modes"))
+	}
+	if modes[0].StartCycle != 0 {
+		return errors.Join(domain.ErrInvalidMode, fmt.Errorf("first mode %s must start at cycle 0", modes[0].ID))
+	}
+
+	tfIDs := make(map[string]bool, len(tfConfs))
+	for i := 0; i < len(tfConfs); i++ {
+		tfIDs[tfConfs[i].ID] = true
+	}
+
+	modeIDs := make(map[string]bool, len(modes))
+	for i := 0; i < len(modes); i++ {
+		if modes[i].ID == "" || modeIDs[modes[i].ID] {
+			return errors.Join(domain.ErrInvalidMode, fmt.Errorf("mode %d has an empty or duplicate id %q", i+1, modes[i].ID))
+		}
+		modeIDs[modes[i].ID] = true
+
+		if i > 0 && modes[i].StartCycle <= modes[i-1].StartCycle {
+			return errors.Join(domain.ErrInvalidMode, fmt.Errorf("mode %s must start after mode %s", modes[i].ID, modes[i-1].ID))
+		}
+
+		ids, err := modes[i].TrafficFlowIDs()
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if !tfIDs[id] {
+				return errors.Join(domain.ErrInvalidMode, domain.ErrMissingTrafficFlow, fmt.Errorf("mode %s traffic flow %s", modes[i].ID, id))
+			}
+		}
+	}
+
+	return nil
+}
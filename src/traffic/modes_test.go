@@ -0,0 +1,61 @@
+package traffic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"main/src/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadModeConfig(t *testing.T) {
+	t.Parallel()
+
+	tfConfs := []domain.TrafficFlowConfig{{ID: "t1"}, {ID: "t2"}, {ID: "t3"}}
+
+	writeModes := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "modes.csv")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		path := writeModes(t, "id,start_cycle,traffic_flows\n"+
+			"cruise,0,\"[t1,t2]\"\n"+
+			"landing,500,\"[t2, t3]\"\n")
+
+		modes, err := LoadModeConfig(path, tfConfs)
+		require.NoError(t, err)
+		require.Len(t, modes, 2)
+		assert.Equal(t, domain.ModeConfig{ID: "cruise", StartCycle: 0, TrafficFlows: "[t1,t2]"}, modes[0])
+
+		ids, err := modes[1].TrafficFlowIDs()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t2", "t3"}, ids)
+	})
+
+	t.Run("InvalidExtension", func(t *testing.T) {
+		_, err := LoadModeConfig("modes.yaml", tfConfs)
+		assert.ErrorIs(t, err, domain.ErrInvalidFilepath)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := map[string]string{
+			"NotStartingAtZero": "cruise,10,\"[t1]\"\n",
+			"DuplicateID":       "cruise,0,\"[t1]\"\ncruise,10,\"[t2]\"\n",
+			"UnorderedStart":    "cruise,0,\"[t1]\"\nlanding,20,\"[t2]\"\ntaxi,20,\"[t3]\"\n",
+			"UnknownFlow":       "cruise,0,\"[t1,t4]\"\n",
+			"NoFlows":           "cruise,0,[]\n",
+		}
+
+		for name, rows := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := LoadModeConfig(writeModes(t, "id,start_cycle,traffic_flows\n"+rows), tfConfs)
+				assert.ErrorIs(t, err, domain.ErrInvalidMode)
+			})
+		}
+	})
+}
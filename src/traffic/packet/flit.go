@@ -26,12 +26,33 @@ const (
 	FlitCreated     FlitEvent = "flit created"
 	FlitTransmitted FlitEvent = "flit transmitted"
 	FlitArrived     FlitEvent = "flit arrived"
+	FlitBuffered    FlitEvent = "flit buffered"
+	FlitForwarded   FlitEvent = "flit forwarded"
 )
 
 func (e FlitEvent) String() string {
 	return string(e)
 }
 
+type FlitEventRecord struct {
+	Cycle    int
+	Event    FlitEvent
+	Location string
+}
+
+// Chronologically ordered events experienced by a flit.
+type FlitEvents []FlitEventRecord
+
+// Returns the first occurrence of the event, restricted to the location unless the location is empty.
+func (e FlitEvents) First(event FlitEvent, location string) (FlitEventRecord, bool) {
+	for i := 0; i < len(e); i++ {
+		if e[i].Event == event && (location == "" || e[i].Location == location) {
+			return e[i], true
+		}
+	}
+	return FlitEventRecord{}, false
+}
+
 type Flit interface {
 	ID() string
 	Type() FlitType
@@ -41,6 +62,9 @@ type Flit interface {
 	FlitIndex() int
 	Priority() int
 	RecordEvent(cycle int, event FlitEvent, location string)
+	Events() FlitEvents
+	Corrupt()
+	Corrupted() bool
 }
 
 type HeaderFlit interface {
@@ -52,8 +76,18 @@ type HeaderFlit interface {
 	FlitIndex() int
 	Priority() int
 	Deadline() int
+	// Cycle the header flit's packet's release period started, its deadline is relative to.
+	GenerationCycle() int
+	// Number of flits in the header flit's packet, 0 if unknown.
+	PacketSize() int
 	Route() domain.Route
+	// IDs of the network interfaces the header flit's packet is injected at and ejected to.
+	Source() string
+	Destination() string
 	RecordEvent(cycle int, event FlitEvent, location string)
+	Events() FlitEvents
+	Corrupt()
+	Corrupted() bool
 }
 
 type headerFlit struct {
@@ -64,7 +98,13 @@ type headerFlit struct {
 	flitIndex     int
 	priority      int
 	deadline      int
+	generation    int
+	packetSize    int
 	route         domain.Route
+	source        string
+	destination   string
+	events        FlitEvents
+	corrupted     bool
 	logger        zerolog.Logger
 }
 
@@ -77,6 +117,9 @@ type BodyFlit interface {
 	FlitIndex() int
 	Priority() int
 	RecordEvent(cycle int, event FlitEvent, location string)
+	Events() FlitEvents
+	Corrupt()
+	Corrupted() bool
 }
 
 type bodyFlit struct {
@@ -86,6 +129,7 @@ type bodyFlit struct {
 	packetIndex   string
 	flitIndex     int
 	priority      int
+	corrupted     bool
 	logger        zerolog.Logger
 }
 
@@ -98,6 +142,9 @@ type TailFlit interface {
 	FlitIndex() int
 	Priority() int
 	RecordEvent(cycle int, event FlitEvent, location string)
+	Events() FlitEvents
+	Corrupt()
+	Corrupted() bool
 }
 
 type tailFlit struct {
@@ -107,6 +154,8 @@ type tailFlit struct {
 	packetIndex   string
 	flitIndex     int
 	priority      int
+	events        FlitEvents
+	corrupted     bool
 	logger        zerolog.Logger
 }
 
@@ -199,14 +248,50 @@ func (f *headerFlit) Deadline() int {
 	return f.deadline
 }
 
+func (f *headerFlit) GenerationCycle() int {
+	return f.generation
+}
+
+// Sets the cycle the header flit's packet's release period started.
+func (f *headerFlit) SetGenerationCycle(cycle int) {
+	f.generation = cycle
+}
+
+func (f *headerFlit) PacketSize() int {
+	return f.packetSize
+}
+
 func (f *headerFlit) Route() domain.Route {
 	return f.route
 }
 
+// Returns the ID of the network interface the packet is injected at, the first router's first network interface if
+// unset.
+func (f *headerFlit) Source() string {
+	if f.source == "" && len(f.route) > 0 {
+		return f.route[0]
+	}
+	return f.source
+}
+
+// Returns the ID of the network interface the packet is ejected to, the last router's first network interface if
+// unset.
+func (f *headerFlit) Destination() string {
+	if f.destination == "" && len(f.route) > 0 {
+		return f.route[len(f.route)-1]
+	}
+	return f.destination
+}
+
 func (f *headerFlit) RecordEvent(cycle int, event FlitEvent, location string) {
+	f.events = append(f.events, FlitEventRecord{Cycle: cycle, Event: event, Location: location})
 	recordEvent(&f.logger, f, cycle, event, location)
 }
 
+func (f *headerFlit) Events() FlitEvents {
+	return f.events
+}
+
 func (f *bodyFlit) ID() string {
 	return f.id
 }
@@ -239,6 +324,11 @@ func (f *bodyFlit) RecordEvent(cycle int, event FlitEvent, location string) {
 	recordEvent(&f.logger, f, cycle, event, location)
 }
 
+// Body flit events are only logged, not stored, to bound the memory used by long packets.
+func (f *bodyFlit) Events() FlitEvents {
+	return nil
+}
+
 func (f *tailFlit) ID() string {
 	return f.id
 }
@@ -268,9 +358,38 @@ func (f *tailFlit) Priority() int {
 }
 
 func (f *tailFlit) RecordEvent(cycle int, event FlitEvent, location string) {
+	f.events = append(f.events, FlitEventRecord{Cycle: cycle, Event: event, Location: location})
 	recordEvent(&f.logger, f, cycle, event, location)
 }
 
+func (f *tailFlit) Events() FlitEvents {
+	return f.events
+}
+
+func (f *headerFlit) Corrupt() {
+	f.corrupted = true
+}
+
+func (f *headerFlit) Corrupted() bool {
+	return f.corrupted
+}
+
+func (f *bodyFlit) Corrupt() {
+	f.corrupted = true
+}
+
+func (f *bodyFlit) Corrupted() bool {
+	return f.corrupted
+}
+
+func (f *tailFlit) Corrupt() {
+	f.corrupted = true
+}
+
+func (f *tailFlit) Corrupted() bool {
+	return f.corrupted
+}
+
 func recordEvent(logger *zerolog.Logger, f Flit, cycle int, event FlitEvent, location string) {
 	logger.Trace().Int("cycle", cycle).Str("flit", f.ID()).Str("event", event.String()).Str("location", location).Msgf("%s at %s", event.String(), location)
 }
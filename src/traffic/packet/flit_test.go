@@ -266,3 +266,66 @@ func TestTailFlitPriority(t *testing.T) {
 	priority := 1
 	assert.Equal(t, priority, NewTailFlit("t", "AABBCCDD", 2, 1, zerolog.New(io.Discard)).Priority())
 }
+
+func TestFlitRecordEvent(t *testing.T) {
+	t.Parallel()
+
+	_, _, route := testDummyRoute()
+
+	t.Run("Header", func(t *testing.T) {
+		flit := NewHeaderFlit("t", "AABBCCDD", 0, 1, 100, route, zerolog.New(io.Discard))
+		flit.RecordEvent(3, FlitTransmitted, "n1")
+		flit.RecordEvent(7, FlitArrived, "n2")
+
+		assert.Equal(t, FlitEvents{
+			{Cycle: 3, Event: FlitTransmitted, Location: "n1"},
+			{Cycle: 7, Event: FlitArrived, Location: "n2"},
+		}, flit.Events())
+	})
+
+	t.Run("Body", func(t *testing.T) {
+		flit := NewBodyFlit("t", "AABBCCDD", 1, 1, zerolog.New(io.Discard))
+		flit.RecordEvent(3, FlitTransmitted, "n1")
+
+		assert.Empty(t, flit.Events())
+	})
+
+	t.Run("Tail", func(t *testing.T) {
+		flit := NewTailFlit("t", "AABBCCDD", 2, 1, zerolog.New(io.Discard))
+		flit.RecordEvent(9, FlitArrived, "n2")
+
+		assert.Equal(t, FlitEvents{{Cycle: 9, Event: FlitArrived, Location: "n2"}}, flit.Events())
+	})
+}
+
+func TestFlitEventsFirst(t *testing.T) {
+	t.Parallel()
+
+	events := FlitEvents{
+		{Cycle: 1, Event: FlitCreated, Location: "n1"},
+		{Cycle: 4, Event: FlitTransmitted, Location: "n1"},
+		{Cycle: 9, Event: FlitArrived, Location: "n3"},
+	}
+
+	t.Run("AnyLocation", func(t *testing.T) {
+		event, exists := events.First(FlitTransmitted, "")
+		assert.True(t, exists)
+		assert.Equal(t, 4, event.Cycle)
+	})
+
+	t.Run("Location", func(t *testing.T) {
+		event, exists := events.First(FlitArrived, "n3")
+		assert.True(t, exists)
+		assert.Equal(t, 9, event.Cycle)
+	})
+
+	t.Run("WrongLocation", func(t *testing.T) {
+		_, exists := events.First(FlitArrived, "n1")
+		assert.False(t, exists)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, exists := FlitEvents{}.First(FlitArrived, "")
+		assert.False(t, exists)
+	})
+}
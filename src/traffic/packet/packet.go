@@ -24,7 +24,13 @@ type Packet interface {
 	PacketIndex() string
 	Priority() int
 	Deadline() int
+	// Cycle the packet's release period started, its deadline is relative to.
+	GenerationCycle() int
+	SetGenerationCycle(cycle int)
 	Route() domain.Route
+	// IDs of the network interfaces the packet is injected at and ejected to.
+	Source() string
+	Destination() string
 	PacketSize() int
 	Flits() []Flit
 }
@@ -35,8 +41,12 @@ type packet struct {
 	packetIndex   string
 	priority      int
 	deadline      int
+	generation    int
 	route         domain.Route
+	source        string
+	destination   string
 	packetSize    int
+	flits         []Flit
 
 	logger zerolog.Logger
 }
@@ -82,18 +92,62 @@ func (p *packet) Deadline() int {
 	return p.deadline
 }
 
+func (p *packet) GenerationCycle() int {
+	return p.generation
+}
+
+// Sets the cycle the packet's release period started, before the packet is split into flits.
+func (p *packet) SetGenerationCycle(cycle int) {
+	p.generation = cycle
+}
+
 func (p *packet) Route() domain.Route {
 	return p.route
 }
 
+// Returns the ID of the network interface the packet is injected at, the first router's first network interface unless
+// set by SetEndpoints.
+func (p *packet) Source() string {
+	if p.source == "" && len(p.route) > 0 {
+		return p.route[0]
+	}
+	return p.source
+}
+
+// Returns the ID of the network interface the packet is ejected to, the last router's first network interface unless
+// set by SetEndpoints.
+func (p *packet) Destination() string {
+	if p.destination == "" && len(p.route) > 0 {
+		return p.route[len(p.route)-1]
+	}
+	return p.destination
+}
+
+// Sets the IDs of the network interfaces the packet is injected at and ejected to, before the packet is split into
+// flits.
+func (p *packet) SetEndpoints(source, destination string) {
+	p.source = source
+	p.destination = destination
+}
+
 func (p *packet) PacketSize() int {
 	return p.packetSize
 }
 
+// Splits the packet into flits on the first call, subsequent calls return the same flits.
 func (p *packet) Flits() []Flit {
+	if p.flits != nil {
+		return p.flits
+	}
+
 	flits := make([]Flit, p.packetSize)
 
-	flits[0] = NewHeaderFlit(p.TrafficFlowID(), p.PacketIndex(), 0, p.priority, p.deadline, p.route, p.logger)
+	header := NewHeaderFlit(p.TrafficFlowID(), p.PacketIndex(), 0, p.priority, p.deadline, p.route, p.logger)
+	header.packetSize = p.packetSize
+	header.generation = p.generation
+	header.source = p.Source()
+	header.destination = p.Destination()
+	flits[0] = header
 
 	bodyFlits := p.bodyFlits()
 	for i := 0; i < len(bodyFlits); i++ {
@@ -102,7 +156,8 @@ func (p *packet) Flits() []Flit {
 
 	flits[len(flits)-1] = NewTailFlit(p.TrafficFlowID(), p.PacketIndex(), len(flits)-1, p.priority, p.logger)
 
-	return flits
+	p.flits = flits
+	return p.flits
 }
 
 func (p *packet) bodyFlits() []BodyFlit {
@@ -151,6 +206,10 @@ func EqualPackets(pkt1, pkt2 Packet) error {
 		}
 	}
 
+	if pkt1.Source() != pkt2.Source() || pkt1.Destination() != pkt2.Destination() {
+		return errors.Join(domain.ErrPacketsNotEqual, fmt.Errorf("Endpoints: %s -> %s != %s -> %s", pkt1.Source(), pkt1.Destination(), pkt2.Source(), pkt2.Destination()))
+	}
+
 	if pkt1.PacketSize() != pkt2.PacketSize() {
 		return errors.Join(domain.ErrPacketsNotEqual, errors.New("Packet Size"))
 	}
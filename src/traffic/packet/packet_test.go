@@ -73,6 +73,40 @@ func TestPacketRoute(t *testing.T) {
 	assert.Equal(t, route, packet.Route())
 }
 
+func TestPacketEndpoints(t *testing.T) {
+	t.Parallel()
+
+	route := domain.Route{"n1", "n2"}
+
+	packet := NewPacket("t", "AA", 1, 100, route, 4, zerolog.New(io.Discard))
+	assert.Equal(t, "n1", packet.Source())
+	assert.Equal(t, "n2", packet.Destination())
+
+	packet = NewPacket("t", "AA", 1, 100, route, 4, zerolog.New(io.Discard))
+	packet.SetEndpoints("n1:2", "n2:1")
+	assert.Equal(t, "n1:2", packet.Source())
+	assert.Equal(t, "n2:1", packet.Destination())
+
+	header, ok := packet.Flits()[0].(HeaderFlit)
+	require.True(t, ok)
+	assert.Equal(t, "n1:2", header.Source())
+	assert.Equal(t, "n2:1", header.Destination())
+}
+
+func TestPacketGenerationCycle(t *testing.T) {
+	t.Parallel()
+
+	packet := NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 4, zerolog.New(io.Discard))
+	assert.Equal(t, 0, packet.GenerationCycle())
+
+	packet.SetGenerationCycle(40)
+	assert.Equal(t, 40, packet.GenerationCycle())
+
+	header, ok := packet.Flits()[0].(HeaderFlit)
+	require.True(t, ok)
+	assert.Equal(t, 40, header.GenerationCycle())
+}
+
 func TestPacketPacketSize(t *testing.T) {
 	t.Parallel()
 
@@ -189,10 +223,24 @@ func TestPacketFlits(t *testing.T) {
 				assert.Equal(t, testCase.expected[i].Type(), gotFlits[i].Type())
 				assert.Equal(t, testCase.expected[i].PacketIndex(), gotFlits[i].PacketIndex())
 			}
+			assert.Equal(t, testCase.packetSize, gotFlits[0].(HeaderFlit).PacketSize())
 		})
 	}
 }
 
+func TestPacketFlitsMemoised(t *testing.T) {
+	t.Parallel()
+
+	pkt := NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 4, zerolog.New(io.Discard))
+
+	flits := pkt.Flits()
+	gotFlits := pkt.Flits()
+	require.Len(t, gotFlits, len(flits))
+	for i := 0; i < len(flits); i++ {
+		assert.Same(t, flits[i], gotFlits[i])
+	}
+}
+
 func TestPacketBodyFlits(t *testing.T) {
 	t.Parallel()
 
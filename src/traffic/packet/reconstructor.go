@@ -71,6 +71,21 @@ func (r *reconstructor) Reconstruct() (Packet, error) {
 		return nil, domain.ErrFlitUnset
 	}
 
+	// The tail flit's index identifies the packet's size, so any missing body flits can be detected.
+	if len(r.bodyFlits)+2 != r.tailFlit.FlitIndex()+1 {
+		r.logger.Trace().Int("body_flits", len(r.bodyFlits)).Int("tail_index", r.tailFlit.FlitIndex()).Msg("packet missing flits")
+		return nil, domain.ErrIncompletePacket
+	}
+
+	if r.headerFlit.Corrupted() || r.tailFlit.Corrupted() {
+		return nil, domain.ErrCorruptPacket
+	}
+	for i := 0; i < len(r.bodyFlits); i++ {
+		if r.bodyFlits[i].Corrupted() {
+			return nil, domain.ErrCorruptPacket
+		}
+	}
+
 	pkt := NewPacket(
 		r.headerFlit.TrafficFlowID(),
 		r.headerFlit.PacketIndex(),
@@ -83,6 +98,17 @@ func (r *reconstructor) Reconstruct() (Packet, error) {
 		zerolog.New(io.Discard),
 	)
 
+	pkt.SetEndpoints(r.headerFlit.Source(), r.headerFlit.Destination())
+	pkt.SetGenerationCycle(r.headerFlit.GenerationCycle())
+
+	// The reconstructed packet retains the received flits, and their recorded events.
+	pkt.flits = make([]Flit, 0, len(r.bodyFlits)+2)
+	pkt.flits = append(pkt.flits, r.headerFlit)
+	for i := 0; i < len(r.bodyFlits); i++ {
+		pkt.flits = append(pkt.flits, r.bodyFlits[i])
+	}
+	pkt.flits = append(pkt.flits, r.tailFlit)
+
 	r.logger.Trace().Str("packet", pkt.ID()).Msg("reconstructed packet")
 	return pkt, nil
 }
@@ -108,6 +108,7 @@ func TestReconstructorReconstruct(t *testing.T) {
 		var bodySize int = 4
 
 		packet := NewPacket(trafficFlowID, packetID, priority, deadline, route, bodySize, zerolog.New(io.Discard))
+		packet.SetEndpoints(src+":1", dst)
 		flits := packet.Flits()
 
 		headerFlit, ok := flits[0].(HeaderFlit)
@@ -141,4 +142,31 @@ func TestReconstructorReconstruct(t *testing.T) {
 		require.ErrorIs(t, domain.ErrFlitUnset, err)
 		assert.Nil(t, pkt)
 	})
+
+	t.Run("MissingBodyFlit", func(t *testing.T) {
+		flits := NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 4, zerolog.New(io.Discard)).Flits()
+
+		reconstructor, err := NewReconstructor(flits[0].(HeaderFlit), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.NoError(t, reconstructor.AddBody(flits[1].(BodyFlit)))
+		require.NoError(t, reconstructor.SetTail(flits[3].(TailFlit)))
+
+		pkt, err := reconstructor.Reconstruct()
+		require.ErrorIs(t, err, domain.ErrIncompletePacket)
+		assert.Nil(t, pkt)
+	})
+
+	t.Run("CorruptedFlit", func(t *testing.T) {
+		flits := NewPacket("t", "AA", 1, 100, domain.Route{"n1", "n2"}, 3, zerolog.New(io.Discard)).Flits()
+		flits[1].Corrupt()
+
+		reconstructor, err := NewReconstructor(flits[0].(HeaderFlit), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.NoError(t, reconstructor.AddBody(flits[1].(BodyFlit)))
+		require.NoError(t, reconstructor.SetTail(flits[2].(TailFlit)))
+
+		pkt, err := reconstructor.Reconstruct()
+		require.ErrorIs(t, err, domain.ErrCorruptPacket)
+		assert.Nil(t, pkt)
+	})
 }
@@ -8,6 +8,7 @@ import (
 
 	"main/log"
 	"main/src/domain"
+	"main/src/topology"
 	"main/src/traffic/packet"
 
 	csvtag "github.com/artonge/go-csv-tag/v2"
@@ -22,6 +23,9 @@ type TrafficFlow interface {
 	Jitter() int
 	PacketSize() int
 	Route() []string
+	// IDs of the network interfaces the traffic flow's packets are injected at and ejected to.
+	Source() string
+	Destination() string
 	ReleasePacket(cycle int, trafficFlow TrafficFlow, route domain.Route, logger zerolog.Logger) (bool, packet.Packet, int)
 }
 
@@ -34,6 +38,8 @@ type trafficFlowImpl struct {
 	jitter        int
 	packetSize    int
 	route         []string
+	source        string
+	destination   string
 
 	currentPeriod int
 	currentJitter int
@@ -80,6 +86,46 @@ func TrafficFlows(conf domain.SimConfig, tfConfs []domain.TrafficFlowConfig) ([]
 	return trafficFlows, nil
 }
 
+// Checks the virtual channel of every router input buffer along each traffic flow's route, and of its destination
+// network interface's ejection buffer, holds the flow's whole packets under virtual cut-through & store-and-forward
+// switching. A router's buffer size is set by its node, or by the edge feeding the input buffer, and an ejection
+// buffer's size by its node, in place of the configured sizes.
+func ValidateRouteBuffers(conf domain.SimConfig, top *topology.Topology, trafficFlows []TrafficFlow) error {
+	if !conf.WholePacketSwitching() {
+		return nil
+	}
+
+	for _, tf := range trafficFlows {
+		route, err := top.Route(tf.Route())
+		if err != nil {
+			log.Log.Error().Err(err).Str("id", tf.ID()).Strs("route", tf.Route()).Msg("Invalid TrafficFlow route")
+			return err
+		}
+
+		for i, router := range route {
+			bufferSize := conf.ForNode(top.NodeConfig(router)).BufferSize
+			if i > 0 {
+				if link := top.LinkConfig(route[i-1], router); link.BufferSize > 0 {
+					bufferSize = link.BufferSize
+				}
+			}
+
+			if depth := conf.VChanDepth(bufferSize, tf.Priority()); tf.PacketSize() > depth {
+				log.Log.Error().Str("id", tf.ID()).Str("router", router).Int("packet_size", tf.PacketSize()).Int("vc_depth", depth).Str("switching", string(conf.SwitchingMode())).Msg("traffic flow packet size exceeds its virtual channel depth, which must hold whole packets under virtual cut-through & store-and-forward switching")
+				return domain.ErrInvalidConfig
+			}
+		}
+
+		ejectionBuffer := conf.ForNode(top.NodeConfig(tf.Destination())).EjectionBuffer()
+		if depth := conf.VChanDepth(ejectionBuffer, tf.Priority()); tf.PacketSize() > depth {
+			log.Log.Error().Str("id", tf.ID()).Str("destination", tf.Destination()).Int("packet_size", tf.PacketSize()).Int("ejection_vc_depth", depth).Str("switching", string(conf.SwitchingMode())).Msg("traffic flow packet size exceeds its ejection buffer virtual channel depth, which must hold whole packets under virtual cut-through & store-and-forward switching")
+			return domain.ErrInvalidConfig
+		}
+	}
+
+	return nil
+}
+
 func NewTrafficFlow(tfConf domain.TrafficFlowConfig, conf domain.SimConfig) (*trafficFlowImpl, error) {
 	if tfConf.Priority < 1 {
 		log.Log.Error().Err(domain.ErrInvalidConfig).Str("id", tfConf.ID).Int("priority", tfConf.Priority).Msg("Invalid TrafficFlow priority")
@@ -112,12 +158,23 @@ func NewTrafficFlow(tfConf domain.TrafficFlowConfig, conf domain.SimConfig) (*tr
 		return nil, domain.ErrInvalidConfig
 	}
 
+	if conf.InjectionQueueDepth > 0 && tfConf.PacketSize > conf.InjectionQueueDepth {
+		log.Log.Error().Str("id", tfConf.ID).Int("packet_size", tfConf.PacketSize).Int("injection_queue_depth", conf.InjectionQueueDepth).Msg("traffic flow packet size exceeds the injection queue depth, which must hold whole packets")
+		return nil, domain.ErrInvalidConfig
+	}
+
 	route, err := tfConf.RouteArray()
 	if err != nil {
 		log.Log.Error().Err(err).Str("id", tfConf.ID).Str("route", tfConf.Route).Msg("Invalid TrafficFlow route")
 		return nil, err
 	}
 
+	source, destination, err := tfConf.Endpoints()
+	if err != nil {
+		log.Log.Error().Err(err).Str("id", tfConf.ID).Str("route", tfConf.Route).Msg("Invalid TrafficFlow endpoints")
+		return nil, err
+	}
+
 	log.Log.Trace().Str("id", tfConf.ID).Msg("new traffic flow")
 	return &trafficFlowImpl{
 		id:            tfConf.ID,
@@ -127,6 +184,8 @@ func NewTrafficFlow(tfConf domain.TrafficFlowConfig, conf domain.SimConfig) (*tr
 		jitter:        tfConf.Jitter,
 		packetSize:    tfConf.PacketSize,
 		route:         route,
+		source:        source,
+		destination:   destination,
 	}, nil
 }
 
@@ -158,6 +217,14 @@ func (t *trafficFlowImpl) Route() []string {
 	return t.route
 }
 
+func (t *trafficFlowImpl) Source() string {
+	return t.source
+}
+
+func (t *trafficFlowImpl) Destination() string {
+	return t.destination
+}
+
 func (t *trafficFlowImpl) ReleasePacket(cycle int, trafficFlow TrafficFlow, route domain.Route, logger zerolog.Logger) (bool, packet.Packet, int) {
 	if cycle%t.releasePeriod == 0 {
 		t.currentPeriod = cycle
@@ -174,6 +241,7 @@ func (t *trafficFlowImpl) ReleasePacket(cycle int, trafficFlow TrafficFlow, rout
 			trafficFlow.PacketSize(),
 			logger,
 		)
+		pkt.SetEndpoints(trafficFlow.Source(), trafficFlow.Destination())
 
 		t.packetCount++
 
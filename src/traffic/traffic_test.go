@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"main/src/domain"
+	"main/src/topology"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -76,6 +77,30 @@ func TestNewTrafficFlow(t *testing.T) {
 		_, err := NewTrafficFlow(tfConf, conf)
 		require.Error(t, err)
 	})
+
+	t.Run("InjectionQueueDepth", func(t *testing.T) {
+		tfConf := domain.TrafficFlowConfig{
+			ID:         "t1",
+			Priority:   1,
+			Period:     4,
+			Deadline:   2,
+			Jitter:     2,
+			PacketSize: 5,
+			Route:      "[n1,n2,n3]",
+		}
+
+		conf := domain.SimConfig{
+			MaxPriority:         2,
+			InjectionQueueDepth: 4,
+		}
+
+		_, err := NewTrafficFlow(tfConf, conf)
+		require.ErrorIs(t, err, domain.ErrInvalidConfig)
+
+		conf.InjectionQueueDepth = 5
+		_, err = NewTrafficFlow(tfConf, conf)
+		require.NoError(t, err)
+	})
 }
 
 func TestTrafficFlowID(t *testing.T) {
@@ -199,6 +224,40 @@ func TestTrafficFlowRoute(t *testing.T) {
 	assert.Equal(t, route, trafficFlow.Route())
 }
 
+func TestTrafficFlowEndpoints(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		trafficFlow, err := NewTrafficFlow(domain.TrafficFlowConfig{
+			Priority:   1,
+			Period:     75,
+			Deadline:   50,
+			PacketSize: 32,
+			Route:      "[n1:2,n2,n3]",
+		}, dummyConfig())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"n1", "n2", "n3"}, trafficFlow.Route())
+		assert.Equal(t, "n1:2", trafficFlow.Source())
+		assert.Equal(t, "n3", trafficFlow.Destination())
+
+		_, pkt, _ := trafficFlow.ReleasePacket(0, trafficFlow, domain.Route{"n1", "n2", "n3"}, zerolog.New(io.Discard))
+		require.NotNil(t, pkt)
+		assert.Equal(t, "n1:2", pkt.Source())
+		assert.Equal(t, "n3", pkt.Destination())
+	})
+
+	t.Run("InvalidIndex", func(t *testing.T) {
+		_, err := NewTrafficFlow(domain.TrafficFlowConfig{
+			Priority:   1,
+			Period:     75,
+			Deadline:   50,
+			PacketSize: 32,
+			Route:      "[n1,n2:x]",
+		}, dummyConfig())
+		require.ErrorIs(t, err, domain.ErrInvalidRoute)
+	})
+}
+
 func TestTrafficFlowRleasePacket(t *testing.T) {
 	t.Parallel()
 
@@ -232,3 +291,87 @@ func TestTrafficFlowRleasePacket(t *testing.T) {
 		assert.Equal(t, cycle, periodCycle)
 	})
 }
+
+func TestValidateRouteBuffers(t *testing.T) {
+	t.Parallel()
+
+	tfConf := domain.TrafficFlowConfig{
+		ID:         "t1",
+		Priority:   2,
+		Period:     4,
+		Deadline:   2,
+		Jitter:     2,
+		PacketSize: 5,
+		Route:      "[n0,n1,n2]",
+	}
+
+	conf := domain.SimConfig{
+		MaxPriority: 2,
+		BufferSize:  8,
+		Switching:   domain.VirtualCutThroughSwitching,
+	}
+
+	newTrafficFlows := func(t *testing.T) []TrafficFlow {
+		tf, err := NewTrafficFlow(tfConf, domain.SimConfig{MaxPriority: conf.MaxPriority})
+		require.NoError(t, err)
+		return []TrafficFlow{tf}
+	}
+
+	t.Run("ConfiguredBufferSize", func(t *testing.T) {
+		top := topology.ThreeNodeLine(t)
+		require.ErrorIs(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)), domain.ErrInvalidConfig)
+
+		conf := conf
+		conf.VCDepths = []int{3, 5}
+		require.NoError(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)))
+
+		conf.Switching = domain.WormholeSwitching
+		conf.VCDepths = nil
+		require.NoError(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)))
+	})
+
+	t.Run("NodeBufferSize", func(t *testing.T) {
+		top := topology.ThreeNodeLine(t)
+		for _, id := range []string{"n0", "n1", "n2"} {
+			top.SetNodeConfig(id, domain.NodeConfig{BufferSize: 10})
+		}
+		require.NoError(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)))
+
+		top.SetNodeConfig("n1", domain.NodeConfig{BufferSize: 8})
+		require.ErrorIs(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)), domain.ErrInvalidConfig)
+	})
+
+	t.Run("EdgeBufferSize", func(t *testing.T) {
+		top := topology.ThreeNodeLine(t)
+		for _, id := range []string{"n0", "n1", "n2"} {
+			top.SetNodeConfig(id, domain.NodeConfig{BufferSize: 10})
+		}
+		edge, exists := top.Edge("e1")
+		require.True(t, exists)
+		edge.SetLinkConfig(domain.LinkConfig{BufferSize: 8})
+		require.ErrorIs(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)), domain.ErrInvalidConfig)
+
+		// The edge only feeds the input buffers of the routers it connects.
+		edge.SetLinkConfig(domain.LinkConfig{})
+		edge, exists = top.Edge("e0")
+		require.True(t, exists)
+		edge.SetLinkConfig(domain.LinkConfig{BufferSize: 12})
+		require.NoError(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)))
+	})
+
+	t.Run("EjectionBufferSize", func(t *testing.T) {
+		conf := conf
+		conf.BufferSize = 10
+		conf.EjectionBufferSize = 8
+		top := topology.ThreeNodeLine(t)
+		require.ErrorIs(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)), domain.ErrInvalidConfig)
+
+		// Only the destination network interface's ejection buffer holds the flow's packets.
+		top.SetNodeConfig("n2", domain.NodeConfig{EjectionBufferSize: 10})
+		require.NoError(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)))
+
+		conf.EjectionBufferSize = 0
+		top.SetNodeConfig("n2", domain.NodeConfig{EjectionBufferSize: 8})
+		require.ErrorIs(t, ValidateRouteBuffers(conf, top, newTrafficFlows(t)), domain.ErrInvalidConfig)
+	})
+}